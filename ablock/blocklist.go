@@ -489,10 +489,12 @@ func IsOriginBlocked(origin Address) bool {
 	return blocklist[origin]
 }
 
-// MockBlocklist mock the blocklist
-func MockBlocklist(list []string) {
+// MockBlocklist mock the blocklist, returns the function to restore the previous one.
+func MockBlocklist(list []string) (restore func()) {
+	prev := blocklist
 	blocklist = make(map[Address]bool)
 	for _, str := range list {
 		blocklist[MustParseAddress(str)] = true
 	}
+	return func() { blocklist = prev }
 }
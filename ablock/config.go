@@ -0,0 +1,57 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package ablock
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Config is the customizable timing config of a network. It's fixed by the genesis, and passed
+// along with ForkConfig to the components depending on it.
+type Config struct {
+	BlockInterval      uint64 `json:"blockInterval"`      // time interval between two consecutive blocks.
+	CheckpointInterval uint32 `json:"checkpointInterval"` // blocks between two bft checkpoints.
+}
+
+// DefaultConfig is the timing config of the main and test networks.
+var DefaultConfig = Config{
+	BlockInterval:      10,
+	CheckpointInterval: 180,
+}
+
+// WithDefaults returns a copy of the config whose zero fields are filled with defaults.
+func (c Config) WithDefaults() Config {
+	if c.BlockInterval == 0 {
+		c.BlockInterval = DefaultConfig.BlockInterval
+	}
+	if c.CheckpointInterval == 0 {
+		c.CheckpointInterval = DefaultConfig.CheckpointInterval
+	}
+	return c
+}
+
+// Validate checks whether the config is applicable along with the fork config.
+// Zero fields are treated as defaults.
+func (c Config) Validate(fc ForkConfig) error {
+	c = c.WithDefaults()
+	if c.BlockInterval > 3600 {
+		return errors.New("block interval too large")
+	}
+	if c.CheckpointInterval < 3 {
+		return errors.New("checkpoint interval too small")
+	}
+	// bft rounds are counted from the checkpoint of the FINALITY fork
+	if fc.FINALITY != math.MaxUint32 && fc.FINALITY%c.CheckpointInterval != 0 {
+		return fmt.Errorf("FINALITY fork %v not aligned with checkpoint interval %v", fc.FINALITY, c.CheckpointInterval)
+	}
+	return nil
+}
+
+func (c Config) String() string {
+	return fmt.Sprintf("block interval: %vs, checkpoint interval: %v", c.BlockInterval, c.CheckpointInterval)
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package ablock_test
+
+import (
+	"testing"
+
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	assert.Nil(t, ablock.Config{}.Validate(ablock.NoFork))
+	assert.Nil(t, ablock.Config{BlockInterval: 1, CheckpointInterval: 30}.Validate(ablock.NoFork))
+	assert.NotNil(t, ablock.Config{CheckpointInterval: 1}.Validate(ablock.NoFork))
+	assert.NotNil(t, ablock.Config{BlockInterval: 3601}.Validate(ablock.NoFork))
+
+	// FINALITY must be at a checkpoint
+	fc := ablock.NoFork
+	fc.FINALITY = 360
+	assert.Nil(t, ablock.Config{}.Validate(fc))
+	assert.Nil(t, ablock.Config{CheckpointInterval: 60}.Validate(fc))
+	assert.NotNil(t, ablock.Config{CheckpointInterval: 100}.Validate(fc))
+
+	// zero fields fall back to defaults
+	assert.Equal(t, ablock.DefaultConfig, ablock.Config{}.WithDefaults())
+	assert.Equal(t, ablock.Config{BlockInterval: 2, CheckpointInterval: ablock.DefaultConfig.CheckpointInterval}, ablock.Config{BlockInterval: 2}.WithDefaults())
+}
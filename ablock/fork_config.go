@@ -6,8 +6,10 @@
 package ablock
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 )
 
@@ -19,13 +21,23 @@ type ForkConfig struct {
 	ETH_IST   uint32
 	VIP214    uint32
 	FINALITY  uint32
+
+	// Extra forks of custom networks, keyed by fork name.
+	Extra map[string]uint32 `json:",omitempty"`
 }
 
-func (fc ForkConfig) String() string {
-	var strs []string
+// Fork a named fork and the block number it takes effect.
+type Fork struct {
+	Name     string
+	BlockNum uint32
+}
+
+// Forks returns all scheduled forks, built-in ones come first, and then extra ones in name order.
+func (fc ForkConfig) Forks() []Fork {
+	var forks []Fork
 	push := func(name string, blockNum uint32) {
 		if blockNum != math.MaxUint32 {
-			strs = append(strs, fmt.Sprintf("%v: #%v", name, blockNum))
+			forks = append(forks, Fork{name, blockNum})
 		}
 	}
 
@@ -36,6 +48,64 @@ func (fc ForkConfig) String() string {
 	push("VIP214", fc.VIP214)
 	push("FINALITY", fc.FINALITY)
 
+	names := make([]string, 0, len(fc.Extra))
+	for name := range fc.Extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		push(name, fc.Extra[name])
+	}
+	return forks
+}
+
+// BlockNum returns the block number of the named fork.
+// math.MaxUint32 returned if the fork is not scheduled.
+func (fc ForkConfig) BlockNum(name string) uint32 {
+	switch name {
+	case "VIP191":
+		return fc.VIP191
+	case "ETH_CONST":
+		return fc.ETH_CONST
+	case "BLOCKLIST":
+		return fc.BLOCKLIST
+	case "ETH_IST":
+		return fc.ETH_IST
+	case "VIP214":
+		return fc.VIP214
+	case "FINALITY":
+		return fc.FINALITY
+	}
+	if n, ok := fc.Extra[name]; ok {
+		return n
+	}
+	return math.MaxUint32
+}
+
+// IsActive returns whether the named fork is active at the given block number.
+func (fc ForkConfig) IsActive(name string, blockNum uint32) bool {
+	return blockNum >= fc.BlockNum(name)
+}
+
+// Validate checks names of extra forks.
+func (fc ForkConfig) Validate() error {
+	for name := range fc.Extra {
+		if name == "" {
+			return errors.New("empty fork name")
+		}
+		// built-in forks are all scheduled at #0 in an empty config
+		if (ForkConfig{}).BlockNum(name) == 0 {
+			return fmt.Errorf("extra fork %v conflicts with built-in one", name)
+		}
+	}
+	return nil
+}
+
+func (fc ForkConfig) String() string {
+	var strs []string
+	for _, f := range fc.Forks() {
+		strs = append(strs, fmt.Sprintf("%v: #%v", f.Name, f.BlockNum))
+	}
 	return strings.Join(strs, ", ")
 }
 
@@ -0,0 +1,39 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package ablock_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkConfigExtra(t *testing.T) {
+	fc := ablock.NoFork
+	assert.Nil(t, json.Unmarshal([]byte(`{"VIP191": 0, "Extra": {"NEW_EVM": 100, "A_PRECOMPILE": 50}}`), &fc))
+	assert.Nil(t, fc.Validate())
+
+	assert.Equal(t, []ablock.Fork{
+		{Name: "VIP191", BlockNum: 0},
+		{Name: "A_PRECOMPILE", BlockNum: 50},
+		{Name: "NEW_EVM", BlockNum: 100},
+	}, fc.Forks())
+	assert.Equal(t, "VIP191: #0, A_PRECOMPILE: #50, NEW_EVM: #100", fc.String())
+
+	assert.Equal(t, uint32(math.MaxUint32), fc.BlockNum("ETH_IST"))
+	assert.Equal(t, uint32(math.MaxUint32), fc.BlockNum("UNKNOWN"))
+	assert.True(t, fc.IsActive("VIP191", 0))
+	assert.False(t, fc.IsActive("NEW_EVM", 99))
+	assert.True(t, fc.IsActive("NEW_EVM", 100))
+
+	fc.Extra["VIP214"] = 1
+	assert.NotNil(t, fc.Validate(), "conflicts with built-in fork")
+	fc.Extra = map[string]uint32{"": 1}
+	assert.NotNil(t, fc.Validate(), "empty name")
+}
@@ -14,8 +14,6 @@ import (
 
 // Constants of block chain.
 const (
-	BlockInterval uint64 = 10 // time interval between two consecutive blocks.
-
 	TxGas                     uint64 = 5000
 	ClauseGas                 uint64 = params.TxGas - TxGas
 	ClauseGasContractCreation uint64 = params.TxGasContractCreation - TxGas
@@ -36,8 +34,7 @@ const (
 
 	MaxStateHistory = 65535 // max guaranteed state history allowed to be accessed in EVM, presented in block number
 
-	SeederInterval     = 8640 // blocks between two seeder epochs.
-	CheckpointInterval = 180  // blocks between two bft checkpoints.
+	SeederInterval = 8640 // blocks between two seeder epochs.
 )
 
 // Keys of governance params.
@@ -122,6 +122,104 @@ func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) er
 	return utils.WriteJSON(w, acc)
 }
 
+// historyLimit is the max number of points in a history query.
+const historyLimit = 1000
+
+// getHistory returns balance and energy of the account at blocks on the best chain from the first block to the
+// last one with the step. States of all points are checked retained before reading, and then read by one account
+// reader, which skips points the account stays unchanged.
+func (a *Accounts) getHistory(ctx context.Context, addr ablock.Address, from, to, step uint32) ([]*AccountHistoryEntry, error) {
+	bestChain := a.repo.NewBestChain()
+	summaries := make([]*chain.BlockSummary, 0, (to-from)/step+1)
+	for n := uint64(from); n <= uint64(to); n += uint64(step) {
+		summary, err := bestChain.GetBlockSummary(uint32(n))
+		if err != nil {
+			return nil, err
+		}
+		if err := utils.CheckStateRetained(a.stater, uint32(n)); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	var (
+		reader  = a.stater.NewAccountReader(addr)
+		history = make([]*AccountHistoryEntry, 0, len(summaries))
+	)
+	for _, summary := range summaries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		acc, err := reader.Get(summary.Header.StateRoot(), summary.Header.Number(), summary.Conflicts, summary.SteadyNum)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, &AccountHistoryEntry{
+			Number:    summary.Header.Number(),
+			ID:        summary.Header.ID(),
+			Timestamp: summary.Header.Timestamp(),
+			Balance:   math.HexOrDecimal256(*acc.Balance),
+			Energy:    math.HexOrDecimal256(*acc.CalcEnergy(summary.Header.Timestamp())),
+		})
+	}
+	return history, nil
+}
+
+func (a *Accounts) handleGetHistory(w http.ResponseWriter, req *http.Request) error {
+	addr, err := ablock.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	query := req.URL.Query()
+	best := a.repo.BestBlockSummary().Header.Number()
+	to, err := parseBlockNumber(query.Get("to"), best)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "to"))
+	}
+	step, err := parseBlockNumber(query.Get("step"), 1)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "step"))
+	}
+	if step == 0 {
+		return utils.BadRequest(errors.New("step: must be positive"))
+	}
+	// from defaults to the earliest block that keeps the points within the limit
+	defFrom := uint32(0)
+	if span := uint64(historyLimit-1) * uint64(step); uint64(to) > span {
+		defFrom = to - uint32(span)
+	}
+	from, err := parseBlockNumber(query.Get("from"), defFrom)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "from"))
+	}
+	if from > to {
+		return utils.BadRequest(errors.New("from: greater than to"))
+	}
+	if to > best {
+		return utils.BadRequest(errors.New("to: beyond the best block"))
+	}
+	if (to-from)/step+1 > historyLimit {
+		return utils.BadRequest(errors.Errorf("too many points, should be no more than %v", historyLimit))
+	}
+	history, err := a.getHistory(req.Context(), addr, from, to, step)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, history)
+}
+
+// parseBlockNumber parses the block number, or returns the default value if empty.
+func parseBlockNumber(s string, def uint32) (uint32, error) {
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(n), nil
+}
+
 func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) error {
 	addr, err := ablock.ParseAddress(mux.Vars(req)["address"])
 	if err != nil {
@@ -142,6 +240,77 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 	return utils.WriteJSON(w, map[string]string{"value": storage.String()})
 }
 
+func (a *Accounts) getProof(addr ablock.Address, keys []ablock.Bytes32, summary *chain.BlockSummary) (*AccountProof, error) {
+	header := summary.Header
+	state := a.stater.NewState(header.StateRoot(), header.Number(), summary.Conflicts, summary.SteadyNum)
+
+	accProof, err := state.ProveAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	b, err := state.GetBalance(addr)
+	if err != nil {
+		return nil, err
+	}
+	energy, err := state.GetEnergy(addr, header.Timestamp())
+	if err != nil {
+		return nil, err
+	}
+	codeHash, err := state.GetCodeHash(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &AccountProof{
+		BlockID:      header.ID(),
+		Balance:      math.HexOrDecimal256(*b),
+		Energy:       math.HexOrDecimal256(*energy),
+		CodeHash:     codeHash,
+		AccountProof: utils.HexProof(accProof),
+		StorageProof: make([]*StorageProof, 0, len(keys)),
+	}
+	for _, key := range keys {
+		value, err := state.GetStorage(addr, key)
+		if err != nil {
+			return nil, err
+		}
+		storageProof, err := state.ProveStorage(addr, key)
+		if err != nil {
+			return nil, err
+		}
+		proof.StorageProof = append(proof.StorageProof, &StorageProof{
+			Key:   key,
+			Value: value,
+			Proof: utils.HexProof(storageProof),
+		})
+	}
+	return proof, nil
+}
+
+func (a *Accounts) handleGetProof(w http.ResponseWriter, req *http.Request) error {
+	addr, err := ablock.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	var keys []ablock.Bytes32
+	for _, k := range req.URL.Query()["key"] {
+		key, err := ablock.ParseBytes32(k)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "key"))
+		}
+		keys = append(keys, key)
+	}
+	summary, err := a.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	proof, err := a.getProof(addr, keys, summary)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, proof)
+}
+
 func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request) error {
 	callData := &CallData{}
 	if err := utils.ParseJSON(req.Body, &callData); err != nil {
@@ -327,6 +496,9 @@ func (a *Accounts) handleRevision(revision string) (*chain.BlockSummary, error)
 			}
 			return nil, err
 		}
+		if err := utils.CheckStateRetained(a.stater, summary.Header.Number()); err != nil {
+			return nil, err
+		}
 		return summary, nil
 	}
 	n, err := strconv.ParseUint(revision, 0, 0)
@@ -343,6 +515,9 @@ func (a *Accounts) handleRevision(revision string) (*chain.BlockSummary, error)
 		}
 		return nil, err
 	}
+	if err := utils.CheckStateRetained(a.stater, summary.Header.Number()); err != nil {
+		return nil, err
+	}
 	return summary, nil
 }
 
@@ -353,6 +528,8 @@ func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 	sub.Path("/{address}").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetAccount))
 	sub.Path("/{address}/code").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetCode))
 	sub.Path("/{address}/storage/{key}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetStorage))
+	sub.Path("/{address}/proof").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetProof))
+	sub.Path("/{address}/history").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetHistory))
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallContract))
 	sub.Path("/{address}").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallContract))
 
@@ -27,6 +27,7 @@ import (
 	"github.com/ashishaw/authorityblock/genesis"
 	"github.com/ashishaw/authorityblock/muxdb"
 	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/proof"
 	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
@@ -98,6 +99,7 @@ var invalidBytes32 = "0x00000000000000000000000000000000000000000000000000000000
 var invalidNumberRevision = "4294967296"                                                  //invalid block number
 
 var ts *httptest.Server
+var repo *chain.Repository
 
 func TestAccount(t *testing.T) {
 	initAccountServer(t)
@@ -105,6 +107,8 @@ func TestAccount(t *testing.T) {
 	getAccount(t)
 	getCode(t)
 	getStorage(t)
+	getProof(t)
+	getHistory(t)
 	deployContractWithCall(t)
 	callContract(t)
 	batchCall(t)
@@ -173,6 +177,107 @@ func getStorage(t *testing.T) {
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
 }
 
+func getProof(t *testing.T) {
+	res, statusCode := httpGet(t, ts.URL+"/accounts/"+invalidAddr+"/proof")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/proof?key="+invalidBytes32)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad storage key")
+
+	absentKey := ablock.BytesToBytes32([]byte{1})
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/proof?key="+storageKey.String()+"&key="+absentKey.String())
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var p accounts.AccountProof
+	if err := json.Unmarshal(res, &p); err != nil {
+		t.Fatal(err)
+	}
+	header := repo.BestBlockSummary().Header
+	assert.Equal(t, header.ID(), p.BlockID)
+
+	acc, err := proof.VerifyAccount(header, contractAddr, toBytes(p.AccountProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ablock.Bytes32(crypto.Keccak256Hash(runtimeBytecode)), p.CodeHash)
+	assert.Equal(t, p.CodeHash.Bytes(), acc.CodeHash)
+
+	if assert.Equal(t, 2, len(p.StorageProof)) {
+		v, err := proof.VerifyStorage(acc, storageKey, toBytes(p.StorageProof[0].Proof))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, ablock.BytesToBytes32([]byte{storageValue}), v)
+		assert.Equal(t, v, p.StorageProof[0].Value)
+
+		v, err = proof.VerifyStorage(acc, absentKey, toBytes(p.StorageProof[1].Proof))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, v.IsZero())
+	}
+
+	// incomplete proof
+	_, err = proof.VerifyStorage(acc, storageKey, nil)
+	assert.NotNil(t, err)
+
+	// proof of account not existent
+	res, _ = httpGet(t, ts.URL+"/accounts/"+ablock.BytesToAddress([]byte("none")).String()+"/proof")
+	if err := json.Unmarshal(res, &p); err != nil {
+		t.Fatal(err)
+	}
+	acc, err = proof.VerifyAccount(header, ablock.BytesToAddress([]byte("none")), toBytes(p.AccountProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, acc.IsEmpty())
+}
+
+func getHistory(t *testing.T) {
+	_, statusCode := httpGet(t, ts.URL+"/accounts/"+invalidAddr+"/history")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	for _, q := range []string{"from=2&to=1", "to=3", "step=0", "from=x", "to=0&step=4294967296"} {
+		_, statusCode = httpGet(t, ts.URL+"/accounts/"+addr.String()+"/history?"+q)
+		assert.Equal(t, http.StatusBadRequest, statusCode, q)
+	}
+
+	// range defaults to all blocks
+	res, statusCode := httpGet(t, ts.URL+"/accounts/"+addr.String()+"/history")
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var history []*accounts.AccountHistoryEntry
+	if err := json.Unmarshal(res, &history); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 3, len(history)) {
+		for i, e := range history {
+			assert.Equal(t, uint32(i), e.Number)
+		}
+		assert.Equal(t, 0, (*big.Int)(&history[0].Balance).Sign())
+		assert.Equal(t, math.HexOrDecimal256(*value), history[1].Balance)
+		assert.Equal(t, math.HexOrDecimal256(*value), history[2].Balance)
+		assert.Equal(t, repo.BestBlockSummary().Header.ID(), history[2].ID)
+	}
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+addr.String()+"/history?from=0&to=2&step=2")
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	history = nil
+	if err := json.Unmarshal(res, &history); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 2, len(history)) {
+		assert.Equal(t, uint32(0), history[0].Number)
+		assert.Equal(t, uint32(2), history[1].Number)
+	}
+}
+
+func toBytes(hex []hexutil.Bytes) [][]byte {
+	b := make([][]byte, 0, len(hex))
+	for _, h := range hex {
+		b = append(b, h)
+	}
+	return b
+}
+
 func initAccountServer(t *testing.T) {
 	db := muxdb.NewMem()
 	stater := state.NewStater(db)
@@ -182,7 +287,7 @@ func initAccountServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	repo, _ := chain.NewRepository(db, b)
+	repo, _ = chain.NewRepository(db, b)
 	claTransfer := tx.NewClause(&addr).WithValue(value)
 	claDeploy := tx.NewClause(nil).WithData(bytecode)
 	transaction := buildTxWithClauses(t, repo.ChainTag(), claTransfer, claDeploy)
@@ -223,7 +328,7 @@ func buildTxWithClauses(t *testing.T, chaiTag byte, clauses ...*tx.Clause) *tx.T
 }
 
 func packTx(repo *chain.Repository, stater *state.Stater, transaction *tx.Transaction, t *testing.T) {
-	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, ablock.NoFork)
+	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, ablock.NoFork, ablock.DefaultConfig)
 	flow, err := packer.Schedule(repo.BestBlockSummary(), uint64(time.Now().Unix()))
 	if err != nil {
 		t.Fatal(err)
@@ -20,6 +20,34 @@ type Account struct {
 	HasCode bool                 `json:"hasCode"`
 }
 
+// AccountHistoryEntry for marshal balance and energy of an account at a block.
+type AccountHistoryEntry struct {
+	Number    uint32               `json:"number"`
+	ID        ablock.Bytes32       `json:"id"`
+	Timestamp uint64               `json:"timestamp"`
+	Balance   math.HexOrDecimal256 `json:"balance"`
+	Energy    math.HexOrDecimal256 `json:"energy"`
+}
+
+// AccountProof for marshal merkle proofs of an account and its storage.
+// The account proof is against the state root of the block, and storage proofs
+// are against the storage root of the proved account.
+type AccountProof struct {
+	BlockID      ablock.Bytes32       `json:"blockID"`
+	Balance      math.HexOrDecimal256 `json:"balance"`
+	Energy       math.HexOrDecimal256 `json:"energy"`
+	CodeHash     ablock.Bytes32       `json:"codeHash"`
+	AccountProof []hexutil.Bytes      `json:"accountProof"`
+	StorageProof []*StorageProof      `json:"storageProof"`
+}
+
+// StorageProof for marshal merkle proof of a storage value.
+type StorageProof struct {
+	Key   ablock.Bytes32  `json:"key"`
+	Value ablock.Bytes32  `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
 //CallData represents contract-call body
 type CallData struct {
 	Value    *math.HexOrDecimal256 `json:"value"`
@@ -16,9 +16,11 @@ import (
 	"github.com/ashishaw/authorityblock/api/accounts"
 	"github.com/ashishaw/authorityblock/api/blocks"
 	"github.com/ashishaw/authorityblock/api/debug"
+	"github.com/ashishaw/authorityblock/api/delegator"
 	"github.com/ashishaw/authorityblock/api/doc"
 	"github.com/ashishaw/authorityblock/api/events"
 	"github.com/ashishaw/authorityblock/api/node"
+	"github.com/ashishaw/authorityblock/api/params"
 	"github.com/ashishaw/authorityblock/api/subscriptions"
 	"github.com/ashishaw/authorityblock/api/transactions"
 	"github.com/ashishaw/authorityblock/api/transfers"
@@ -37,12 +39,16 @@ func New(
 	logDB *logdb.LogDB,
 	bft blocks.BFTEngine,
 	nw node.Network,
+	evidences node.EvidenceSource,
+	packingReports node.PackingReportSource,
+	gasPayer *delegator.Delegator,
 	allowedOrigins string,
 	backtraceLimit uint32,
 	callGasLimit uint64,
 	pprofOn bool,
 	skipLogs bool,
 	forkConfig ablock.ForkConfig,
+	config ablock.Config,
 ) (http.HandlerFunc, func()) {
 
 	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
@@ -74,15 +80,23 @@ func New(
 			Mount(router, "/logs/event")
 		transfers.New(repo, logDB).
 			Mount(router, "/logs/transfer")
+		params.New(repo, stater, logDB).
+			Mount(router, "/params")
+	} else {
+		params.New(repo, stater, nil).
+			Mount(router, "/params")
 	}
 	blocks.New(repo, bft).
 		Mount(router, "/blocks")
 	transactions.New(repo, txPool).
 		Mount(router, "/transactions")
-	debug.New(repo, stater, forkConfig).
+	debug.New(repo, stater, forkConfig, config).
 		Mount(router, "/debug")
-	node.New(nw).
+	node.New(repo, nw, evidences, packingReports, txPool, txPool, forkConfig).
 		Mount(router, "/node")
+	if gasPayer != nil {
+		gasPayer.Mount(router, "/delegator")
+	}
 	subs := subscriptions.New(repo, origins, backtraceLimit)
 	subs.Mount(router, "/subscriptions")
 
@@ -94,7 +108,7 @@ func New(
 		router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
 	}
 
-	handler := handlers.CompressHandler(router)
+	handler := handlers.CompressHandler(meterRequests(router))
 	handler = handlers.CORS(
 		handlers.AllowedOrigins(origins),
 		handlers.AllowedHeaders([]string{"content-type", "x-genesis-id"}),
@@ -35,13 +35,15 @@ type Debug struct {
 	repo       *chain.Repository
 	stater     *state.Stater
 	forkConfig ablock.ForkConfig
+	config     ablock.Config
 }
 
-func New(repo *chain.Repository, stater *state.Stater, forkConfig ablock.ForkConfig) *Debug {
+func New(repo *chain.Repository, stater *state.Stater, forkConfig ablock.ForkConfig, config ablock.Config) *Debug {
 	return &Debug{
 		repo,
 		stater,
 		forkConfig,
+		config,
 	}
 }
 
@@ -60,11 +62,16 @@ func (d *Debug) handleTxEnv(ctx context.Context, blockID ablock.Bytes32, txIndex
 	if clauseIndex >= uint64(len(txs[txIndex].Clauses())) {
 		return nil, nil, utils.Forbidden(errors.New("clause index out of range"))
 	}
+	// replay starts from the parent state
+	if err := utils.CheckStateRetained(d.stater, block.Header().Number()-1); err != nil {
+		return nil, nil, err
+	}
 	skipPoA := d.repo.GenesisBlock().Header().ID() == devNetGenesisID
 	rt, err := consensus.New(
 		d.repo,
 		d.stater,
 		d.forkConfig,
+		d.config,
 	).NewRuntimeForReplay(block.Header(), skipPoA)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package delegator implements the VIP-191 gas payer service, which co-signs txs
+// satisfying the policy.
+package delegator
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/api/utils"
+	"github.com/ashishaw/authorityblock/builtin"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+type Delegator struct {
+	repo    *chain.Repository
+	stater  *state.Stater
+	key     *ecdsa.PrivateKey
+	address ablock.Address
+	policy  *Policy
+	budget  *budget
+}
+
+// New creates the delegator service, which signs with the given key.
+func New(repo *chain.Repository, stater *state.Stater, key *ecdsa.PrivateKey, policy *Policy) *Delegator {
+	return &Delegator{
+		repo,
+		stater,
+		key,
+		ablock.Address(crypto.PubkeyToAddress(key.PublicKey)),
+		policy,
+		newBudget(policy.DailyEnergyBudget),
+	}
+}
+
+// Address returns address of the delegator.
+func (d *Delegator) Address() ablock.Address {
+	return d.address
+}
+
+// Sign checks the tx against the policy and signs it.
+func (d *Delegator) Sign(trx *tx.Transaction, origin ablock.Address) ([]byte, error) {
+	if !trx.Features().IsDelegated() {
+		return nil, errors.New("tx not delegated")
+	}
+	if trx.ChainTag() != d.repo.ChainTag() {
+		return nil, errors.New("chain tag mismatch")
+	}
+	if err := d.policy.CheckClauses(trx.Clauses()); err != nil {
+		return nil, err
+	}
+	// the origin must be proved before its budget charged, otherwise anyone can drain the budget of others
+	sig := trx.Signature()
+	if len(sig) == 0 {
+		return nil, errors.New("origin signature required")
+	}
+	if len(sig) != 65 {
+		return nil, errors.New("invalid origin signature length")
+	}
+	pub, err := crypto.SigToPub(trx.SigningHash().Bytes(), sig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "origin signature")
+	}
+	if ablock.Address(crypto.PubkeyToAddress(*pub)) != origin {
+		return nil, errors.New("origin mismatch")
+	}
+
+	best := d.repo.BestBlockSummary()
+	st := d.stater.NewState(best.Header.StateRoot(), best.Header.Number(), best.Conflicts, best.SteadyNum)
+	baseGasPrice, err := builtin.Params.Native(st).Get(ablock.KeyBaseGasPrice)
+	if err != nil {
+		return nil, err
+	}
+	// charge the budget with max energy the tx would cost
+	cost := trx.GasPrice(baseGasPrice)
+	cost.Mul(cost, new(big.Int).SetUint64(trx.Gas()))
+	if err := d.budget.Spend(origin, cost, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return crypto.Sign(trx.DelegatorSigningHash(origin).Bytes(), d.key)
+}
+
+func (d *Delegator) handleSign(w http.ResponseWriter, req *http.Request) error {
+	var body SignRequest
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	var trx *tx.Transaction
+	if err := rlp.DecodeBytes(body.Raw, &trx); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "raw"))
+	}
+	sig, err := d.Sign(trx, body.Origin)
+	if err != nil {
+		return utils.Forbidden(err)
+	}
+	return utils.WriteJSON(w, &SignResult{sig})
+}
+
+func (d *Delegator) handleAddress(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, &Info{d.address})
+}
+
+func (d *Delegator) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(d.handleAddress))
+	sub.Path("/sign").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(d.handleSign))
+}
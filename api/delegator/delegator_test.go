@@ -0,0 +1,146 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package delegator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/api/delegator"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+var (
+	contract = ablock.BytesToAddress([]byte("contract"))
+	selector = []byte{1, 2, 3, 4}
+	origin   = genesis.DevAccounts()[0]
+	payer    = genesis.DevAccounts()[1]
+)
+
+func newServer(t *testing.T, policy *delegator.Policy) (*httptest.Server, *chain.Repository) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b)
+
+	router := mux.NewRouter()
+	delegator.New(repo, stater, payer.PrivateKey, policy).Mount(router, "/delegator")
+	return httptest.NewServer(router), repo
+}
+
+func newTx(chainTag byte, to *ablock.Address, data []byte, delegated bool) *tx.Transaction {
+	var features tx.Features
+	features.SetDelegated(delegated)
+	return new(tx.Builder).
+		ChainTag(chainTag).
+		Clause(tx.NewClause(to).WithData(data)).
+		Gas(100000).
+		Expiration(100).
+		Features(features).
+		Build()
+}
+
+func sign(t *testing.T, url string, trx *tx.Transaction, origin ablock.Address) (int, []byte) {
+	raw, _ := rlp.EncodeToBytes(trx)
+	body, _ := json.Marshal(&delegator.SignRequest{Raw: raw, Origin: origin})
+	res, err := http.Post(url+"/delegator/sign", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, _ := ioutil.ReadAll(res.Body)
+	return res.StatusCode, data
+}
+
+func TestDelegator(t *testing.T) {
+	// budget for 2 txs at base gas price
+	budget := math.HexOrDecimal256(*new(big.Int).Mul(big.NewInt(200000), ablock.InitialBaseGasPrice))
+	ts, repo := newServer(t, &delegator.Policy{
+		AllowedContracts:  []ablock.Address{contract},
+		AllowedMethods:    []hexutil.Bytes{selector},
+		DailyEnergyBudget: &budget,
+	})
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/delegator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info delegator.Info
+	json.NewDecoder(res.Body).Decode(&info)
+	res.Body.Close()
+	assert.Equal(t, payer.Address, info.Address)
+
+	// unsigned tx is refused, before the budget charged
+	trx := newTx(repo.ChainTag(), &contract, append(selector, 5), true)
+	code, data := sign(t, ts.URL, trx, origin.Address)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "origin signature required", string(bytes.TrimSpace(data)))
+
+	// the delegator signs the origin signed tx
+	originSig, _ := crypto.Sign(trx.SigningHash().Bytes(), origin.PrivateKey)
+	code, data = sign(t, ts.URL, trx.WithSignature(originSig), origin.Address)
+	assert.Equal(t, http.StatusOK, code, string(data))
+	var result delegator.SignResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	signed := trx.WithSignature(append(originSig, result.Signature...))
+	d, err := signed.Delegator()
+	assert.Nil(t, err)
+	assert.Equal(t, payer.Address, *d)
+
+	code, _ = sign(t, ts.URL, trx.WithSignature(originSig), origin.Address)
+	assert.Equal(t, http.StatusOK, code)
+
+	tests := []struct {
+		name   string
+		tx     *tx.Transaction
+		origin ablock.Address
+		err    string
+	}{
+		{"budget", trx.WithSignature(originSig), origin.Address, "daily energy budget exceeded"},
+		{"not delegated", newTx(repo.ChainTag(), &contract, selector, false), payer.Address, "tx not delegated"},
+		{"chain tag", newTx(repo.ChainTag()+1, &contract, selector, true), payer.Address, "chain tag mismatch"},
+		{"contract", newTx(repo.ChainTag(), &payer.Address, selector, true), payer.Address, "clause #0: contract not allowed"},
+		{"creation", newTx(repo.ChainTag(), nil, selector, true), payer.Address, "clause #0: contract creation not allowed"},
+		{"method", newTx(repo.ChainTag(), &contract, []byte{1, 2, 3, 5}, true), payer.Address, "clause #0: method not allowed"},
+		{"origin mismatch", trx.WithSignature(originSig), payer.Address, "origin mismatch"},
+	}
+	for _, tt := range tests {
+		code, data := sign(t, ts.URL, tt.tx, tt.origin)
+		assert.Equal(t, http.StatusForbidden, code, tt.name)
+		assert.Equal(t, tt.err, string(bytes.TrimSpace(data)), tt.name)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	budget := math.HexOrDecimal256(*big.NewInt(1))
+	assert.Nil(t, (&delegator.Policy{DailyEnergyBudget: &budget}).Validate())
+	assert.NotNil(t, (&delegator.Policy{}).Validate(), "budget required")
+	assert.NotNil(t, (&delegator.Policy{AllowedMethods: []hexutil.Bytes{{1, 2, 3}}, DailyEnergyBudget: &budget}).Validate())
+	negative := math.HexOrDecimal256(*big.NewInt(-1))
+	assert.NotNil(t, (&delegator.Policy{DailyEnergyBudget: &negative}).Validate())
+}
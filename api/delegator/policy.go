@@ -0,0 +1,147 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package delegator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+// Policy decides which txs to be sponsored.
+type Policy struct {
+	// AllowedContracts contracts allowed to be called, any if empty.
+	// Contract creation is not allowed if set.
+	AllowedContracts []ablock.Address `json:"allowedContracts"`
+	// AllowedMethods selectors of methods allowed to be called, any if empty.
+	// Clauses without data are not allowed if set.
+	AllowedMethods []hexutil.Bytes `json:"allowedMethods"`
+	// DailyEnergyBudget max energy sponsored for an origin in a UTC day, required.
+	DailyEnergyBudget *math.HexOrDecimal256 `json:"dailyEnergyBudget"`
+}
+
+// LoadPolicy loads policy from JSON file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate validates the policy.
+func (p *Policy) Validate() error {
+	for _, m := range p.AllowedMethods {
+		if len(m) != 4 {
+			return errors.Errorf("invalid method selector %v", m)
+		}
+	}
+	if p.DailyEnergyBudget == nil {
+		return errors.New("daily energy budget required")
+	}
+	if (*big.Int)(p.DailyEnergyBudget).Sign() < 0 {
+		return errors.New("negative daily energy budget")
+	}
+	return nil
+}
+
+// CheckClauses checks whether all clauses are allowed.
+func (p *Policy) CheckClauses(clauses []*tx.Clause) error {
+	if len(clauses) == 0 {
+		return errors.New("no clause")
+	}
+	for i, c := range clauses {
+		if len(p.AllowedContracts) > 0 {
+			if c.To() == nil {
+				return errors.Errorf("clause #%v: contract creation not allowed", i)
+			}
+			if !p.isContractAllowed(*c.To()) {
+				return errors.Errorf("clause #%v: contract not allowed", i)
+			}
+		}
+		if len(p.AllowedMethods) > 0 {
+			data := c.Data()
+			if len(data) < 4 || !p.isMethodAllowed(data[:4]) {
+				return errors.Errorf("clause #%v: method not allowed", i)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) isContractAllowed(addr ablock.Address) bool {
+	for _, c := range p.AllowedContracts {
+		if c == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) isMethodAllowed(selector []byte) bool {
+	for _, m := range p.AllowedMethods {
+		if bytes.Equal(m, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// budget tracks energy sponsored per origin in the current UTC day.
+type budget struct {
+	limit *big.Int // nil if unlimited
+	lock  sync.Mutex
+	day   int64
+	spent map[ablock.Address]*big.Int
+}
+
+func newBudget(limit *math.HexOrDecimal256) *budget {
+	b := &budget{spent: make(map[ablock.Address]*big.Int)}
+	if limit != nil {
+		b.limit = (*big.Int)(limit)
+	}
+	return b
+}
+
+// Spend charges the origin with the amount of energy, if the budget is not exceeded.
+func (b *budget) Spend(origin ablock.Address, amount *big.Int, now time.Time) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if day := now.UTC().Unix() / (24 * 3600); day != b.day {
+		b.day = day
+		b.spent = make(map[ablock.Address]*big.Int)
+	}
+
+	spent := b.spent[origin]
+	if spent == nil {
+		spent = new(big.Int)
+	}
+	newSpent := new(big.Int).Add(spent, amount)
+	if b.limit != nil && newSpent.Cmp(b.limit) > 0 {
+		return errors.New("daily energy budget exceeded")
+	}
+	b.spent[origin] = newSpent
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package delegator
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// SignRequest the request to sign a tx as the delegator.
+type SignRequest struct {
+	// Raw RLP encoded tx, which is not signed or signed by the origin only.
+	Raw    hexutil.Bytes  `json:"raw"`
+	Origin ablock.Address `json:"origin"`
+}
+
+// SignResult the signature of the delegator.
+type SignResult struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Info the info of the delegator.
+type Info struct {
+	Address ablock.Address `json:"address"`
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ashishaw/authorityblock/metric"
+)
+
+var metricRequestDuration = metric.NewHistogramVec("api_request_duration_seconds", "duration of API requests", nil, "route", "method")
+
+// meterRequests records the duration of requests, labelled by the path template of matched route.
+// Long-lived websocket requests are not metered.
+func meterRequests(router *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			router.ServeHTTP(w, req)
+			return
+		}
+
+		route := "unknown"
+		var match mux.RouteMatch
+		if router.Match(req, &match) && match.Route != nil {
+			if tpl, err := match.Route.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		startTime := time.Now()
+		router.ServeHTTP(w, req)
+		metricRequestDuration.WithLabelValues(route, req.Method).Observe(time.Since(startTime).Seconds())
+	})
+}
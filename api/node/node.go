@@ -7,18 +7,46 @@ package node
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/ashishaw/authorityblock/api/utils"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/ablock"
 )
 
+const defaultReportLimit = 10
+
 type Node struct {
-	nw Network
+	repo           *chain.Repository
+	nw             Network
+	evidences      EvidenceSource
+	packingReports PackingReportSource
+	blocklist      Blocklist
+	rateLimits     RateLimitSource
+	forkConfig     ablock.ForkConfig
 }
 
-func New(nw Network) *Node {
+// New creates the node API. evidences can be nil if misbehavior watching is disabled,
+// and packingReports can be nil if the node doesn't pack blocks.
+func New(
+	repo *chain.Repository,
+	nw Network,
+	evidences EvidenceSource,
+	packingReports PackingReportSource,
+	blocklist Blocklist,
+	rateLimits RateLimitSource,
+	forkConfig ablock.ForkConfig,
+) *Node {
 	return &Node{
+		repo,
 		nw,
+		evidences,
+		packingReports,
+		blocklist,
+		rateLimits,
+		forkConfig,
 	}
 }
 
@@ -30,8 +58,65 @@ func (n *Node) handleNetwork(w http.ResponseWriter, req *http.Request) error {
 	return utils.WriteJSON(w, n.PeersStats())
 }
 
+func (n *Node) handleEvidences(w http.ResponseWriter, req *http.Request) error {
+	if n.evidences == nil {
+		return utils.WriteJSON(w, []*Evidence{})
+	}
+	evs, err := n.evidences.Evidences()
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, ConvertEvidences(evs))
+}
+
+func (n *Node) handlePackingReports(w http.ResponseWriter, req *http.Request) error {
+	limit := defaultReportLimit
+	if s := req.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 16)
+		if err != nil || v == 0 {
+			return utils.BadRequest(errors.New("limit: should be positive integer"))
+		}
+		limit = int(v)
+	}
+	if n.packingReports == nil {
+		return utils.WriteJSON(w, []*PackingReport{})
+	}
+	return utils.WriteJSON(w, ConvertPackingReports(n.packingReports.Last(limit)))
+}
+
+func (n *Node) handleForks(w http.ResponseWriter, req *http.Request) error {
+	best := n.repo.BestBlockSummary().Header.Number()
+	return utils.WriteJSON(w, ConvertForks(n.forkConfig, best))
+}
+
+func (n *Node) handleBlocklist(w http.ResponseWriter, req *http.Request) error {
+	addr, err := ablock.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	source := n.blocklist.BlockedBy(addr)
+	return utils.WriteJSON(w, &BlocklistEntry{
+		Address: addr,
+		Blocked: source != "",
+		Source:  source,
+	})
+}
+
+func (n *Node) handleRateLimit(w http.ResponseWriter, req *http.Request) error {
+	addr, err := ablock.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	return utils.WriteJSON(w, ConvertRateLimitEntry(addr, n.rateLimits.RateLimitState(addr)))
+}
+
 func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("/network/peers").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleNetwork))
+	sub.Path("/evidences").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleEvidences))
+	sub.Path("/forks").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleForks))
+	sub.Path("/packing/reports").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handlePackingReports))
+	sub.Path("/blocklist/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleBlocklist))
+	sub.Path("/ratelimit/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleRateLimit))
 }
@@ -15,11 +15,14 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/ashishaw/authorityblock/api/node"
+	"github.com/ashishaw/authorityblock/bft"
 	"github.com/ashishaw/authorityblock/chain"
 	"github.com/ashishaw/authorityblock/comm"
 	"github.com/ashishaw/authorityblock/genesis"
 	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
 	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/txpool"
 )
 
@@ -33,6 +36,65 @@ func TestNode(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, 0, len(peersStats), "count should be zero")
+
+	res = httpGet(t, ts.URL+"/node/evidences")
+	var evidences []*node.Evidence
+	if err := json.Unmarshal(res, &evidences); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(evidences), "count should be zero")
+
+	res = httpGet(t, ts.URL+"/node/forks")
+	var forks []*node.Fork
+	if err := json.Unmarshal(res, &forks); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*node.Fork{
+		{Name: "VIP191", BlockNumber: 0, Active: true},
+		{Name: "FINALITY", BlockNumber: 10, Active: false},
+		{Name: "NEW_EVM", BlockNumber: 0, Active: true},
+	}, forks)
+
+	res = httpGet(t, ts.URL+"/node/packing/reports?limit=1")
+	var reports []*node.PackingReport
+	if err := json.Unmarshal(res, &reports); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(reports)) {
+		assert.Equal(t, uint32(3), reports[0].Number)
+		assert.Equal(t, 1, reports[0].Rejected.KnownTx)
+		assert.Equal(t, 0.5, reports[0].GasUtilization)
+	}
+
+	res = httpGet(t, ts.URL+"/node/packing/reports")
+	if err := json.Unmarshal(res, &reports); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(reports), "only the last 2 kept")
+
+	blocked := ablock.MustParseAddress("0x4427be8010dd870395975a8fbaa7afa9439b5332") // in builtin list
+	res = httpGet(t, ts.URL+"/node/blocklist/"+blocked.String())
+	var entry node.BlocklistEntry
+	if err := json.Unmarshal(res, &entry); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, node.BlocklistEntry{Address: blocked, Blocked: true, Source: txpool.BlocklistSourceBuiltin}, entry)
+
+	other := ablock.BytesToAddress([]byte("other"))
+	res = httpGet(t, ts.URL+"/node/blocklist/"+other.String())
+	entry = node.BlocklistEntry{}
+	if err := json.Unmarshal(res, &entry); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, node.BlocklistEntry{Address: other}, entry)
+
+	// rate limiting disabled
+	res = httpGet(t, ts.URL+"/node/ratelimit/"+other.String())
+	var rateLimit node.RateLimitEntry
+	if err := json.Unmarshal(res, &rateLimit); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, node.RateLimitEntry{Address: other, Limits: []*node.RateLimitState{}}, rateLimit)
 }
 
 func initCommServer(t *testing.T) {
@@ -45,13 +107,22 @@ func initCommServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	repo, _ := chain.NewRepository(db, b)
-	comm := comm.New(repo, txpool.New(repo, stater, txpool.Options{
+	pool := txpool.New(repo, stater, txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     10 * time.Minute,
-	}))
+	})
+	comm := comm.New(repo, pool, db, ablock.DefaultConfig)
 	router := mux.NewRouter()
-	node.New(comm).Mount(router, "/node")
+	fc := ablock.NoFork
+	fc.VIP191 = 0
+	fc.FINALITY = 10
+	fc.Extra = map[string]uint32{"NEW_EVM": 0}
+	reports := packer.NewReports(2)
+	for i := uint32(1); i <= 3; i++ {
+		reports.Add(&packer.Report{Number: i, Candidates: 2, Adopted: 1, Rejected: packer.Rejections{KnownTx: 1}, GasUsed: 50, GasLimit: 100})
+	}
+	node.New(repo, comm, bft.NewWatcher(repo, db, false), reports, pool, pool, fc).Mount(router, "/node")
 	ts = httptest.NewServer(router)
 }
 
@@ -6,14 +6,41 @@
 package node
 
 import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ashishaw/authorityblock/bft"
 	"github.com/ashishaw/authorityblock/comm"
+	"github.com/ashishaw/authorityblock/packer"
 	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/txpool"
 )
 
 type Network interface {
 	PeersStats() []*comm.PeerStats
 }
 
+// EvidenceSource provides evidence of authority misbehavior.
+type EvidenceSource interface {
+	Evidences() ([]*bft.Evidence, error)
+}
+
+// PackingReportSource provides reports of recently packed blocks.
+type PackingReportSource interface {
+	Last(n int) []*packer.Report
+}
+
+// Blocklist tells which blocklist source blocks an address.
+type Blocklist interface {
+	BlockedBy(addr ablock.Address) string
+}
+
+// RateLimitSource provides rate limiting state of accounts.
+type RateLimitSource interface {
+	RateLimitState(addr ablock.Address) []*txpool.RateLimitState
+}
+
 type PeerStats struct {
 	Name        string       `json:"name"`
 	BestBlockID ablock.Bytes32 `json:"bestBlockID"`
@@ -42,3 +69,143 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 	}
 	return peersStats
 }
+
+// Evidence is a pair of conflicting headers signed by the same signer.
+type Evidence struct {
+	Kind    bft.EvidenceKind  `json:"kind"`
+	Signer  ablock.Address    `json:"signer"`
+	Headers []*EvidenceHeader `json:"headers"`
+}
+
+type EvidenceHeader struct {
+	ID        ablock.Bytes32 `json:"id"`
+	Number    uint32         `json:"number"`
+	ParentID  ablock.Bytes32 `json:"parentID"`
+	Timestamp uint64         `json:"timestamp"`
+	COM       bool           `json:"com"`
+	Raw       string         `json:"raw"`
+}
+
+func ConvertEvidences(evs []*bft.Evidence) []*Evidence {
+	list := make([]*Evidence, 0, len(evs))
+	for _, ev := range evs {
+		e := &Evidence{
+			Kind:   ev.Kind,
+			Signer: ev.Signer,
+		}
+		for _, h := range ev.Headers {
+			raw, _ := rlp.EncodeToBytes(h)
+			e.Headers = append(e.Headers, &EvidenceHeader{
+				ID:        h.ID(),
+				Number:    h.Number(),
+				ParentID:  h.ParentID(),
+				Timestamp: h.Timestamp(),
+				COM:       h.COM(),
+				Raw:       hexutil.Encode(raw),
+			})
+		}
+		list = append(list, e)
+	}
+	return list
+}
+
+// Fork is a scheduled fork and whether it's active at the best block.
+type Fork struct {
+	Name        string `json:"name"`
+	BlockNumber uint32 `json:"blockNumber"`
+	Active      bool   `json:"active"`
+}
+
+func ConvertForks(fc ablock.ForkConfig, bestNum uint32) []*Fork {
+	forks := fc.Forks()
+	list := make([]*Fork, 0, len(forks))
+	for _, f := range forks {
+		list = append(list, &Fork{
+			Name:        f.Name,
+			BlockNumber: f.BlockNum,
+			Active:      bestNum >= f.BlockNum,
+		})
+	}
+	return list
+}
+
+type Rejections struct {
+	BadTx               int `json:"badTx"`
+	BlockedOrigin       int `json:"blockedOrigin"`
+	NotAdoptableNow     int `json:"notAdoptableNow"`
+	NotAdoptableForever int `json:"notAdoptableForever"`
+	GasLimitReached     int `json:"gasLimitReached"`
+	KnownTx             int `json:"knownTx"`
+	Other               int `json:"other"`
+}
+
+// PackingReport is the statistics of packing a block, times are in milliseconds.
+type PackingReport struct {
+	Number         uint32         `json:"number"`
+	ID             ablock.Bytes32 `json:"id"`
+	Timestamp      uint64         `json:"timestamp"`
+	Candidates     int            `json:"candidates"`
+	Adopted        int            `json:"adopted"`
+	Rejected       Rejections     `json:"rejected"`
+	AdoptTime      float64        `json:"adoptTime"`
+	ExecTime       float64        `json:"execTime"`
+	GasUsed        uint64         `json:"gasUsed"`
+	GasLimit       uint64         `json:"gasLimit"`
+	GasUtilization float64        `json:"gasUtilization"`
+}
+
+func ConvertPackingReports(reports []*packer.Report) []*PackingReport {
+	list := make([]*PackingReport, 0, len(reports))
+	for _, r := range reports {
+		list = append(list, &PackingReport{
+			Number:         r.Number,
+			ID:             r.ID,
+			Timestamp:      r.Timestamp,
+			Candidates:     r.Candidates,
+			Adopted:        r.Adopted,
+			Rejected:       Rejections(r.Rejected),
+			AdoptTime:      float64(r.AdoptTime) / float64(time.Millisecond),
+			ExecTime:       float64(r.ExecTime) / float64(time.Millisecond),
+			GasUsed:        r.GasUsed,
+			GasLimit:       r.GasLimit,
+			GasUtilization: r.GasUtilization(),
+		})
+	}
+	return list
+}
+
+// BlocklistEntry tells whether an address is blocked, and by which source.
+type BlocklistEntry struct {
+	Address ablock.Address `json:"address"`
+	Blocked bool           `json:"blocked"`
+	Source  string         `json:"source,omitempty"`
+}
+
+// RateLimitEntry is the rate limiting state of an address, limits are empty if rate limiting is disabled.
+type RateLimitEntry struct {
+	Address ablock.Address    `json:"address"`
+	Limits  []*RateLimitState `json:"limits"`
+}
+
+type RateLimitState struct {
+	Kind        string `json:"kind"`
+	Txs         int    `json:"txs"`
+	Failures    int    `json:"failures"`
+	BannedUntil uint32 `json:"bannedUntil"`
+}
+
+func ConvertRateLimitEntry(addr ablock.Address, states []*txpool.RateLimitState) *RateLimitEntry {
+	entry := &RateLimitEntry{
+		Address: addr,
+		Limits:  make([]*RateLimitState, 0, len(states)),
+	}
+	for _, st := range states {
+		entry.Limits = append(entry.Limits, &RateLimitState{
+			Kind:        st.Kind,
+			Txs:         st.Txs,
+			Failures:    st.Failures,
+			BannedUntil: st.BannedUntil,
+		})
+	}
+	return entry
+}
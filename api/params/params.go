@@ -0,0 +1,209 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package params
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/api/utils"
+	"github.com/ashishaw/authorityblock/builtin"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// KnownParams lists names of the governance params used by the protocol.
+var KnownParams = []string{
+	"executor",
+	"reward-ratio",
+	"base-gas-price",
+	"proposer-endorsement",
+	"max-block-proposers",
+}
+
+type Params struct {
+	repo   *chain.Repository
+	stater *state.Stater
+	logDB  *logdb.LogDB
+}
+
+// New creates the params API. The history of params is omitted if logDB is nil.
+func New(repo *chain.Repository, stater *state.Stater, logDB *logdb.LogDB) *Params {
+	return &Params{
+		repo,
+		stater,
+		logDB,
+	}
+}
+
+// ParseKey converts param name or hex bytes32 into the param key.
+func ParseKey(s string) (ablock.Bytes32, error) {
+	if len(s) == 66 || len(s) == 64 {
+		return ablock.ParseBytes32(s)
+	}
+	if len(s) > 32 {
+		return ablock.Bytes32{}, errors.New("name too long")
+	}
+	return ablock.BytesToBytes32([]byte(s)), nil
+}
+
+// KeyName returns the name of the key if it's printable, otherwise the hex string.
+func KeyName(key ablock.Bytes32) string {
+	b := key.Bytes()
+	i := 0
+	for i < len(b) && b[i] == 0 {
+		i++
+	}
+	for _, c := range b[i:] {
+		if c < 0x20 || c > 0x7e {
+			return key.String()
+		}
+	}
+	return string(b[i:])
+}
+
+func (p *Params) getParam(ctx context.Context, key ablock.Bytes32, summary *chain.BlockSummary) (*Param, error) {
+	st := p.stater.NewState(summary.Header.StateRoot(), summary.Header.Number(), summary.Conflicts, summary.SteadyNum)
+	value, err := builtin.Params.Native(st).Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	param := &Param{
+		Key:   key,
+		Name:  KeyName(key),
+		Value: (*math.HexOrDecimal256)(value),
+	}
+	if p.logDB != nil {
+		if param.History, err = p.getHistory(ctx, key, summary); err != nil {
+			return nil, err
+		}
+	}
+	return param, nil
+}
+
+// getHistory collects the changes of the param up to the given block, from 'Set' events of the Params contract.
+func (p *Params) getHistory(ctx context.Context, key ablock.Bytes32, summary *chain.BlockSummary) ([]*Change, error) {
+	ev, _ := builtin.Params.ABI.EventByName("Set")
+	topic0 := ev.ID()
+	events, err := p.logDB.FilterEvents(ctx, &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{
+			Address: &builtin.Params.Address,
+			Topics:  [5]*ablock.Bytes32{&topic0, &key},
+		}},
+		Range: &logdb.Range{From: 0, To: summary.Header.Number()},
+		Order: logdb.ASC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*Change, 0, len(events))
+	for _, e := range events {
+		var value *big.Int
+		if err := ev.Decode(e.Data, &value); err != nil {
+			return nil, err
+		}
+		history = append(history, &Change{
+			Value: (*math.HexOrDecimal256)(value),
+			Meta: ChangeMeta{
+				BlockID:        e.BlockID,
+				BlockNumber:    e.BlockNumber,
+				BlockTimestamp: e.BlockTime,
+				TxID:           e.TxID,
+				TxOrigin:       e.TxOrigin,
+			},
+		})
+	}
+	return history, nil
+}
+
+func (p *Params) handleGetParams(w http.ResponseWriter, req *http.Request) error {
+	summary, err := p.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	params := make([]*Param, 0, len(KnownParams))
+	for _, name := range KnownParams {
+		param, err := p.getParam(req.Context(), ablock.BytesToBytes32([]byte(name)), summary)
+		if err != nil {
+			return err
+		}
+		params = append(params, param)
+	}
+	return utils.WriteJSON(w, params)
+}
+
+func (p *Params) handleGetParam(w http.ResponseWriter, req *http.Request) error {
+	key, err := ParseKey(mux.Vars(req)["key"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "key"))
+	}
+	summary, err := p.handleRevision(req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	param, err := p.getParam(req.Context(), key, summary)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, param)
+}
+
+func (p *Params) handleRevision(revision string) (*chain.BlockSummary, error) {
+	if revision == "" || revision == "best" {
+		return p.repo.BestBlockSummary(), nil
+	}
+	if len(revision) == 66 || len(revision) == 64 {
+		blockID, err := ablock.ParseBytes32(revision)
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		summary, err := p.repo.GetBlockSummary(blockID)
+		if err != nil {
+			if p.repo.IsNotFound(err) {
+				return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+			}
+			return nil, err
+		}
+		if err := utils.CheckStateRetained(p.stater, summary.Header.Number()); err != nil {
+			return nil, err
+		}
+		return summary, nil
+	}
+	n, err := strconv.ParseUint(revision, 0, 0)
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	if n > math.MaxUint32 {
+		return nil, utils.BadRequest(errors.WithMessage(errors.New("block number out of max uint32"), "revision"))
+	}
+	summary, err := p.repo.NewBestChain().GetBlockSummary(uint32(n))
+	if err != nil {
+		if p.repo.IsNotFound(err) {
+			return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		return nil, err
+	}
+	if err := utils.CheckStateRetained(p.stater, summary.Header.Number()); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (p *Params) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(p.handleGetParams))
+	sub.Path("/{key}").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(p.handleGetParam))
+}
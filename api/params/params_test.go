@@ -0,0 +1,163 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package params_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/api/params"
+	"github.com/ashishaw/authorityblock/builtin"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+var (
+	ts          *httptest.Server
+	newGasPrice = big.NewInt(1234)
+)
+
+func TestParams(t *testing.T) {
+	initParamsServer(t)
+	defer ts.Close()
+
+	res, statusCode := httpGet(t, ts.URL+"/params")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var ps []*params.Param
+	if err := json.Unmarshal(res, &ps); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(params.KnownParams), len(ps))
+	for i, p := range ps {
+		assert.Equal(t, params.KnownParams[i], p.Name)
+	}
+
+	res, statusCode = httpGet(t, ts.URL+"/params/base-gas-price")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var p params.Param
+	if err := json.Unmarshal(res, &p); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ablock.KeyBaseGasPrice, p.Key)
+	assert.Equal(t, math.HexOrDecimal256(*newGasPrice), *p.Value)
+	if assert.Equal(t, 2, len(p.History)) {
+		assert.Equal(t, math.HexOrDecimal256(*ablock.InitialBaseGasPrice), *p.History[0].Value)
+		assert.Equal(t, uint32(0), p.History[0].Meta.BlockNumber)
+		assert.Equal(t, math.HexOrDecimal256(*newGasPrice), *p.History[1].Value)
+		assert.Equal(t, uint32(1), p.History[1].Meta.BlockNumber)
+	}
+
+	// at genesis
+	res, statusCode = httpGet(t, ts.URL+"/params/"+ablock.KeyBaseGasPrice.String()+"?revision=0")
+	assert.Equal(t, http.StatusOK, statusCode)
+	if err := json.Unmarshal(res, &p); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, math.HexOrDecimal256(*ablock.InitialBaseGasPrice), *p.Value)
+	assert.Equal(t, 1, len(p.History))
+
+	_, statusCode = httpGet(t, ts.URL+"/params/base-gas-price?revision=abc")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+}
+
+func initParamsServer(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	gene := genesis.NewDevnet()
+
+	b, events, transfers, err := gene.Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _ := chain.NewRepository(db, b)
+
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := logDB.NewWriter()
+	if err := w.Write(b, tx.Receipts{{Outputs: []*tx.Output{{Events: events, Transfers: transfers}}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// devnet executor is the first dev account
+	method, _ := builtin.Params.ABI.MethodByName("set")
+	data, err := method.EncodeInput(ablock.KeyBaseGasPrice, newGasPrice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx := new(tx.Builder).
+		ChainTag(repo.ChainTag()).
+		Expiration(10).
+		Gas(1000000).
+		Clause(tx.NewClause(&builtin.Params.Address).WithData(data)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+
+	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, ablock.NoFork, ablock.DefaultConfig)
+	flow, err := packer.Schedule(repo.BestBlockSummary(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := flow.Adopt(trx); err != nil {
+		t.Fatal(err)
+	}
+	blk, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddBlock(blk, receipts, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetBestBlockID(blk.Header().ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(blk, receipts); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	params.New(repo, stater, logDB).Mount(router, "/params")
+	ts = httptest.NewServer(router)
+}
+
+func httpGet(t *testing.T, url string) ([]byte, int) {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
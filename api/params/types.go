@@ -0,0 +1,33 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package params
+
+import (
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// Param is the value of a governance param at a revision, with its change history.
+type Param struct {
+	Key     ablock.Bytes32        `json:"key"`
+	Name    string                `json:"name"`
+	Value   *math.HexOrDecimal256 `json:"value"`
+	History []*Change             `json:"history"`
+}
+
+// Change records a value set to the param.
+type Change struct {
+	Value *math.HexOrDecimal256 `json:"value"`
+	Meta  ChangeMeta            `json:"meta"`
+}
+
+type ChangeMeta struct {
+	BlockID        ablock.Bytes32 `json:"blockID"`
+	BlockNumber    uint32         `json:"blockNumber"`
+	BlockTimestamp uint64         `json:"blockTimestamp"`
+	TxID           ablock.Bytes32 `json:"txID"`
+	TxOrigin       ablock.Address `json:"txOrigin"`
+}
@@ -114,6 +114,50 @@ func (t *Transactions) getTransactionReceiptByID(txID ablock.Bytes32, head abloc
 
 	return convertReceipt(receipt, summary.Header, tx)
 }
+
+// getInclusionProof returns inclusion proofs of the tx and its receipt.
+func (t *Transactions) getInclusionProof(txID ablock.Bytes32, head ablock.Bytes32) (*InclusionProof, error) {
+	chain := t.repo.NewChain(head)
+	meta, err := chain.GetTransactionMeta(txID)
+	if err != nil {
+		if t.repo.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary, err := t.repo.GetBlockSummary(meta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := t.repo.GetBlockTransactions(meta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := t.repo.GetBlockReceipts(meta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	header, err := rlp.EncodeToBytes(summary.Header)
+	if err != nil {
+		return nil, err
+	}
+	txProof, err := txs.Prove(int(meta.Index))
+	if err != nil {
+		return nil, err
+	}
+	receiptProof, err := receipts.Prove(int(meta.Index))
+	if err != nil {
+		return nil, err
+	}
+	return &InclusionProof{
+		Header:       header,
+		Index:        meta.Index,
+		TxProof:      utils.HexProof(txProof),
+		ReceiptProof: utils.HexProof(receiptProof),
+	}, nil
+}
+
 func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Request) error {
 	var rawTx *RawTx
 	if err := utils.ParseJSON(req.Body, &rawTx); err != nil {
@@ -202,6 +246,29 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 	return utils.WriteJSON(w, receipt)
 }
 
+func (t *Transactions) handleGetInclusionProof(w http.ResponseWriter, req *http.Request) error {
+	txID, err := ablock.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	head, err := t.parseHead(req.URL.Query().Get("head"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "head"))
+	}
+	if _, err := t.repo.GetBlockSummary(head); err != nil {
+		if t.repo.IsNotFound(err) {
+			return utils.BadRequest(errors.WithMessage(err, "head"))
+		}
+		return err
+	}
+
+	proof, err := t.getInclusionProof(txID, head)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, proof)
+}
+
 func (t *Transactions) parseHead(head string) (ablock.Bytes32, error) {
 	if head == "" {
 		return t.repo.BestBlockSummary().Header.ID(), nil
@@ -219,4 +286,5 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransaction))
 	sub.Path("/{id}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionByID))
 	sub.Path("/{id}/receipt").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionReceiptByID))
+	sub.Path("/{id}/proof").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetInclusionProof))
 }
@@ -21,10 +21,12 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/ashishaw/authorityblock/api/transactions"
+	"github.com/ashishaw/authorityblock/block"
 	"github.com/ashishaw/authorityblock/chain"
 	"github.com/ashishaw/authorityblock/genesis"
 	"github.com/ashishaw/authorityblock/muxdb"
 	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/proof"
 	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
@@ -40,6 +42,7 @@ func TestTransaction(t *testing.T) {
 	defer ts.Close()
 	getTx(t)
 	getTxReceipt(t)
+	getInclusionProof(t)
 	senTx(t)
 }
 
@@ -72,6 +75,42 @@ func getTxReceipt(t *testing.T) {
 	assert.Equal(t, uint64(receipt.GasUsed), transaction.Gas(), "gas should be equal")
 }
 
+func getInclusionProof(t *testing.T) {
+	r := httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/proof")
+	var p *transactions.InclusionProof
+	if err := json.Unmarshal(r, &p); err != nil {
+		t.Fatal(err)
+	}
+	var header block.Header
+	if err := rlp.DecodeBytes(p.Header, &header); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, repo.BestBlockSummary().Header.ID(), header.ID())
+
+	trx, err := proof.VerifyTx(&header, p.Index, toBytes(p.TxProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, transaction.ID(), trx.ID())
+
+	receipt, err := proof.VerifyReceipt(&header, p.Index, toBytes(p.ReceiptProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, transaction.Gas(), receipt.GasUsed)
+
+	r = httpGet(t, ts.URL+"/transactions/"+ablock.BytesToBytes32([]byte("none")).String()+"/proof")
+	assert.Equal(t, "null\n", string(r))
+}
+
+func toBytes(hex []hexutil.Bytes) [][]byte {
+	b := make([][]byte, 0, len(hex))
+	for _, h := range hex {
+		b = append(b, h)
+	}
+	return b
+}
+
 func senTx(t *testing.T) {
 	var blockRef = tx.NewBlockRef(0)
 	var chainTag = repo.ChainTag()
@@ -146,7 +185,7 @@ func initTransactionServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	transaction = transaction.WithSignature(sig)
-	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, ablock.NoFork)
+	packer := packer.New(repo, stater, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address, ablock.NoFork, ablock.DefaultConfig)
 	sum, _ := repo.GetBlockSummary(b.Header().ID())
 	flow, err := packer.Schedule(sum, uint64(time.Now().Unix()))
 	if err != nil {
@@ -223,3 +223,13 @@ func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 	}
 	return receipt, nil
 }
+
+// InclusionProof for marshal inclusion proofs of a tx and its receipt.
+// The tx proof is against the txs root of the header, and the receipt proof
+// is against the receipts root.
+type InclusionProof struct {
+	Header       hexutil.Bytes   `json:"header"` // RLP encoded block header
+	Index        uint64          `json:"index"`  // index of the tx in the block
+	TxProof      []hexutil.Bytes `json:"txProof"`
+	ReceiptProof []hexutil.Bytes `json:"receiptProof"`
+}
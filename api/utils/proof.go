@@ -0,0 +1,17 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import "github.com/ethereum/go-ethereum/common/hexutil"
+
+// HexProof converts merkle proof nodes into hex form for JSON.
+func HexProof(proof [][]byte) []hexutil.Bytes {
+	hex := make([]hexutil.Bytes, 0, len(proof))
+	for _, n := range proof {
+		hex = append(hex, n)
+	}
+	return hex
+}
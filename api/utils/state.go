@@ -0,0 +1,25 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/state"
+)
+
+// CheckStateRetained returns http error 410 if the state of the block is pruned.
+func CheckStateRetained(stater *state.Stater, blockNum uint32) error {
+	retained, err := stater.IsRetained(blockNum)
+	if err != nil {
+		return err
+	}
+	if !retained {
+		return HTTPError(errors.Errorf("state of block %v pruned", blockNum), http.StatusGone)
+	}
+	return nil
+}
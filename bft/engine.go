@@ -31,6 +31,7 @@ type BFTEngine struct {
 	data       kv.Store
 	stater     *state.Stater
 	forkConfig ablock.ForkConfig
+	config     ablock.Config
 	master     ablock.Address
 	casts      casts
 	finalized  atomic.Value
@@ -42,12 +43,13 @@ type BFTEngine struct {
 }
 
 // NewEngine creates a new bft engine.
-func NewEngine(repo *chain.Repository, mainDB *muxdb.MuxDB, forkConfig ablock.ForkConfig, master ablock.Address) (*BFTEngine, error) {
+func NewEngine(repo *chain.Repository, mainDB *muxdb.MuxDB, forkConfig ablock.ForkConfig, config ablock.Config, master ablock.Address) (*BFTEngine, error) {
 	engine := BFTEngine{
 		repo:       repo,
 		data:       mainDB.NewStore(dataStoreName),
 		stater:     state.NewStater(mainDB),
 		forkConfig: forkConfig,
+		config:     config,
 		master:     master,
 	}
 
@@ -106,7 +108,7 @@ func (engine *BFTEngine) Select(header *block.Header) (bool, error) {
 // CommitBlock commits bft state to storage.
 func (engine *BFTEngine) CommitBlock(header *block.Header, isPacking bool) error {
 	// save quality and finalized at the end of each round
-	if getStorePoint(header.Number()) == header.Number() {
+	if engine.getStorePoint(header.Number()) == header.Number() {
 		state, err := engine.computeState(header)
 		if err != nil {
 			return err
@@ -137,7 +139,7 @@ func (engine *BFTEngine) CommitBlock(header *block.Header, isPacking bool) error
 			return err
 		}
 
-		checkpoint, err := engine.repo.NewChain(header.ID()).GetBlockID(getCheckPoint(header.Number()))
+		checkpoint, err := engine.repo.NewChain(header.ID()).GetBlockID(engine.getCheckPoint(header.Number()))
 		if err != nil {
 			return err
 		}
@@ -178,7 +180,7 @@ func (engine *BFTEngine) ShouldVote(parentID ablock.Bytes32) (bool, error) {
 	var recentJC ablock.Bytes32
 	if st.Justified {
 		// if justified in this round, use this round's checkpoint
-		checkpoint, err := engine.repo.NewChain(parentID).GetBlockID(getCheckPoint(block.Number(parentID)))
+		checkpoint, err := engine.repo.NewChain(parentID).GetBlockID(engine.getCheckPoint(block.Number(parentID)))
 		if err != nil {
 			return false, err
 		}
@@ -231,7 +233,7 @@ func (engine *BFTEngine) computeState(header *block.Header) (*bftState, error) {
 		end uint32
 	)
 
-	if entry := engine.caches.justifier.Remove(header.ParentID()); !isCheckPoint(header.Number()) && entry != nil {
+	if entry := engine.caches.justifier.Remove(header.ParentID()); !engine.isCheckPoint(header.Number()) && entry != nil {
 		js = interface{}(entry.Entry.Value).(*justifier)
 		end = header.Number()
 	} else {
@@ -281,19 +283,19 @@ func (engine *BFTEngine) findCheckpointByQuality(target uint32, finalized, paren
 
 	searchStart := block.Number(finalized)
 	if searchStart == 0 {
-		searchStart = getCheckPoint(engine.forkConfig.FINALITY)
+		searchStart = engine.getCheckPoint(engine.forkConfig.FINALITY)
 	}
 
 	c := engine.repo.NewChain(parentID)
 	get := func(i int) (uint32, error) {
-		id, err := c.GetBlockID(getStorePoint(searchStart + uint32(i)*ablock.CheckpointInterval))
+		id, err := c.GetBlockID(engine.getStorePoint(searchStart + uint32(i)*engine.config.CheckpointInterval))
 		if err != nil {
 			return 0, err
 		}
 		return engine.getQuality(id)
 	}
 
-	n := int((block.Number(parentID) + 1 - searchStart) / ablock.CheckpointInterval)
+	n := int((block.Number(parentID) + 1 - searchStart) / engine.config.CheckpointInterval)
 	num := sort.Search(n, func(i int) bool {
 		quality, err := get(i)
 		if err != nil {
@@ -316,7 +318,7 @@ func (engine *BFTEngine) findCheckpointByQuality(target uint32, finalized, paren
 		return ablock.Bytes32{}, errors.New("failed to find the block by quality")
 	}
 
-	return c.GetBlockID(searchStart + uint32(num)*ablock.CheckpointInterval)
+	return c.GetBlockID(searchStart + uint32(num)*engine.config.CheckpointInterval)
 }
 
 func (engine *BFTEngine) getMaxBlockProposers(sum *chain.BlockSummary) (uint64, error) {
@@ -347,15 +349,23 @@ func (engine *BFTEngine) getQuality(id ablock.Bytes32) (quality uint32, err erro
 	return loadQuality(engine.data, id)
 }
 
-func getCheckPoint(blockNum uint32) uint32 {
-	return blockNum / ablock.CheckpointInterval * ablock.CheckpointInterval
+func (engine *BFTEngine) getCheckPoint(blockNum uint32) uint32 {
+	return getCheckPoint(blockNum, engine.config.CheckpointInterval)
 }
 
-func isCheckPoint(blockNum uint32) bool {
-	return getCheckPoint(blockNum) == blockNum
+func (engine *BFTEngine) isCheckPoint(blockNum uint32) bool {
+	return engine.getCheckPoint(blockNum) == blockNum
+}
+
+func (engine *BFTEngine) getStorePoint(blockNum uint32) uint32 {
+	return getStorePoint(blockNum, engine.config.CheckpointInterval)
+}
+
+func getCheckPoint(blockNum, interval uint32) uint32 {
+	return blockNum / interval * interval
 }
 
 // save quality at the end of round
-func getStorePoint(blockNum uint32) uint32 {
-	return getCheckPoint(blockNum) + ablock.CheckpointInterval - 1
+func getStorePoint(blockNum, interval uint32) uint32 {
+	return getCheckPoint(blockNum, interval) + interval - 1
 }
@@ -92,7 +92,7 @@ func newTestBft(forkCfg ablock.ForkConfig) (*TestBFT, error) {
 		return nil, err
 	}
 
-	engine, err := NewEngine(repo, db, forkCfg, devAccounts[len(devAccounts)-1].Address)
+	engine, err := NewEngine(repo, db, forkCfg, ablock.DefaultConfig, devAccounts[len(devAccounts)-1].Address)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +113,7 @@ func newTestBft(forkCfg ablock.ForkConfig) (*TestBFT, error) {
 }
 
 func (test *TestBFT) reCreateEngine() error {
-	engine, err := NewEngine(test.repo, test.db, test.engine.forkConfig, devAccounts[len(devAccounts)-1].Address)
+	engine, err := NewEngine(test.repo, test.db, test.engine.forkConfig, ablock.DefaultConfig, devAccounts[len(devAccounts)-1].Address)
 	if err != nil {
 		return err
 	}
@@ -129,8 +129,8 @@ func (test *TestBFT) reCreateEngine() error {
 }
 
 func (test *TestBFT) newBlock(parentSummary *chain.BlockSummary, master genesis.DevAccount, shouldVote bool) (*chain.BlockSummary, error) {
-	packer := packer.New(test.repo, test.stater, master.Address, &ablock.Address{}, test.fc)
-	flow, err := packer.Mock(parentSummary, parentSummary.Header.Timestamp()+ablock.BlockInterval, parentSummary.Header.GasLimit())
+	packer := packer.New(test.repo, test.stater, master.Address, &ablock.Address{}, test.fc, ablock.DefaultConfig)
+	flow, err := packer.Mock(parentSummary, parentSummary.Header.Timestamp()+ablock.DefaultConfig.BlockInterval, parentSummary.Header.GasLimit())
 	if err != nil {
 		return nil, err
 	}
@@ -160,11 +160,11 @@ func (test *TestBFT) newBlock(parentSummary *chain.BlockSummary, master genesis.
 	return test.repo.GetBlockSummary(b.Header().ID())
 }
 
-func (test *TestBFT) fastForward(cnt int) error {
+func (test *TestBFT) fastForward(cnt uint32) error {
 	parent := test.repo.BestBlockSummary()
 
 	devCnt := len(devAccounts) - 1
-	for i := 1; i <= cnt; i++ {
+	for i := uint32(1); i <= cnt; i++ {
 		acc := devAccounts[(int(parent.Header.Number())+1)%devCnt]
 
 		var err error
@@ -177,11 +177,11 @@ func (test *TestBFT) fastForward(cnt int) error {
 	return test.repo.SetBestBlockID(parent.Header.ID())
 }
 
-func (test *TestBFT) fastForwardWithMinority(cnt int) error {
+func (test *TestBFT) fastForwardWithMinority(cnt uint32) error {
 	parent := test.repo.BestBlockSummary()
 
 	devCnt := len(devAccounts) - 1
-	for i := 1; i <= cnt; i++ {
+	for i := uint32(1); i <= cnt; i++ {
 		acc := devAccounts[(int(parent.Header.Number())+1)%(devCnt/3)]
 
 		var err error
@@ -251,7 +251,7 @@ func TestNewBlock(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err = testBFT.fastForward(ablock.CheckpointInterval - 1); err != nil {
+	if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval - 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -281,7 +281,7 @@ func TestNeverReachJustified(t *testing.T) {
 	}
 
 	genesisID := testBFT.repo.GenesisBlock().Header().ID()
-	if err := testBFT.fastForwardWithMinority(ablock.CheckpointInterval - 1); err != nil {
+	if err := testBFT.fastForwardWithMinority(ablock.DefaultConfig.CheckpointInterval - 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -295,7 +295,7 @@ func TestNeverReachJustified(t *testing.T) {
 	assert.Equal(t, genesisID, testBFT.engine.Finalized())
 
 	for i := 0; i < 3; i++ {
-		if err := testBFT.fastForwardWithMinority(ablock.CheckpointInterval); err != nil {
+		if err := testBFT.fastForwardWithMinority(ablock.DefaultConfig.CheckpointInterval); err != nil {
 			t.Fatal(err)
 		}
 
@@ -317,7 +317,7 @@ func TestReCreate(t *testing.T) {
 	}
 
 	genesisID := testBFT.repo.GenesisBlock().Header().ID()
-	if err := testBFT.fastForwardWithMinority(ablock.CheckpointInterval - 2); err != nil {
+	if err := testBFT.fastForwardWithMinority(ablock.DefaultConfig.CheckpointInterval - 2); err != nil {
 		t.Fatal(err)
 	}
 
@@ -326,7 +326,7 @@ func TestReCreate(t *testing.T) {
 	}
 	assert.Equal(t, genesisID, testBFT.engine.Finalized())
 
-	if err := testBFT.fastForwardWithMinority(ablock.CheckpointInterval*2 - 1); err != nil {
+	if err := testBFT.fastForwardWithMinority(ablock.DefaultConfig.CheckpointInterval*2 - 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -349,7 +349,7 @@ func TestFinalized(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err = testBFT.fastForward(ablock.CheckpointInterval*3 - 1); err != nil {
+	if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval*3 - 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -370,7 +370,7 @@ func TestFinalized(t *testing.T) {
 	assert.True(t, st.Justified)
 	assert.True(t, st.Committed)
 
-	blockNum = uint32(ablock.CheckpointInterval*2 + MaxBlockProposers*2/3)
+	blockNum = uint32(ablock.DefaultConfig.CheckpointInterval*2 + MaxBlockProposers*2/3)
 
 	sum, err = testBFT.repo.NewBestChain().GetBlockSummary(blockNum)
 	if err != nil {
@@ -388,7 +388,7 @@ func TestFinalized(t *testing.T) {
 	assert.True(t, st.Committed)
 
 	// chain stops the end of third bft round,should commit the second checkpoint
-	finalized, err := testBFT.repo.NewBestChain().GetBlockID(ablock.CheckpointInterval)
+	finalized, err := testBFT.repo.NewBestChain().GetBlockID(ablock.DefaultConfig.CheckpointInterval)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -402,7 +402,7 @@ func TestAccepts(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err = testBFT.fastForward(ablock.CheckpointInterval - 1); err != nil {
+	if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval - 1); err != nil {
 		t.Fatal(err)
 	}
 
@@ -411,7 +411,7 @@ func TestAccepts(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err = testBFT.fastForward(ablock.CheckpointInterval * 2); err != nil {
+	if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval * 2); err != nil {
 		t.Fatal(err)
 	}
 
@@ -420,7 +420,7 @@ func TestAccepts(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, ok, true)
 
-	branchID, err := branch.GetBlockID(ablock.CheckpointInterval)
+	branchID, err := branch.GetBlockID(ablock.DefaultConfig.CheckpointInterval)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -456,7 +456,7 @@ func TestGetVote(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				testBFT.fastForwardWithMinority(ablock.CheckpointInterval * 3)
+				testBFT.fastForwardWithMinority(ablock.DefaultConfig.CheckpointInterval * 3)
 				v, err := testBFT.engine.ShouldVote(testBFT.repo.BestBlockSummary().Header.ID())
 				if err != nil {
 					t.Fatal(err)
@@ -470,7 +470,7 @@ func TestGetVote(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				testBFT.fastForward(ablock.CheckpointInterval * 3)
+				testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval * 3)
 				v, err := testBFT.engine.ShouldVote(testBFT.repo.BestBlockSummary().Header.ID())
 				if err != nil {
 					t.Fatal(err)
@@ -484,7 +484,7 @@ func TestGetVote(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				if err = testBFT.fastForward(ablock.CheckpointInterval*3 - 1); err != nil {
+				if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval*3 - 1); err != nil {
 					t.Fatal(err)
 				}
 
@@ -529,7 +529,7 @@ func TestGetVote(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				if err = testBFT.fastForward(ablock.CheckpointInterval*3 - 1); err != nil {
+				if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval*3 - 1); err != nil {
 					t.Fatal(err)
 				}
 
@@ -591,7 +591,7 @@ func TestGetVote(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				if err = testBFT.fastForward(ablock.CheckpointInterval*3 - 1); err != nil {
+				if err = testBFT.fastForward(ablock.DefaultConfig.CheckpointInterval*3 - 1); err != nil {
 					t.Fatal(err)
 				}
 
@@ -662,7 +662,7 @@ func TestJustifier(t *testing.T) {
 		}, {
 			"fork in the middle of checkpoint", func(t *testing.T) {
 				fc := defaultFC
-				fc.VIP214 = ablock.CheckpointInterval / 2
+				fc.VIP214 = ablock.DefaultConfig.CheckpointInterval / 2
 				testBft, err := newTestBft(fc)
 				if err != nil {
 					t.Fatal(err)
@@ -678,19 +678,19 @@ func TestJustifier(t *testing.T) {
 		}, {
 			"the second bft round", func(t *testing.T) {
 				fc := defaultFC
-				fc.VIP214 = ablock.CheckpointInterval / 2
+				fc.VIP214 = ablock.DefaultConfig.CheckpointInterval / 2
 				testBft, err := newTestBft(fc)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				testBft.fastForward(ablock.CheckpointInterval * 2)
+				testBft.fastForward(ablock.DefaultConfig.CheckpointInterval * 2)
 				vs, err := testBft.engine.newJustifier(testBft.repo.BestBlockSummary().Header.ID())
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				assert.Equal(t, uint32(ablock.CheckpointInterval*2), vs.checkpoint)
+				assert.Equal(t, uint32(ablock.DefaultConfig.CheckpointInterval*2), vs.checkpoint)
 				assert.Equal(t, uint64(MaxBlockProposers*2/3), vs.threshold)
 				assert.Equal(t, uint32(2), vs.Summarize().Quality)
 				assert.False(t, vs.Summarize().Justified)
@@ -699,13 +699,13 @@ func TestJustifier(t *testing.T) {
 		}, {
 			"add votes: commits", func(t *testing.T) {
 				fc := defaultFC
-				fc.VIP214 = ablock.CheckpointInterval / 2
+				fc.VIP214 = ablock.DefaultConfig.CheckpointInterval / 2
 				testBft, err := newTestBft(fc)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				testBft.fastForward(ablock.CheckpointInterval*2 - 1)
+				testBft.fastForward(ablock.DefaultConfig.CheckpointInterval*2 - 1)
 				vs, err := testBft.engine.newJustifier(testBft.repo.BestBlockSummary().Header.ID())
 				if err != nil {
 					t.Fatal(err)
@@ -732,13 +732,13 @@ func TestJustifier(t *testing.T) {
 		}, {
 			"add votes: justifies", func(t *testing.T) {
 				fc := defaultFC
-				fc.VIP214 = ablock.CheckpointInterval / 2
+				fc.VIP214 = ablock.DefaultConfig.CheckpointInterval / 2
 				testBft, err := newTestBft(fc)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				testBft.fastForward(ablock.CheckpointInterval*2 - 1)
+				testBft.fastForward(ablock.DefaultConfig.CheckpointInterval*2 - 1)
 				vs, err := testBft.engine.newJustifier(testBft.repo.BestBlockSummary().Header.ID())
 				if err != nil {
 					t.Fatal(err)
@@ -758,13 +758,13 @@ func TestJustifier(t *testing.T) {
 		}, {
 			"add votes: one votes WIT then changes to COM", func(t *testing.T) {
 				fc := defaultFC
-				fc.VIP214 = ablock.CheckpointInterval / 2
+				fc.VIP214 = ablock.DefaultConfig.CheckpointInterval / 2
 				testBft, err := newTestBft(fc)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				testBft.fastForward(ablock.CheckpointInterval*2 - 1)
+				testBft.fastForward(ablock.DefaultConfig.CheckpointInterval*2 - 1)
 				vs, err := testBft.engine.newJustifier(testBft.repo.BestBlockSummary().Header.ID())
 				if err != nil {
 					t.Fatal(err)
@@ -0,0 +1,157 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+package bft
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/inconshreveable/log15"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/kv"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+const evidenceStoreName = "bft.evidence"
+
+var log = log15.New("pkg", "bft")
+
+// EvidenceKind describes the kind of misbehavior.
+type EvidenceKind string
+
+const (
+	// DoubleProposal two different blocks proposed by the same signer in the same slot.
+	DoubleProposal EvidenceKind = "double-proposal"
+	// ConflictingCOM two conflicting blocks at the same height with COM voted by the same signer.
+	ConflictingCOM EvidenceKind = "conflicting-com"
+)
+
+// Evidence is the proof of misbehavior of an authority node.
+// The two headers are both signed by the signer, so that anyone can verify it.
+type Evidence struct {
+	Kind    EvidenceKind
+	Signer  ablock.Address
+	Headers [2]*block.Header
+}
+
+// Watcher detects conflicting headers signed by the same master, and persists them as evidence.
+type Watcher struct {
+	repo  *chain.Repository
+	store kv.Store
+	alert bool
+}
+
+// NewWatcher creates a misbehavior watcher. If alert is true, a warning is logged once evidence found.
+func NewWatcher(repo *chain.Repository, mainDB *muxdb.MuxDB, alert bool) *Watcher {
+	return &Watcher{
+		repo:  repo,
+		store: mainDB.NewStore(evidenceStoreName),
+		alert: alert,
+	}
+}
+
+// Observe checks the given header against saved blocks with the same number,
+// and returns the newly found evidence.
+func (w *Watcher) Observe(header *block.Header) ([]*Evidence, error) {
+	if conflicts, err := w.repo.ScanConflicts(header.Number()); err != nil || conflicts == 0 {
+		return nil, err
+	}
+
+	signer, err := header.Signer()
+	if err != nil {
+		return nil, nil
+	}
+
+	ids, err := w.repo.ScanBlockIDs(header.Number())
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*Evidence
+	for _, id := range ids {
+		if id == header.ID() {
+			continue
+		}
+		sum, err := w.repo.GetBlockSummary(id)
+		if err != nil {
+			return nil, err
+		}
+		other := sum.Header
+		if s, err := other.Signer(); err != nil || s != signer {
+			continue
+		}
+
+		var kind EvidenceKind
+		switch {
+		case other.Timestamp() == header.Timestamp():
+			kind = DoubleProposal
+		case other.COM() && header.COM():
+			kind = ConflictingCOM
+		default:
+			continue
+		}
+
+		ev := &Evidence{Kind: kind, Signer: signer, Headers: [2]*block.Header{other, header}}
+		saved, err := w.save(ev)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			if w.alert {
+				log.Warn("misbehavior detected", "kind", kind, "signer", signer, "number", header.Number(), "block1", other.ID(), "block2", header.ID())
+			}
+			found = append(found, ev)
+		}
+	}
+	return found, nil
+}
+
+// save saves the evidence, returns false if it already exists.
+func (w *Watcher) save(ev *Evidence) (bool, error) {
+	key := evidenceKey(ev)
+	if has, err := w.store.Has(key); err != nil || has {
+		return false, err
+	}
+	data, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		return false, err
+	}
+	if err := w.store.Put(key, data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Evidences returns all saved evidence in ascending order of block number.
+func (w *Watcher) Evidences() ([]*Evidence, error) {
+	iter := w.store.Iterate(kv.Range{})
+	defer iter.Release()
+
+	var list []*Evidence
+	for iter.Next() {
+		var ev Evidence
+		if err := rlp.DecodeBytes(iter.Value(), &ev); err != nil {
+			return nil, err
+		}
+		list = append(list, &ev)
+	}
+	return list, iter.Error()
+}
+
+// evidenceKey is the block number followed by the hash of the ordered header IDs,
+// which makes the key independent of the observing order.
+func evidenceKey(ev *Evidence) []byte {
+	a, b := ev.Headers[0].ID(), ev.Headers[1].ID()
+	if string(a[:]) > string(b[:]) {
+		a, b = b, a
+	}
+	var key [36]byte
+	binary.BigEndian.PutUint32(key[:], ev.Headers[0].Number())
+	h := ablock.Blake2b(a[:], b[:])
+	copy(key[4:], h[:])
+	return key[:]
+}
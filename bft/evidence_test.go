@@ -0,0 +1,120 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+package bft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+func (test *TestBFT) mockBlock(parent *chain.BlockSummary, master genesis.DevAccount, timestamp uint64, gasLimit uint64, shouldVote bool) (*block.Header, error) {
+	packer := packer.New(test.repo, test.stater, master.Address, &ablock.Address{}, test.fc, ablock.DefaultConfig)
+	flow, err := packer.Mock(parent, timestamp, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := test.repo.ScanConflicts(parent.Header.Number() + 1)
+	if err != nil {
+		return nil, err
+	}
+	b, stg, _, err := flow.Pack(master.PrivateKey, conflicts, shouldVote)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stg.Commit(); err != nil {
+		return nil, err
+	}
+	if err = test.repo.AddBlock(b, nil, conflicts); err != nil {
+		return nil, err
+	}
+	return b.Header(), nil
+}
+
+func TestWatcher(t *testing.T) {
+	test, err := newTestBft(defaultFC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(test.repo, test.db, false)
+
+	genesis := test.repo.BestBlockSummary()
+	ts := genesis.Header.Timestamp() + ablock.DefaultConfig.BlockInterval
+	gl := genesis.Header.GasLimit()
+
+	h1, err := test.mockBlock(genesis, devAccounts[0], ts, gl, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evs, err := w.Observe(h1)
+	assert.Nil(t, err)
+	assert.Empty(t, evs, "no conflicts")
+
+	// another signer at the same slot is not misbehavior of devAccounts[0]
+	h2, err := test.mockBlock(genesis, devAccounts[1], ts, gl, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evs, err = w.Observe(h2)
+	assert.Nil(t, err)
+	assert.Empty(t, evs)
+
+	// same signer, same slot
+	h3, err := test.mockBlock(genesis, devAccounts[0], ts, gl+1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evs, err = w.Observe(h3)
+	assert.Nil(t, err)
+	if assert.Len(t, evs, 1) {
+		assert.Equal(t, DoubleProposal, evs[0].Kind)
+		assert.Equal(t, devAccounts[0].Address, evs[0].Signer)
+		assert.Equal(t, h1.ID(), evs[0].Headers[0].ID())
+		assert.Equal(t, h3.ID(), evs[0].Headers[1].ID())
+	}
+
+	// already recorded
+	evs, err = w.Observe(h1)
+	assert.Nil(t, err)
+	assert.Empty(t, evs)
+
+	// same signer, different slots, both voted COM
+	h4, err := test.mockBlock(genesis, devAccounts[1], ts+ablock.DefaultConfig.BlockInterval, gl, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evs, err = w.Observe(h4)
+	assert.Nil(t, err)
+	if assert.Len(t, evs, 1) {
+		assert.Equal(t, ConflictingCOM, evs[0].Kind)
+		assert.Equal(t, devAccounts[1].Address, evs[0].Signer)
+	}
+
+	// different slots without COM
+	h5, err := test.mockBlock(genesis, devAccounts[0], ts+ablock.DefaultConfig.BlockInterval*2, gl, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evs, err = w.Observe(h5)
+	assert.Nil(t, err)
+	assert.Empty(t, evs)
+
+	all, err := w.Evidences()
+	assert.Nil(t, err)
+	if assert.Len(t, all, 2) {
+		for _, ev := range all {
+			for _, h := range ev.Headers {
+				signer, err := h.Signer()
+				assert.Nil(t, err)
+				assert.Equal(t, ev.Signer, signer)
+			}
+		}
+	}
+}
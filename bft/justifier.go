@@ -30,7 +30,7 @@ func (engine *BFTEngine) newJustifier(parentID ablock.Bytes32) (*justifier, erro
 	blockNum := block.Number(parentID) + 1
 
 	var lastOfParentRound uint32
-	checkpoint := getCheckPoint(blockNum)
+	checkpoint := engine.getCheckPoint(blockNum)
 	if checkpoint > 0 {
 		lastOfParentRound = checkpoint - 1
 	} else {
@@ -48,7 +48,7 @@ func (engine *BFTEngine) newJustifier(parentID ablock.Bytes32) (*justifier, erro
 	threshold := mbp * 2 / 3
 
 	var parentQuality uint32 // quality of last round
-	if absRound := blockNum/ablock.CheckpointInterval - engine.forkConfig.FINALITY/ablock.CheckpointInterval; absRound == 0 {
+	if absRound := blockNum/engine.config.CheckpointInterval - engine.forkConfig.FINALITY/engine.config.CheckpointInterval; absRound == 0 {
 		parentQuality = 0
 	} else {
 		var err error
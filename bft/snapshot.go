@@ -0,0 +1,64 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+package bft
+
+import (
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// Quality is the quality saved at the end of a bft round.
+type Quality struct {
+	BlockID ablock.Bytes32
+	Quality uint32
+}
+
+// IsStorePoint returns whether the quality is saved at the given block.
+// The state of a store point block is enough to continue computing bft state for later blocks.
+func IsStorePoint(blockNum, checkpointInterval uint32) bool {
+	return getStorePoint(blockNum, checkpointInterval) == blockNum
+}
+
+// ExportQualities returns saved qualities of rounds on the chain of the given head block.
+// Rounds whose blocks are not indexed, e.g. before the base block of a bootstrapped chain, are skipped.
+func ExportQualities(repo *chain.Repository, mainDB *muxdb.MuxDB, headID ablock.Bytes32, checkpointInterval uint32) ([]*Quality, error) {
+	var (
+		data      = mainDB.NewStore(dataStoreName)
+		c         = repo.NewChain(headID)
+		qualities []*Quality
+	)
+
+	for num := getStorePoint(0, checkpointInterval); num <= block.Number(headID); num += checkpointInterval {
+		id, err := c.GetBlockID(num)
+		if err != nil {
+			if repo.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		quality, err := loadQuality(data, id)
+		if err != nil {
+			if data.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		qualities = append(qualities, &Quality{id, quality})
+	}
+	return qualities, nil
+}
+
+// ImportQualities saves qualities exported by ExportQualities.
+func ImportQualities(mainDB *muxdb.MuxDB, qualities []*Quality) error {
+	bulk := mainDB.NewStore(dataStoreName).Bulk()
+	for _, q := range qualities {
+		if err := saveQuality(bulk, q.BlockID, q.Quality); err != nil {
+			return err
+		}
+	}
+	return bulk.Write()
+}
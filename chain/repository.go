@@ -16,6 +16,7 @@ import (
 	"github.com/ashishaw/authorityblock/kv"
 	"github.com/ashishaw/authorityblock/muxdb"
 	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/trie"
 	"github.com/ashishaw/authorityblock/tx"
 )
 
@@ -271,6 +272,78 @@ func (r *Repository) AddBlock(newBlock *block.Block, receipts tx.Receipts, confl
 	return nil
 }
 
+// ImportBaseBlock adds a block without its ancestors, which is used to bootstrap the repository from a snapshot.
+// Ids of ancestors are required to build the block index, and are fetched by ancestorID in ascending order of
+// block number. A zero id means the ancestor is unknown and left unindexed, otherwise the id of the parent must
+// match the parent id of the base block. Blocks after the base block can be added by AddBlock afterwards.
+func (r *Repository) ImportBaseBlock(
+	baseBlock *block.Block,
+	receipts tx.Receipts,
+	conflicts uint32,
+	ancestorID func(num uint32) (ablock.Bytes32, error),
+) error {
+	if r.BestBlockSummary().Header.Number() != 0 {
+		return errors.New("repository not empty")
+	}
+
+	const batchSize = 100000
+	var (
+		header    = baseBlock.Header()
+		baseNum   = header.Number()
+		indexTrie = r.db.NewNonCryptoTrie(IndexTrieName, trie.NonCryptoNodeHash, 0, 0)
+	)
+	if baseNum == 0 {
+		return errors.New("genesis block can't be imported")
+	}
+
+	var parentID ablock.Bytes32
+	if baseNum == 1 {
+		parentID = r.genesis.Header().ID()
+	}
+	for num := uint32(1); num < baseNum; num++ {
+		id, err := ancestorID(num)
+		if err != nil {
+			return err
+		}
+		if !id.IsZero() {
+			if block.Number(id) != num {
+				return errors.New("ancestor id mismatch")
+			}
+			if err := indexTrie.Update(id[:4], id[:], nil); err != nil {
+				return err
+			}
+		}
+		if num == baseNum-1 {
+			parentID = id
+		}
+		// intermediate commits to bound memory usage, and they are never referenced by block summaries.
+		if num%batchSize == 0 {
+			_, commit := indexTrie.Stage(num, 0)
+			if err := commit(); err != nil {
+				return err
+			}
+		}
+	}
+	if !parentID.IsZero() && header.ParentID() != parentID {
+		return errors.New("parent id mismatch")
+	}
+	id := header.ID()
+	if err := indexTrie.Update(id[:4], id[:], nil); err != nil {
+		return err
+	}
+	_, commit := indexTrie.Stage(baseNum, conflicts)
+	if err := commit(); err != nil {
+		return err
+	}
+
+	// the genesis block is no longer a chain head
+	if err := r.head.Delete(r.genesis.Header().ID().Bytes()); err != nil {
+		return err
+	}
+	_, err := r.saveBlock(baseBlock, receipts, conflicts, 0)
+	return err
+}
+
 // ScanConflicts returns the count of saved blocks with the given blockNum.
 func (r *Repository) ScanConflicts(blockNum uint32) (uint32, error) {
 	var prefix [4]byte
@@ -288,6 +361,23 @@ func (r *Repository) ScanConflicts(blockNum uint32) (uint32, error) {
 	return count, iter.Error()
 }
 
+// ScanBlockIDs returns IDs of saved blocks with the given blockNum.
+func (r *Repository) ScanBlockIDs(blockNum uint32) ([]ablock.Bytes32, error) {
+	var prefix [4]byte
+	binary.BigEndian.PutUint32(prefix[:], blockNum)
+
+	iter := r.data.Iterate(kv.Range(*util.BytesPrefix(prefix[:])))
+	defer iter.Release()
+
+	var ids []ablock.Bytes32
+	for iter.Next() {
+		if len(iter.Key()) == 32 {
+			ids = append(ids, ablock.BytesToBytes32(iter.Key()))
+		}
+	}
+	return ids, iter.Error()
+}
+
 // ScanHeads returns all head blockIDs from the given blockNum(included) in descending order.
 func (r *Repository) ScanHeads(from uint32) ([]ablock.Bytes32, error) {
 	var start [4]byte
@@ -114,6 +114,10 @@ func TestConflicts(t *testing.T) {
 	repo.AddBlock(b1x, nil, 1)
 	assert.Equal(t, []interface{}{uint32(1), nil}, M(repo.GetMaxBlockNum()))
 	assert.Equal(t, []interface{}{uint32(2), nil}, M(repo.ScanConflicts(1)))
+
+	ids, err := repo.ScanBlockIDs(1)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []ablock.Bytes32{b1.Header().ID(), b1x.Header().ID()}, ids)
 }
 
 func TestSteadyBlockID(t *testing.T) {
@@ -213,3 +217,31 @@ func TestScanHeads(t *testing.T) {
 		assert.Equal(t, []ablock.Bytes32{b3x.Header().ID(), b3.Header().ID(), b2x.Header().ID()}, heads)
 	}
 }
+
+func TestImportBaseBlock(t *testing.T) {
+	_, src := newTestRepo()
+	b1 := newBlock(src.GenesisBlock(), 10)
+	b2 := newBlock(b1, 20)
+	b3 := newBlock(b2, 30)
+
+	ancestors := func(ids ...ablock.Bytes32) func(num uint32) (ablock.Bytes32, error) {
+		return func(num uint32) (ablock.Bytes32, error) {
+			return ids[num-1], nil
+		}
+	}
+
+	// parent id mismatch
+	_, repo := newTestRepo()
+	assert.Error(t, repo.ImportBaseBlock(b3, nil, 0, ancestors(b1.Header().ID(), b3.Header().ID())))
+
+	// unknown ancestors are not checked
+	_, repo = newTestRepo()
+	assert.Nil(t, repo.ImportBaseBlock(b3, nil, 0, ancestors(ablock.Bytes32{}, ablock.Bytes32{})))
+
+	_, repo = newTestRepo()
+	assert.Nil(t, repo.ImportBaseBlock(b3, nil, 0, ancestors(b1.Header().ID(), b2.Header().ID())))
+	assert.Nil(t, repo.SetBestBlockID(b3.Header().ID()))
+	id, err := repo.NewBestChain().GetBlockID(2)
+	assert.Nil(t, err)
+	assert.Equal(t, b2.Header().ID(), id)
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/abi"
+	"github.com/ashishaw/authorityblock/api/transactions"
+	"github.com/ashishaw/authorityblock/builtin"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var authorityCommand = cli.Command{
+	Name:  "authority",
+	Usage: "manage authority nodes through the builtin Authority contract",
+	Subcommands: []cli.Command{
+		{
+			Name:   "list",
+			Usage:  "list candidates of block proposers",
+			Flags:  []cli.Flag{nodeURLFlag, revisionFlag},
+			Action: authorityListAction,
+		},
+		{
+			Name:      "add",
+			Usage:     "propose to add a candidate",
+			ArgsUsage: "<node-master> <endorsor> <identity>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityAddAction,
+		},
+		{
+			Name:      "revoke",
+			Usage:     "propose to revoke a candidate",
+			ArgsUsage: "<node-master>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityRevokeAction,
+		},
+		{
+			Name:      "propose",
+			Usage:     "raise an executor proposal with arbitrary target and call data",
+			ArgsUsage: "<target> <data>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityProposeAction,
+		},
+		{
+			Name:      "approve",
+			Usage:     "approve an executor proposal",
+			ArgsUsage: "<proposal-id>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityApproveAction,
+		},
+		{
+			Name:      "execute",
+			Usage:     "execute an executor proposal which has reached the quorum",
+			ArgsUsage: "<proposal-id>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityExecuteAction,
+		},
+	},
+}
+
+// mustEncodeInput encodes the call data of the named method of a builtin contract.
+func mustEncodeInput(contractABI *abi.ABI, name string, args ...interface{}) []byte {
+	method, found := contractABI.MethodByName(name)
+	if !found {
+		panic("method not found: " + name)
+	}
+	data, err := method.EncodeInput(args...)
+	if err != nil {
+		panic(errors.Wrap(err, "encode input of "+name))
+	}
+	return data
+}
+
+// callBuiltin calls a constant method of a builtin contract and decodes the output into v.
+func callBuiltin(node *remoteNode, revision string, to ablock.Address, contractABI *abi.ABI, v interface{}, name string, args ...interface{}) error {
+	clause := tx.NewClause(&to).WithData(mustEncodeInput(contractABI, name, args...))
+	results, err := node.call(revision, nil, []*tx.Clause{clause})
+	if err != nil {
+		return err
+	}
+	if results[0].Reverted {
+		return fmt.Errorf("call %v reverted: %v", name, results[0].VMError)
+	}
+	output, err := hexutil.Decode(results[0].Data)
+	if err != nil {
+		return err
+	}
+	method, _ := contractABI.MethodByName(name)
+	return method.DecodeOutput(output, v)
+}
+
+func authorityListAction(ctx *cli.Context) error {
+	node := newRemoteNode(ctx)
+	// pin the revision, to get a consistent view of the linked list
+	best, err := node.block(ctx.String(revisionFlag.Name))
+	if err != nil {
+		return err
+	}
+	revision := best.ID.String()
+	authority := builtin.Authority
+
+	var ptr common.Address
+	if err := callBuiltin(node, revision, authority.Address, authority.ABI, &ptr, "first"); err != nil {
+		return err
+	}
+
+	fmt.Printf("Candidates at block #%v %v\n", best.Number, best.ID)
+	for n := 0; ptr != (common.Address{}); n++ {
+		var entry struct {
+			Listed   bool
+			Endorsor common.Address
+			Identity common.Hash
+			Active   bool
+		}
+		if err := callBuiltin(node, revision, authority.Address, authority.ABI, &entry, "get", ptr); err != nil {
+			return err
+		}
+		fmt.Printf("%4d  master %v  endorsor %v  identity %v  active %v\n",
+			n+1,
+			ablock.Address(ptr),
+			ablock.Address(entry.Endorsor),
+			ablock.Bytes32(entry.Identity),
+			entry.Active)
+
+		if err := callBuiltin(node, revision, authority.Address, authority.ABI, &ptr, "next", ptr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func authorityAddAction(ctx *cli.Context) error {
+	if ctx.NArg() != 3 {
+		return errors.New("requires args <node-master> <endorsor> <identity>")
+	}
+	nodeMaster, err := ablock.ParseAddress(ctx.Args().Get(0))
+	if err != nil {
+		return errors.WithMessage(err, "node master")
+	}
+	endorsor, err := ablock.ParseAddress(ctx.Args().Get(1))
+	if err != nil {
+		return errors.WithMessage(err, "endorsor")
+	}
+	identity, err := ablock.ParseBytes32(ctx.Args().Get(2))
+	if err != nil {
+		return errors.WithMessage(err, "identity")
+	}
+	data := mustEncodeInput(builtin.Authority.ABI, "add",
+		common.Address(nodeMaster), common.Address(endorsor), common.Hash(identity))
+	return propose(ctx, builtin.Authority.Address, data)
+}
+
+func authorityRevokeAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("requires arg <node-master>")
+	}
+	nodeMaster, err := ablock.ParseAddress(ctx.Args().First())
+	if err != nil {
+		return errors.WithMessage(err, "node master")
+	}
+	data := mustEncodeInput(builtin.Authority.ABI, "revoke", common.Address(nodeMaster))
+	return propose(ctx, builtin.Authority.Address, data)
+}
+
+func authorityProposeAction(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("requires args <target> <data>")
+	}
+	target, err := ablock.ParseAddress(ctx.Args().Get(0))
+	if err != nil {
+		return errors.WithMessage(err, "target")
+	}
+	data, err := hexutil.Decode(ctx.Args().Get(1))
+	if err != nil {
+		return errors.WithMessage(err, "data")
+	}
+	return propose(ctx, target, data)
+}
+
+func authorityApproveAction(ctx *cli.Context) error {
+	return proposalAction(ctx, "approve")
+}
+
+func authorityExecuteAction(ctx *cli.Context) error {
+	return proposalAction(ctx, "execute")
+}
+
+func proposalAction(ctx *cli.Context, method string) error {
+	if ctx.NArg() != 1 {
+		return errors.New("requires arg <proposal-id>")
+	}
+	proposalID, err := ablock.ParseBytes32(ctx.Args().First())
+	if err != nil {
+		return errors.WithMessage(err, "proposal id")
+	}
+	data := mustEncodeInput(builtin.Executor.ABI, method, common.Hash(proposalID))
+	_, err = sendExecutorClause(ctx, data)
+	return err
+}
+
+// propose raises a proposal to the executor and prints the id of the proposal.
+func propose(ctx *cli.Context, target ablock.Address, data []byte) error {
+	receipt, err := sendExecutorClause(ctx,
+		mustEncodeInput(builtin.Executor.ABI, "propose", common.Address(target), data))
+	if err != nil {
+		return err
+	}
+
+	ev, _ := builtin.Executor.ABI.EventByName("Proposal")
+	for _, o := range receipt.Outputs {
+		for _, e := range o.Events {
+			if e.Address == builtin.Executor.Address && len(e.Topics) > 1 && e.Topics[0] == ev.ID() {
+				fmt.Println("Proposal ID:", e.Topics[1])
+				return nil
+			}
+		}
+	}
+	return errors.New("proposal event not found in receipt")
+}
+
+// sendExecutorClause signs and sends a tx which calls the executor, and waits for it to be packed.
+func sendExecutorClause(ctx *cli.Context, data []byte) (*transactions.Receipt, error) {
+	key, err := loadKeystore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	origin := ablock.Address(crypto.PubkeyToAddress(key.PublicKey))
+
+	node := newRemoteNode(ctx)
+	trx, err := node.buildTx(origin, []*tx.Clause{
+		tx.NewClause(&builtin.Executor.Address).WithData(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	txID, err := node.signAndSend(trx, key)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Transaction sent:", txID)
+
+	receipt, err := node.waitReceipt(txID, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Reverted {
+		return nil, fmt.Errorf("tx %v reverted", txID)
+	}
+	fmt.Printf("Transaction packed in block #%v %v\n", receipt.Meta.BlockNumber, receipt.Meta.BlockID)
+	return receipt, nil
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package blockfile exports a range of blocks along with receipts into a portable stream,
+// and imports it into a node with full consensus validation.
+//
+// Two formats are supported. The RLP format is a stream of RLP items, the NDJSON format
+// is a stream of JSON lines with RLP encoded block and receipts in hex. Both in the order of:
+//
+//	header, blocks from the first to the last.
+package blockfile
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/consensus"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+const version = 1
+
+// supported formats
+const (
+	FormatRLP    = "rlp"
+	FormatNDJSON = "ndjson"
+)
+
+type header struct {
+	Version   uint           `json:"version"`
+	GenesisID ablock.Bytes32 `json:"genesisID"`
+	From      uint32         `json:"from"`
+	To        uint32         `json:"to"`
+}
+
+type blockEntry struct {
+	Block    *block.Block
+	Receipts tx.Receipts
+}
+
+// jsonBlockEntry is the NDJSON form of blockEntry. Number and id are informational.
+type jsonBlockEntry struct {
+	Number   uint32         `json:"number"`
+	ID       ablock.Bytes32 `json:"id"`
+	Block    hexutil.Bytes  `json:"block"`
+	Receipts hexutil.Bytes  `json:"receipts"`
+}
+
+type encoder interface {
+	encodeHeader(h *header) error
+	encodeBlock(entry *blockEntry) error
+}
+
+type decoder interface {
+	decodeHeader(h *header) error
+	decodeBlock(entry *blockEntry) error
+}
+
+type rlpEncoder struct{ w io.Writer }
+
+func (e *rlpEncoder) encodeHeader(h *header) error        { return rlp.Encode(e.w, h) }
+func (e *rlpEncoder) encodeBlock(entry *blockEntry) error { return rlp.Encode(e.w, entry) }
+
+type rlpDecoder struct{ s *rlp.Stream }
+
+func (d *rlpDecoder) decodeHeader(h *header) error        { return d.s.Decode(h) }
+func (d *rlpDecoder) decodeBlock(entry *blockEntry) error { return d.s.Decode(entry) }
+
+type jsonEncoder struct{ enc *json.Encoder }
+
+func (e *jsonEncoder) encodeHeader(h *header) error { return e.enc.Encode(h) }
+
+func (e *jsonEncoder) encodeBlock(entry *blockEntry) error {
+	b, err := rlp.EncodeToBytes(entry.Block)
+	if err != nil {
+		return err
+	}
+	receipts, err := rlp.EncodeToBytes(entry.Receipts)
+	if err != nil {
+		return err
+	}
+	return e.enc.Encode(&jsonBlockEntry{
+		Number:   entry.Block.Header().Number(),
+		ID:       entry.Block.Header().ID(),
+		Block:    b,
+		Receipts: receipts,
+	})
+}
+
+type jsonDecoder struct{ dec *json.Decoder }
+
+func (d *jsonDecoder) decodeHeader(h *header) error { return d.dec.Decode(h) }
+
+func (d *jsonDecoder) decodeBlock(entry *blockEntry) error {
+	var je jsonBlockEntry
+	if err := d.dec.Decode(&je); err != nil {
+		return err
+	}
+	if err := rlp.DecodeBytes(je.Block, &entry.Block); err != nil {
+		return errors.Wrap(err, "block")
+	}
+	if err := rlp.DecodeBytes(je.Receipts, &entry.Receipts); err != nil {
+		return errors.Wrap(err, "receipts")
+	}
+	return nil
+}
+
+// Export writes blocks in range [from, to] on the chain of the given head block into w.
+// The progress callback, if not nil, is called after each block written.
+func Export(
+	ctx context.Context,
+	w io.Writer,
+	repo *chain.Repository,
+	headID ablock.Bytes32,
+	from, to uint32,
+	format string,
+	progress func(num uint32),
+) error {
+	if from == 0 || from > to {
+		return errors.Errorf("invalid block range [%v, %v]", from, to)
+	}
+	if to > block.Number(headID) {
+		return errors.Errorf("block %v is beyond the head block", to)
+	}
+
+	var enc encoder
+	switch format {
+	case FormatRLP:
+		enc = &rlpEncoder{w}
+	case FormatNDJSON:
+		enc = &jsonEncoder{json.NewEncoder(w)}
+	default:
+		return errors.Errorf("unsupported format %q", format)
+	}
+
+	if err := enc.encodeHeader(&header{
+		Version:   version,
+		GenesisID: repo.GenesisBlock().Header().ID(),
+		From:      from,
+		To:        to,
+	}); err != nil {
+		return err
+	}
+
+	c := repo.NewChain(headID)
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := c.GetBlock(n)
+		if err != nil {
+			return errors.WithMessagef(err, "get block %v", n)
+		}
+		receipts, err := repo.GetBlockReceipts(b.Header().ID())
+		if err != nil {
+			return errors.WithMessagef(err, "get receipts %v", n)
+		}
+		if err := enc.encodeBlock(&blockEntry{b, receipts}); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(n)
+		}
+	}
+	return nil
+}
+
+// Import reads blocks from r and processes them one by one on top of the best block, as they were received
+// from peers. The format is detected automatically. Blocks already on the best chain are skipped, so that an
+// interrupted import can be resumed. The progress callback, if not nil, is called after each block imported.
+// It returns the best block after importing.
+func Import(
+	ctx context.Context,
+	r io.Reader,
+	repo *chain.Repository,
+	stater *state.Stater,
+	engine *bft.BFTEngine,
+	forkConfig ablock.ForkConfig,
+	config ablock.Config,
+	progress func(num uint32),
+) (*chain.BlockSummary, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1)
+	if err != nil {
+		return nil, errors.Wrap(err, "read header")
+	}
+	var dec decoder
+	if first[0] == '{' {
+		dec = &jsonDecoder{json.NewDecoder(br)}
+	} else {
+		dec = &rlpDecoder{rlp.NewStream(br, 0)}
+	}
+
+	var h header
+	if err := dec.decodeHeader(&h); err != nil {
+		return nil, errors.Wrap(err, "decode header")
+	}
+	if h.Version != version {
+		return nil, errors.Errorf("unsupported version %v", h.Version)
+	}
+	if h.GenesisID != repo.GenesisBlock().Header().ID() {
+		return nil, errors.New("genesis mismatch")
+	}
+	if h.From == 0 || h.From > h.To {
+		return nil, errors.New("invalid block range")
+	}
+
+	var (
+		cons      = consensus.New(repo, stater, forkConfig, config)
+		best      = repo.BestBlockSummary()
+		bestChain = repo.NewBestChain()
+	)
+	if h.From > best.Header.Number()+1 {
+		return nil, errors.Errorf("missing blocks between the best block %v and the first block %v", best.Header.Number(), h.From)
+	}
+
+	for n := h.From; n <= h.To; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var entry blockEntry
+		if err := dec.decodeBlock(&entry); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, errors.Wrapf(err, "decode block %v", n)
+		}
+		header := entry.Block.Header()
+		if header.Number() != n {
+			return nil, errors.Errorf("block number mismatch, want %v, got %v", n, header.Number())
+		}
+		if header.ReceiptsRoot() != entry.Receipts.RootHash() {
+			return nil, errors.Errorf("block %v: receipts root mismatch", n)
+		}
+
+		if n <= best.Header.Number() {
+			id, err := bestChain.GetBlockID(n)
+			if err != nil {
+				return nil, err
+			}
+			if id != header.ID() {
+				return nil, errors.Errorf("block %v conflicts with the best chain", n)
+			}
+			continue
+		}
+
+		summary, err := processBlock(repo, cons, engine, forkConfig, best, entry.Block)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "block %v", n)
+		}
+		best = summary
+		if progress != nil {
+			progress(n)
+		}
+	}
+	return best, nil
+}
+
+// processBlock validates and executes the block on top of the parent, then makes it the new best block.
+// The block already known, e.g. after a rewind, is executed again rather than trusted.
+func processBlock(
+	repo *chain.Repository,
+	cons *consensus.Consensus,
+	engine *bft.BFTEngine,
+	forkConfig ablock.ForkConfig,
+	parent *chain.BlockSummary,
+	blk *block.Block,
+) (*chain.BlockSummary, error) {
+	header := blk.Header()
+	if header.ParentID() != parent.Header.ID() {
+		return nil, errors.New("parent mismatch")
+	}
+	if ok, err := engine.Accepts(header.ParentID()); err != nil {
+		return nil, errors.Wrap(err, "bft accepts")
+	} else if !ok {
+		return nil, errors.New("rejected by bft")
+	}
+
+	conflicts, err := repo.ScanConflicts(header.Number())
+	if err != nil {
+		return nil, err
+	}
+	// the block rewound away from the best chain is processed again, keeping its index
+	if summary, err := repo.GetBlockSummary(header.ID()); err == nil {
+		conflicts = summary.Conflicts
+	} else if !repo.IsNotFound(err) {
+		return nil, err
+	}
+	stage, receipts, err := cons.Process(parent, blk, uint64(time.Now().Unix()), conflicts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stage.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit state")
+	}
+	if err := repo.AddBlock(blk, receipts, conflicts); err != nil {
+		return nil, errors.Wrap(err, "add block")
+	}
+	if header.Number() >= forkConfig.FINALITY {
+		if err := engine.CommitBlock(header, false); err != nil {
+			return nil, errors.Wrap(err, "bft commits")
+		}
+	}
+	if err := repo.SetBestBlockID(header.ID()); err != nil {
+		return nil, err
+	}
+	return repo.GetBlockSummary(header.ID())
+}
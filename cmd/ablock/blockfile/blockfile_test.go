@@ -0,0 +1,199 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package blockfile
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+type testNode struct {
+	db     *muxdb.MuxDB
+	repo   *chain.Repository
+	stater *state.Stater
+}
+
+func newTestNode(t *testing.T) *testNode {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testNode{db, repo, stater}
+}
+
+func (n *testNode) packBlock(t *testing.T, txs ...*tx.Transaction) {
+	master := genesis.DevAccounts()[0]
+	best := n.repo.BestBlockSummary()
+	flow, err := packer.New(n.repo, n.stater, master.Address, &master.Address, ablock.NoFork, ablock.DefaultConfig).
+		Mock(best, best.Header.Timestamp()+ablock.DefaultConfig.BlockInterval, best.Header.GasLimit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range txs {
+		if err := flow.Adopt(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conflicts, err := n.repo.ScanConflicts(best.Header.Number() + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, stage, receipts, err := flow.Pack(master.PrivateKey, conflicts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.repo.AddBlock(b, receipts, conflicts); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.repo.SetBestBlockID(b.Header().ID()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTransfer(t *testing.T, repo *chain.Repository, to ablock.Address) *tx.Transaction {
+	trx := new(tx.Builder).
+		ChainTag(repo.ChainTag()).
+		Expiration(1000).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1000))).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func (n *testNode) importBlocks(t *testing.T, r *bytes.Buffer) (*chain.BlockSummary, error) {
+	engine, err := bft.NewEngine(n.repo, n.db, ablock.NoFork, ablock.DefaultConfig, ablock.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Import(context.Background(), r, n.repo, n.stater, engine, ablock.NoFork, ablock.DefaultConfig, nil)
+}
+
+func TestExportImport(t *testing.T) {
+	var (
+		src       = newTestNode(t)
+		recipient = ablock.BytesToAddress([]byte("recipient"))
+	)
+	for i := 0; i < 20; i++ {
+		if i == 5 {
+			src.packBlock(t, newTransfer(t, src.repo, recipient))
+		} else {
+			src.packBlock(t)
+		}
+	}
+	best := src.repo.BestBlockSummary()
+
+	for _, format := range []string{FormatRLP, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			var exported []uint32
+			assert.Nil(t, Export(context.Background(), &buf, src.repo, best.Header.ID(), 1, 10, format, func(num uint32) {
+				exported = append(exported, num)
+			}))
+			assert.Equal(t, 10, len(exported))
+
+			dst := newTestNode(t)
+			imported, err := dst.importBlocks(t, &buf)
+			assert.Nil(t, err)
+			expected, _ := src.repo.NewChain(best.Header.ID()).GetBlockSummary(10)
+			assert.Equal(t, expected.Header.ID(), imported.Header.ID())
+			assert.Equal(t, expected.Header.ID(), dst.repo.BestBlockSummary().Header.ID())
+
+			// overlapping blocks are skipped
+			buf.Reset()
+			assert.Nil(t, Export(context.Background(), &buf, src.repo, best.Header.ID(), 5, 20, format, nil))
+			imported, err = dst.importBlocks(t, &buf)
+			assert.Nil(t, err)
+			assert.Equal(t, best.Header.ID(), imported.Header.ID())
+
+			st := dst.stater.NewState(imported.Header.StateRoot(), imported.Header.Number(), imported.Conflicts, imported.SteadyNum)
+			balance, err := st.GetBalance(recipient)
+			assert.Nil(t, err)
+			assert.Equal(t, big.NewInt(1000), balance)
+
+			receipts, err := dst.repo.GetBlockReceipts(imported.Header.ID())
+			assert.Nil(t, err)
+			assert.Equal(t, imported.Header.ReceiptsRoot(), receipts.RootHash())
+		})
+	}
+
+	t.Run("gap", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Nil(t, Export(context.Background(), &buf, src.repo, best.Header.ID(), 2, 3, FormatRLP, nil))
+		_, err := newTestNode(t).importBlocks(t, &buf)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Nil(t, Export(context.Background(), &buf, src.repo, best.Header.ID(), 1, 3, FormatNDJSON, nil))
+		buf.Truncate(buf.Len() / 2)
+		dst := newTestNode(t)
+		_, err := dst.importBlocks(t, &buf)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("rewound", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Nil(t, Export(context.Background(), &buf, src.repo, best.Header.ID(), 1, 20, FormatRLP, nil))
+		data := buf.Bytes()
+
+		dst := newTestNode(t)
+		_, err := dst.importBlocks(t, bytes.NewBuffer(data))
+		assert.Nil(t, err)
+
+		// rewind as the rewind command does, then the same blocks are imported again
+		rewindTo, _ := src.repo.NewChain(best.Header.ID()).GetBlockID(10)
+		assert.Nil(t, dst.repo.SetBestBlockID(rewindTo))
+		imported, err := dst.importBlocks(t, bytes.NewBuffer(data))
+		assert.Nil(t, err)
+		assert.Equal(t, best.Header.ID(), imported.Header.ID())
+		assert.Equal(t, best.Header.ID(), dst.repo.BestBlockSummary().Header.ID())
+		summary, err := dst.repo.GetBlockSummary(best.Header.ID())
+		assert.Nil(t, err)
+		assert.Equal(t, best.Conflicts, summary.Conflicts)
+		_, err = dst.repo.NewBestChain().GetBlockHeader(15)
+		assert.Nil(t, err)
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		// the block is valid, but conflicts with the local best chain
+		other := newTestNode(t)
+		other.packBlock(t, newTransfer(t, other.repo, recipient))
+		var buf bytes.Buffer
+		assert.Nil(t, Export(context.Background(), &buf, other.repo, other.repo.BestBlockSummary().Header.ID(), 1, 1, FormatRLP, nil))
+
+		dst := newTestNode(t)
+		dst.packBlock(t)
+		_, err := dst.importBlocks(t, &buf)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/cmd/ablock/blockfile"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var blocksCommand = cli.Command{
+	Name:  "blocks",
+	Usage: "export blocks with receipts into a portable file, or import them with full validation",
+	Subcommands: []cli.Command{
+		{
+			Name:      "export",
+			Usage:     "export a range of blocks on the best chain into the file",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				networkFlag,
+				dataDirFlag,
+				cacheFlag,
+				disablePrunerFlag,
+				stateRetentionFlag,
+				verbosityFlag,
+				blocksFromFlag,
+				blocksToFlag,
+				blocksFormatFlag,
+			},
+			Action: blocksExportAction,
+		},
+		{
+			Name:      "import",
+			Usage:     "import blocks from the file on top of the best block, the node must be stopped",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				networkFlag,
+				dataDirFlag,
+				cacheFlag,
+				disablePrunerFlag,
+				stateRetentionFlag,
+				dbEngineFlag,
+				skipLogsFlag,
+				verbosityFlag,
+			},
+			Action: blocksImportAction,
+		},
+	},
+}
+
+func blocksExportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("file path required")
+	}
+
+	inst, err := openInstance(ctx)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+
+	best := inst.repo.BestBlockSummary().Header
+	from := uint32(ctx.Uint(blocksFromFlag.Name))
+	to := uint32(ctx.Uint(blocksToFlag.Name))
+	if to == 0 {
+		to = best.Number()
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "create file")
+	}
+	w := bufio.NewWriter(file)
+
+	fmt.Println(">> Exporting blocks <<")
+	err = blockfile.Export(exitSignal, w, inst.repo, best.ID(), from, to, ctx.String(blocksFormatFlag.Name), func(num uint32) {
+		fmt.Printf("\rexported block %v", num)
+	})
+	fmt.Println()
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return errors.Wrap(err, "export blocks")
+	}
+	fmt.Printf("blocks %v-%v exported\n", from, to)
+	return nil
+}
+
+func blocksImportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("file path required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "open file")
+	}
+	defer file.Close()
+
+	inst, err := openInstance(ctx)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+
+	engine, err := bft.NewEngine(inst.repo, inst.mainDB, inst.forkConfig, inst.config, ablock.Address{})
+	if err != nil {
+		return errors.Wrap(err, "load bft engine")
+	}
+
+	fmt.Println(">> Importing blocks <<")
+	best, err := blockfile.Import(exitSignal, file, inst.repo, state.NewStater(inst.mainDB), engine, inst.forkConfig, inst.config, func(num uint32) {
+		fmt.Printf("\rimported block %v", num)
+	})
+	fmt.Println()
+	if err != nil {
+		// blocks imported are kept, importing the same file again resumes
+		return errors.Wrap(err, "import blocks")
+	}
+	fmt.Printf("best block %v (%v)\n", best.Header.Number(), best.Header.ID())
+
+	if !ctx.Bool(skipLogsFlag.Name) {
+		return syncLogDB(exitSignal, inst.repo, inst.logDB, false)
+	}
+	return nil
+}
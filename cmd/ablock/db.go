@@ -0,0 +1,343 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/state"
+	"gopkg.in/cheggaaa/pb.v1"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var dbCommand = cli.Command{
+	Name:  "db",
+	Usage: "offline maintenance of the node databases",
+	Subcommands: []cli.Command{
+		{
+			Name:  "migrate",
+			Usage: "migrate the main database to another storage engine, the node must be stopped",
+			Flags: []cli.Flag{
+				networkFlag,
+				dataDirFlag,
+				cacheFlag,
+				disablePrunerFlag,
+				stateRetentionFlag,
+				dbEngineFlag,
+				verbosityFlag,
+			},
+			Action: dbMigrateAction,
+		},
+		{
+			Name:  "check",
+			Usage: "check the integrity of the chain data, state and log db, the node must be stopped",
+			Flags: []cli.Flag{
+				networkFlag,
+				dataDirFlag,
+				cacheFlag,
+				disablePrunerFlag,
+				stateRetentionFlag,
+				skipLogsFlag,
+				dbRepairFlag,
+				verbosityFlag,
+			},
+			Action: dbCheckAction,
+		},
+	},
+}
+
+func dbMigrateAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	target := ctx.String(dbEngineFlag.Name)
+	if target == "" {
+		return fmt.Errorf("target engine required, use -%s to specify", dbEngineFlag.Name)
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(instanceDir, "main.db")
+	source, err := muxdb.DetectEngine(path)
+	if err != nil {
+		return errors.Wrap(err, "detect engine")
+	}
+	if source == "" {
+		return fmt.Errorf("no database found at [%v]", path)
+	}
+	if source == target {
+		return fmt.Errorf("database is already backed by %v", target)
+	}
+
+	srcDB, err := openMainDBAt(ctx, path, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if srcDB != nil {
+			srcDB.Close()
+		}
+	}()
+
+	// copy into a temp database, which is left over if interrupted
+	tmpPath := path + "." + target + ".tmp"
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return errors.Wrap(err, "remove temp database")
+	}
+	dstDB, err := openMainDBAt(ctx, tmpPath, target)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if dstDB != nil {
+			dstDB.Close()
+		}
+	}()
+
+	fmt.Printf(">> Migrating main database from %v to %v <<\n", source, target)
+	if err := srcDB.CopyTo(exitSignal, dstDB, func(n int) {
+		fmt.Printf("\rcopied %v entries", n)
+	}); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+	fmt.Println()
+
+	err = srcDB.Close()
+	srcDB = nil
+	if err != nil {
+		return errors.Wrap(err, "close source database")
+	}
+	err = dstDB.Close()
+	dstDB = nil
+	if err != nil {
+		return errors.Wrap(err, "close target database")
+	}
+
+	backupPath := path + "." + source + ".bak"
+	if err := os.Rename(path, backupPath); err != nil {
+		return errors.Wrap(err, "backup source database")
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.Wrap(err, "replace source database")
+	}
+	fmt.Printf("main database migrated, the backup [%v] can be removed once the node works well\n", backupPath)
+	return nil
+}
+
+func dbCheckAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	inst, err := openInstance(ctx)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+
+	firstNum, err := seekFirstBlockNum(inst.repo)
+	if err != nil {
+		return errors.Wrap(err, "seek first block")
+	}
+	// must be sought before the best block rewound
+	logDBPos, err := seekLogDBSyncPosition(inst.repo, inst.logDB)
+	if err != nil {
+		return errors.Wrap(err, "seek log db sync position")
+	}
+
+	fmt.Println(">> Checking chain <<")
+	lastGood, chainErr := checkChain(exitSignal, inst.repo, inst.mainDB, firstNum)
+	if chainErr != nil {
+		if exitSignal.Err() != nil {
+			return exitSignal.Err()
+		}
+		if lastGood == nil {
+			return errors.WithMessage(chainErr, "no consistent block")
+		}
+		fmt.Printf("chain inconsistent after block %v: %v\n", lastGood.Header.Number(), chainErr)
+	} else {
+		fmt.Println("chain is consistent")
+	}
+
+	var logDBErr error
+	if !ctx.Bool(skipLogsFlag.Name) {
+		end := logDBPos - 1
+		if n := lastGood.Header.Number(); end > n {
+			end = n
+		}
+		if logDBPos > firstNum && end >= firstNum {
+			if logDBErr = verifyLogDB(exitSignal, firstNum, end, inst.repo, inst.logDB); logDBErr != nil {
+				if exitSignal.Err() != nil {
+					return exitSignal.Err()
+				}
+				fmt.Printf("log db inconsistent: %v\n", logDBErr)
+			} else {
+				fmt.Println("log db is consistent")
+			}
+		}
+	}
+
+	if chainErr == nil && logDBErr == nil {
+		return nil
+	}
+	if !ctx.Bool(dbRepairFlag.Name) {
+		return fmt.Errorf("database inconsistent, use -%s to repair", dbRepairFlag.Name)
+	}
+
+	fmt.Println(">> Repairing <<")
+	w := inst.logDB.NewWriter()
+	if logDBErr != nil {
+		// the log db will be rebuilt from the first block on the next start
+		if err := w.Truncate(firstNum); err != nil {
+			return errors.Wrap(err, "truncate log db")
+		}
+	} else if err := w.Truncate(lastGood.Header.Number() + 1); err != nil {
+		return errors.Wrap(err, "truncate log db")
+	}
+	if err := w.Commit(); err != nil {
+		return errors.Wrap(err, "commit log db")
+	}
+	if chainErr != nil {
+		// blocks after the last good one are left in the repository, and they are executed and
+		// saved again when received from peers or imported
+		if err := inst.repo.SetBestBlockID(lastGood.Header.ID()); err != nil {
+			return errors.Wrap(err, "set best block")
+		}
+		fmt.Printf("best block rewound to %v (%v), later blocks will be processed again\n", lastGood.Header.Number(), lastGood.Header.ID())
+	}
+	return nil
+}
+
+// checkChain walks the best chain from the first block, and returns the last block before the first inconsistent
+// one, along with the inconsistency. For each block, it verifies the header link, txs and receipts roots, block
+// and tx index entries, and reachability of the state root if the state is retained.
+func checkChain(ctx context.Context, repo *chain.Repository, mainDB *muxdb.MuxDB, firstNum uint32) (*chain.BlockSummary, error) {
+	var (
+		best      = repo.BestBlockSummary()
+		stater    = state.NewStater(mainDB)
+		bestChain = repo.NewChain(best.Header.ID())
+		bestNum   = best.Header.Number()
+	)
+	// the parent of the first block is absent if bootstrapped from a snapshot
+	prev, err := bestChain.GetBlockSummary(firstNum - 1)
+	if err != nil && !repo.IsNotFound(err) {
+		return nil, err
+	}
+
+	pb := pb.New64(int64(bestNum)).
+		Set64(int64(firstNum - 1)).
+		SetMaxWidth(90).
+		Start()
+	defer func() { pb.NotPrint = true }()
+
+	for num := firstNum; num <= bestNum; num++ {
+		select {
+		case <-ctx.Done():
+			return prev, ctx.Err()
+		default:
+		}
+		summary, err := checkBlock(repo, mainDB, stater, bestChain, prev, num)
+		if err != nil {
+			return prev, errors.WithMessagef(err, "block %v", num)
+		}
+		prev = summary
+		pb.Add64(1)
+	}
+	pb.Finish()
+	return prev, nil
+}
+
+func checkBlock(
+	repo *chain.Repository,
+	mainDB *muxdb.MuxDB,
+	stater *state.Stater,
+	bestChain *chain.Chain,
+	parent *chain.BlockSummary,
+	num uint32,
+) (*chain.BlockSummary, error) {
+	id, err := bestChain.GetBlockID(num)
+	if err != nil {
+		return nil, errors.WithMessage(err, "get block id")
+	}
+	summary, err := repo.GetBlockSummary(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "get block summary")
+	}
+	header := summary.Header
+	if header.ID() != id || header.Number() != num {
+		return nil, errors.New("header mismatch")
+	}
+	// the parent may be absent
+	if parent != nil && header.ParentID() != parent.Header.ID() {
+		return nil, errors.New("broken parent link")
+	}
+
+	// entries written by indexBlock
+	blockChain := repo.NewChain(id)
+	if indexed, err := blockChain.GetBlockID(num); err != nil {
+		return nil, errors.WithMessage(err, "block index")
+	} else if indexed != id {
+		return nil, errors.New("block index mismatch")
+	}
+	if parent != nil {
+		if indexed, err := blockChain.GetBlockID(num - 1); err != nil {
+			return nil, errors.WithMessage(err, "parent block index")
+		} else if indexed != header.ParentID() {
+			return nil, errors.New("parent block index mismatch")
+		}
+	}
+
+	txs, err := repo.GetBlockTransactions(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "get txs")
+	}
+	if txs.RootHash() != header.TxsRoot() {
+		return nil, errors.New("txs root mismatch")
+	}
+	receipts, err := repo.GetBlockReceipts(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "get receipts")
+	}
+	if receipts.RootHash() != header.ReceiptsRoot() {
+		return nil, errors.New("receipts root mismatch")
+	}
+	for i, tx := range txs {
+		if tx.ID() != summary.Txs[i] {
+			return nil, errors.New("tx id mismatch")
+		}
+		meta, err := blockChain.GetTransactionMeta(tx.ID())
+		if err != nil {
+			return nil, errors.WithMessagef(err, "tx index %v", tx.ID())
+		}
+		if meta.BlockID != id || meta.Index != uint64(i) || meta.Reverted != receipts[i].Reverted {
+			return nil, errors.Errorf("tx index %v mismatch", tx.ID())
+		}
+	}
+
+	if retained, err := stater.IsRetained(num); err != nil {
+		return nil, err
+	} else if retained {
+		// resolving any key ensures the root node is reachable
+		accTrie := mainDB.NewTrie(state.AccountTrieName, header.StateRoot(), num, summary.Conflicts)
+		accTrie.SetNoFillCache(true)
+		if _, _, err := accTrie.Get(bytes.Repeat([]byte{0}, 32)); err != nil {
+			return nil, errors.WithMessage(err, "state root")
+		}
+	}
+	return summary, nil
+}
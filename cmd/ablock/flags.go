@@ -7,6 +7,7 @@ package main
 
 import (
 	"github.com/inconshreveable/log15"
+	"github.com/ashishaw/authorityblock/txpool"
 	cli "gopkg.in/urfave/cli.v1"
 )
 
@@ -110,6 +111,10 @@ var (
 		Name:  "pprof",
 		Usage: "turn on go-pprof",
 	}
+	metricsAddrFlag = cli.StringFlag{
+		Name:  "metrics-addr",
+		Usage: "address for Prometheus metrics service (e.g. localhost:2112), disabled if empty",
+	}
 	skipLogsFlag = cli.BoolFlag{
 		Name:  "skip-logs",
 		Usage: "skip writing event|transfer logs (/logs API will be disabled)",
@@ -128,6 +133,18 @@ var (
 		Name:  "disable-pruner",
 		Usage: "disable state pruner to keep all history",
 	}
+	dbEngineFlag = cli.StringFlag{
+		Name:  "db-engine",
+		Usage: "storage engine of the main database (leveldb|pebble), defaults to the engine of the existing database or leveldb",
+	}
+	dbRepairFlag = cli.BoolFlag{
+		Name:  "repair",
+		Usage: "rewind the best block to the last consistent one if any inconsistency found, later blocks are processed again on sync",
+	}
+	stateRetentionFlag = cli.StringFlag{
+		Name:  "state-retention",
+		Usage: "state history retention policy, 'archive' or 'recent=<n>[,checkpoint=<k>]' to keep state of recent n blocks and every k-th block (default recent=70000)",
+	}
 	txPoolLimitFlag = cli.IntFlag{
 		Name:  "txpool-limit",
 		Value: 10000,
@@ -138,4 +155,156 @@ var (
 		Value: 16,
 		Usage: "set tx limit per account in pool",
 	}
+	txPoolJournalFlag = cli.BoolFlag{
+		Name:  "txpool-journal",
+		Usage: "persist pending txs of pool into instance dir, to survive restarts",
+	}
+	txPoolRateLimitFlag = cli.StringFlag{
+		Name:  "txpool-rate-limit",
+		Usage: "limit txs per origin and delegator, e.g. 'originTxs=16,delegatorTxs=64,originFailures=4,delegatorFailures=16,window=6,ban=60' (window and ban in blocks)",
+	}
+	delegatorKeyFlag = cli.StringFlag{
+		Name:  "delegator-key",
+		Usage: "path of private key file to enable VIP-191 delegator service at /delegator",
+	}
+	delegatorPolicyFlag = cli.StringFlag{
+		Name:  "delegator-policy",
+		Usage: "path of JSON policy file for delegator service, required by -delegator-key, the daily energy budget must be set",
+	}
+	blocklistFileFlag = cli.StringFlag{
+		Name:  "blocklist-file",
+		Usage: "path of local blocklist file, one address per line, '!' prefixed to allow, reloaded once modified",
+	}
+	blocklistRegistryFlag = cli.StringFlag{
+		Name:  "blocklist-registry",
+		Usage: "address of on-chain blocklist registry contract, which implements 'blocklist() returns (address[])'",
+	}
+	blocklistURLFlag = cli.StringFlag{
+		Name:  "blocklist-url",
+		Usage: "URL to fetch blocklist from periodically",
+	}
+	misbehaviorAlertFlag = cli.BoolFlag{
+		Name:  "misbehavior-alert",
+		Usage: "log an alert when an authority node is found double signing",
+	}
+	nodeURLFlag = cli.StringFlag{
+		Name:  "node",
+		Value: "http://localhost:8669",
+		Usage: "API URL of the node to interact with",
+	}
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "path of the JSON keystore file to sign transactions",
+	}
+	revisionFlag = cli.StringFlag{
+		Name:  "revision",
+		Value: "best",
+		Usage: "block number or ID to query at",
+	}
+	txOriginFlag = cli.StringFlag{
+		Name:  "origin",
+		Usage: "address of the tx origin to estimate gas with",
+	}
+	txGasFlag = cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "gas limit of the tx, estimated by the node if not set",
+	}
+	txGasMarginFlag = cli.UintFlag{
+		Name:  "gas-margin",
+		Value: 10,
+		Usage: "extra gas in percent added to the estimated gas",
+	}
+	txGasPriceCoefFlag = cli.UintFlag{
+		Name:  "gas-price-coef",
+		Usage: "gas price coefficient of the tx (0-255)",
+	}
+	txExpirationFlag = cli.UintFlag{
+		Name:  "expiration",
+		Value: 720,
+		Usage: "expiration of the tx in blocks, counted from the best block",
+	}
+	txDependsOnFlag = cli.StringFlag{
+		Name:  "depends-on",
+		Usage: "ID of the tx which the tx depends on",
+	}
+	txDelegatedFlag = cli.BoolFlag{
+		Name:  "delegated",
+		Usage: "build a delegated tx (VIP-191), whose gas is paid by the delegator",
+	}
+	txWaitFlag = cli.BoolFlag{
+		Name:  "wait",
+		Usage: "wait for the tx to be packed",
+	}
+	delegatorKeystoreFlag = cli.StringFlag{
+		Name:  "delegator-keystore",
+		Usage: "path of the JSON keystore file to sign transactions as the delegator",
+	}
+	delegatorURLFlag = cli.StringFlag{
+		Name:  "delegator-url",
+		Usage: "URL of the delegator service to request the delegator signature from (e.g. http://localhost:8669/delegator/sign)",
+	}
+	txOrderingFlag = cli.StringFlag{
+		Name:  "tx-ordering",
+		Value: txpool.OrderingGasPrice,
+		Usage: "ordering policy of txs to pack (gasprice|fifo|round-robin|priority)",
+	}
+	txPriorityLanesFlag = cli.StringFlag{
+		Name:  "tx-priority-lanes",
+		Usage: "origins of priority lanes for priority ordering, lanes in descending priority separated by ';', origins in a lane separated by ','",
+	}
+	remoteSignerFlag = cli.StringFlag{
+		Name:  "remote-signer",
+		Usage: "URL of the remote signer to sign blocks instead of the local master key (http://host:port or unix:///path)",
+	}
+	remoteSignerTokenFileFlag = cli.StringFlag{
+		Name:  "remote-signer-token-file",
+		Usage: "path of the file containing the bearer token of the remote signer",
+	}
+	signerListenFlag = cli.StringFlag{
+		Name:  "listen",
+		Usage: "address for the signer to listen on (unix:///path or host:port), defaults to 'signer.sock' in config dir",
+	}
+	signerTokenFileFlag = cli.StringFlag{
+		Name:  "token-file",
+		Usage: "path of the file containing the bearer token required from clients, mandatory when listening on host:port",
+	}
+	snapshotBlockFlag = cli.StringFlag{
+		Name:  "block",
+		Usage: "number or ID of the block to export the snapshot at, defaults to the finalized block",
+	}
+	snapshotRecentBlocksFlag = cli.UintFlag{
+		Name:  "recent-blocks",
+		Value: 1000,
+		Usage: "number of recent blocks to be exported with txs and receipts",
+	}
+	rewindToFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "number or id of the block on the best chain to rewind to",
+	}
+	rewindForceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "allow rewinding below the finalized block",
+	}
+	stateSyncFlag = cli.StringFlag{
+		Name:  "state-sync",
+		Usage: "ID of a trusted finalized block, to bootstrap a fresh node by downloading its state from peers",
+	}
+	blocksFromFlag = cli.UintFlag{
+		Name:  "from",
+		Value: 1,
+		Usage: "number of the first block to export",
+	}
+	blocksToFlag = cli.UintFlag{
+		Name:  "to",
+		Usage: "number of the last block to export, defaults to the best block",
+	}
+	blocksFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Value: "rlp",
+		Usage: "format of the exported file (rlp|ndjson)",
+	}
+	signerStateFlag = cli.StringFlag{
+		Name:  "state-file",
+		Usage: "path of the file to keep signed records for double-sign protection, defaults to 'signer.state' in config dir",
+	}
 )
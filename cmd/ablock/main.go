@@ -27,6 +27,7 @@ import (
 	"github.com/ashishaw/authorityblock/genesis"
 	"github.com/ashishaw/authorityblock/logdb"
 	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
 	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/txpool"
@@ -42,6 +43,9 @@ var (
 	gitTag    string
 	log       = log15.New()
 
+	// number of recent packing reports kept for the API.
+	packingReportsLimit = 100
+
 	defaultTxPoolOptions = txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
@@ -82,8 +86,24 @@ func main() {
 			bootNodeFlag,
 			skipLogsFlag,
 			pprofFlag,
+			metricsAddrFlag,
 			verifyLogsFlag,
 			disablePrunerFlag,
+			stateRetentionFlag,
+			dbEngineFlag,
+			misbehaviorAlertFlag,
+			txPoolJournalFlag,
+			txPoolRateLimitFlag,
+			delegatorKeyFlag,
+			delegatorPolicyFlag,
+			blocklistFileFlag,
+			blocklistRegistryFlag,
+			blocklistURLFlag,
+			txOrderingFlag,
+			txPriorityLanesFlag,
+			remoteSignerFlag,
+			remoteSignerTokenFileFlag,
+			stateSyncFlag,
 		},
 		Action: defaultAction,
 		Commands: []cli.Command{
@@ -103,11 +123,19 @@ func main() {
 					gasLimitFlag,
 					verbosityFlag,
 					pprofFlag,
+					metricsAddrFlag,
 					verifyLogsFlag,
 					skipLogsFlag,
 					txPoolLimitFlag,
 					txPoolLimitPerAccountFlag,
+					txPoolJournalFlag,
+					delegatorKeyFlag,
+					delegatorPolicyFlag,
 					disablePrunerFlag,
+					stateRetentionFlag,
+					dbEngineFlag,
+					txOrderingFlag,
+					txPriorityLanesFlag,
 				},
 				Action: soloAction,
 			},
@@ -121,6 +149,14 @@ func main() {
 				},
 				Action: masterKeyAction,
 			},
+			authorityCommand,
+			paramsCommand,
+			signerCommand,
+			txCommand,
+			snapshotCommand,
+			dbCommand,
+			rewindCommand,
+			blocksCommand,
 		},
 	}
 
@@ -140,6 +176,7 @@ func defaultAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	config := gene.Config()
 	instanceDir, err := makeInstanceDir(ctx, gene)
 	if err != nil {
 		return err
@@ -177,19 +214,40 @@ func defaultAction(ctx *cli.Context) error {
 		}
 	}
 
+	ordering, err := txOrdering(ctx)
+	if err != nil {
+		return err
+	}
+
 	txpoolOpt := defaultTxPoolOptions
+	txpoolOpt.BlockInterval = config.BlockInterval
+	if ctx.Bool(txPoolJournalFlag.Name) {
+		txpoolOpt.JournalPath = filepath.Join(instanceDir, "txpool.journal")
+	}
+	if err := setBlocklistOptions(ctx, &txpoolOpt, instanceDir); err != nil {
+		return err
+	}
+	if txpoolOpt.RateLimit, err = txpool.ParseRateLimit(ctx.String(txPoolRateLimitFlag.Name)); err != nil {
+		return errors.Wrap(err, txPoolRateLimitFlag.Name)
+	}
 	txPool := txpool.New(repo, state.NewStater(mainDB), txpoolOpt)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
-	p2pcom, err := newP2PComm(ctx, repo, txPool, instanceDir)
+	p2pcom, err := newP2PComm(ctx, repo, mainDB, txPool, instanceDir, config)
 	if err != nil {
 		return err
 	}
 
-	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, master.Address())
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, config, master.Address())
 	if err != nil {
 		return errors.Wrap(err, "init bft engine")
 	}
+	watcher := bft.NewWatcher(repo, mainDB, ctx.Bool(misbehaviorAlertFlag.Name))
+	packingReports := packer.NewReports(packingReportsLimit)
+	gasPayer, err := newDelegator(ctx, repo, state.NewStater(mainDB))
+	if err != nil {
+		return err
+	}
 
 	apiHandler, apiCloser := api.New(
 		repo,
@@ -198,12 +256,16 @@ func defaultAction(ctx *cli.Context) error {
 		logDB,
 		bftEngine,
 		p2pcom.comm,
+		watcher,
+		packingReports,
+		gasPayer,
 		ctx.String(apiCorsFlag.Name),
 		uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
 		uint64(ctx.Int(apiCallGasLimitFlag.Name)),
 		ctx.Bool(pprofFlag.Name),
 		skipLogs,
-		forkConfig)
+		forkConfig,
+		config)
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
 	apiURL, srvCloser, err := startAPIServer(ctx, apiHandler, repo.GenesisBlock().Header().ID())
@@ -212,6 +274,12 @@ func defaultAction(ctx *cli.Context) error {
 	}
 	defer func() { log.Info("stopping API server..."); srvCloser() }()
 
+	metricsCloser, err := startMetricsServer(ctx, mainDB, repo, bftEngine)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("stopping metrics server..."); metricsCloser() }()
+
 	printStartupMessage2(apiURL, p2pcom.enode)
 
 	if err := p2pcom.Start(); err != nil {
@@ -219,21 +287,35 @@ func defaultAction(ctx *cli.Context) error {
 	}
 	defer p2pcom.Stop()
 
-	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
+	if str := ctx.String(stateSyncFlag.Name); str != "" {
+		if err := syncStateFromPeers(exitSignal, str, p2pcom.comm, repo, mainDB, logDB, skipLogs); err != nil {
+			return err
+		}
+	}
+
+	retention, err := parseRetention(ctx)
+	if err != nil {
+		return err
+	}
+	optimizer := optimizer.New(mainDB, repo, retention)
 	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
 
 	return node.New(
 		master,
 		repo,
 		bftEngine,
+		watcher,
 		state.NewStater(mainDB),
 		logDB,
 		txPool,
+		ordering,
+		packingReports,
 		filepath.Join(instanceDir, "tx.stash"),
 		p2pcom.comm,
 		uint64(ctx.Int(targetGasLimitFlag.Name)),
 		skipLogs,
-		forkConfig).Run(exitSignal)
+		forkConfig,
+		config).Run(exitSignal)
 }
 
 func soloAction(ctx *cli.Context) error {
@@ -244,6 +326,7 @@ func soloAction(ctx *cli.Context) error {
 	gene := genesis.NewDevnet()
 	// Solo forks from the start
 	forkConfig := ablock.ForkConfig{}
+	config := gene.Config()
 
 	var mainDB *muxdb.MuxDB
 	var logDB *logdb.LogDB
@@ -281,14 +364,30 @@ func soloAction(ctx *cli.Context) error {
 		}
 	}
 
+	ordering, err := txOrdering(ctx)
+	if err != nil {
+		return err
+	}
+
 	txPoolOption := defaultTxPoolOptions
 	txPoolOption.Limit = ctx.Int(txPoolLimitFlag.Name)
 	txPoolOption.LimitPerAccount = ctx.Int(txPoolLimitPerAccountFlag.Name)
+	if ctx.Bool(txPoolJournalFlag.Name) {
+		if !ctx.Bool(persistFlag.Name) {
+			return errors.New("--txpool-journal requires --persist in solo mode")
+		}
+		txPoolOption.JournalPath = filepath.Join(instanceDir, "txpool.journal")
+	}
 
 	txPool := txpool.New(repo, state.NewStater(mainDB), txPoolOption)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
 	bftEngine := solo.NewBFTEngine(repo)
+	packingReports := packer.NewReports(packingReportsLimit)
+	gasPayer, err := newDelegator(ctx, repo, state.NewStater(mainDB))
+	if err != nil {
+		return err
+	}
 	apiHandler, apiCloser := api.New(
 		repo,
 		state.NewStater(mainDB),
@@ -296,12 +395,16 @@ func soloAction(ctx *cli.Context) error {
 		logDB,
 		bftEngine,
 		&solo.Communicator{},
+		nil,
+		packingReports,
+		gasPayer,
 		ctx.String(apiCorsFlag.Name),
 		uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
 		uint64(ctx.Int(apiCallGasLimitFlag.Name)),
 		ctx.Bool(pprofFlag.Name),
 		skipLogs,
-		forkConfig)
+		forkConfig,
+		config)
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
 	apiURL, srvCloser, err := startAPIServer(ctx, apiHandler, repo.GenesisBlock().Header().ID())
@@ -310,19 +413,32 @@ func soloAction(ctx *cli.Context) error {
 	}
 	defer func() { log.Info("stopping API server..."); srvCloser() }()
 
+	metricsCloser, err := startMetricsServer(ctx, mainDB, repo, bftEngine)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("stopping metrics server..."); metricsCloser() }()
+
 	printSoloStartupMessage(gene, repo, instanceDir, apiURL, forkConfig)
 
-	optimizer := optimizer.New(mainDB, repo, !ctx.Bool(disablePrunerFlag.Name))
+	retention, err := parseRetention(ctx)
+	if err != nil {
+		return err
+	}
+	optimizer := optimizer.New(mainDB, repo, retention)
 	defer func() { log.Info("stopping optimizer..."); optimizer.Stop() }()
 
 	return solo.New(repo,
 		state.NewStater(mainDB),
 		logDB,
 		txPool,
+		ordering,
+		packingReports,
 		uint64(ctx.Int(gasLimitFlag.Name)),
 		ctx.Bool(onDemandFlag.Name),
 		skipLogs,
-		forkConfig).Run(exitSignal)
+		forkConfig,
+		config).Run(exitSignal)
 }
 
 func masterKeyAction(ctx *cli.Context) error {
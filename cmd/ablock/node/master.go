@@ -6,17 +6,15 @@
 package node
 
 import (
-	"crypto/ecdsa"
-
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ashishaw/authorityblock/signer"
 	"github.com/ashishaw/authorityblock/ablock"
 )
 
 type Master struct {
-	PrivateKey  *ecdsa.PrivateKey
+	Signer      signer.Signer
 	Beneficiary *ablock.Address
 }
 
 func (m *Master) Address() ablock.Address {
-	return ablock.Address(crypto.PubkeyToAddress(m.PrivateKey.PublicKey))
+	return m.Signer.Address()
 }
@@ -51,13 +51,17 @@ type Node struct {
 	master         *Master
 	repo           *chain.Repository
 	bft            *bft.BFTEngine
+	watcher        *bft.Watcher
 	logDB          *logdb.LogDB
 	txPool         *txpool.TxPool
+	txOrdering     txpool.Ordering
+	packingReports *packer.Reports
 	txStashPath    string
 	comm           *comm.Communicator
 	targetGasLimit uint64
 	skipLogs       bool
 	forkConfig     ablock.ForkConfig
+	config         ablock.Config
 
 	logDBFailed bool
 	bandwidth   bandwidth.Bandwidth
@@ -70,29 +74,37 @@ func New(
 	master *Master,
 	repo *chain.Repository,
 	bft *bft.BFTEngine,
+	watcher *bft.Watcher,
 	stater *state.Stater,
 	logDB *logdb.LogDB,
 	txPool *txpool.TxPool,
+	txOrdering txpool.Ordering,
+	packingReports *packer.Reports,
 	txStashPath string,
 	comm *comm.Communicator,
 	targetGasLimit uint64,
 	skipLogs bool,
 	forkConfig ablock.ForkConfig,
+	config ablock.Config,
 ) *Node {
 
 	return &Node{
-		packer:         packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig),
-		cons:           consensus.New(repo, stater, forkConfig),
+		packer:         packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig, config),
+		cons:           consensus.New(repo, stater, forkConfig, config),
 		master:         master,
 		repo:           repo,
 		bft:            bft,
+		watcher:        watcher,
 		logDB:          logDB,
 		txPool:         txPool,
+		txOrdering:     txOrdering,
+		packingReports: packingReports,
 		txStashPath:    txStashPath,
 		comm:           comm,
 		targetGasLimit: targetGasLimit,
 		skipLogs:       skipLogs,
 		forkConfig:     forkConfig,
+		config:         config,
 	}
 }
 
@@ -166,7 +178,7 @@ func (n *Node) houseKeeping(ctx context.Context) {
 	newBlockCh := make(chan *comm.NewBlockEvent)
 	scope.Track(n.comm.SubscribeBlock(newBlockCh))
 
-	futureTicker := time.NewTicker(time.Duration(ablock.BlockInterval) * time.Second)
+	futureTicker := time.NewTicker(time.Duration(n.config.BlockInterval) * time.Second)
 	defer futureTicker.Stop()
 
 	connectivityTicker := time.NewTicker(time.Second)
@@ -221,7 +233,7 @@ func (n *Node) houseKeeping(ctx context.Context) {
 				noPeerTimes++
 				if noPeerTimes > 30 {
 					noPeerTimes = 0
-					go checkClockOffset()
+					go checkClockOffset(n.config.BlockInterval)
 				}
 			} else {
 				noPeerTimes = 0
@@ -301,12 +313,20 @@ func (n *Node) processBlock(newBlock *block.Block, stats *blockStats) (bool, err
 		// Check whether the block was already there.
 		// It can be skipped if no conflicts.
 		if conflicts > 0 {
-			if _, err := n.repo.GetBlockSummary(newBlock.Header().ID()); err != nil {
+			// collect evidence of misbehavior, even if the block would be rejected later
+			if _, err := n.watcher.Observe(newBlock.Header()); err != nil {
+				log.Warn("failed to observe block", "err", err)
+			}
+			if summary, err := n.repo.GetBlockSummary(newBlock.Header().ID()); err != nil {
 				if !n.repo.IsNotFound(err) {
 					return err
 				}
-			} else {
+			} else if newBlock.Header().ParentID() != n.repo.BestBlockSummary().Header.ID() {
 				return errKnownBlock
+			} else {
+				// the block was rewound away from the best chain, maybe to repair it, so process
+				// it again, keeping its index
+				conflicts = summary.Conflicts
 			}
 		}
 		parentSummary, err := n.repo.GetBlockSummary(newBlock.Header().ParentID())
@@ -397,6 +417,7 @@ func (n *Node) processBlock(newBlock *block.Block, stats *blockStats) (bool, err
 		}
 
 		stats.UpdateProcessed(1, len(receipts), execElapsed, commitElapsed, realElapsed, newBlock.Header().GasUsed())
+		stats.ObserveBlock(execElapsed, commitElapsed)
 		return nil
 	}); err != nil {
 		switch {
@@ -507,13 +528,13 @@ side-chain:   %v  %v`,
 	}
 }
 
-func checkClockOffset() {
+func checkClockOffset(blockInterval uint64) {
 	resp, err := ntp.Query("pool.ntp.org")
 	if err != nil {
 		log.Debug("failed to access NTP", "err", err)
 		return
 	}
-	if resp.ClockOffset > time.Duration(ablock.BlockInterval)*time.Second/2 {
+	if resp.ClockOffset > time.Duration(blockInterval)*time.Second/2 {
 		log.Warn("clock offset detected", "offset", common.PrettyDuration(resp.ClockOffset))
 	}
 }
@@ -14,7 +14,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/pkg/errors"
 	"github.com/ashishaw/authorityblock/packer"
-	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
 )
 
@@ -74,7 +73,7 @@ func (n *Node) packerLoop(ctx context.Context) {
 		log.Debug("scheduled to pack block", "after", time.Duration(flow.When()-now)*time.Second)
 
 		for {
-			if uint64(time.Now().Unix())+ablock.BlockInterval/2 > flow.When() {
+			if uint64(time.Now().Unix())+n.config.BlockInterval/2 > flow.When() {
 				// time to pack block
 				// blockInterval/2 early to allow more time for processing txs
 				if err := n.pack(flow); err != nil {
@@ -107,7 +106,7 @@ func (n *Node) packerLoop(ctx context.Context) {
 }
 
 func (n *Node) pack(flow *packer.Flow) error {
-	txs := n.txPool.Executables()
+	txs := n.txOrdering.Order(n.txPool.ExecutableTxs())
 	var txsToRemove []*tx.Transaction
 	defer func() {
 		for _, tx := range txsToRemove {
@@ -145,7 +144,7 @@ func (n *Node) pack(flow *packer.Flow) error {
 		}
 
 		// pack the new block
-		newBlock, stage, receipts, err := flow.Pack(n.master.PrivateKey, conflicts, shouldVote)
+		newBlock, stage, receipts, err := flow.PackWithSigner(n.master.Signer, conflicts, shouldVote)
 		if err != nil {
 			return errors.Wrap(err, "failed to pack block")
 		}
@@ -199,6 +198,12 @@ func (n *Node) pack(flow *packer.Flow) error {
 			"id", shortID(newBlock.Header().ID()),
 		)
 
+		report := flow.Report()
+		report.ID = newBlock.Header().ID()
+		report.ExecTime = time.Duration(execElapsed)
+		n.packingReports.Add(report)
+		log.Debug("packing report", report.LogContext()...)
+
 		if v, updated := n.bandwidth.Update(newBlock.Header(), time.Duration(realElapsed)); updated {
 			log.Debug("bandwidth updated", "gps", v)
 		}
@@ -7,13 +7,23 @@ package node
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/metric"
 	"github.com/ashishaw/authorityblock/ablock"
 )
 
+var (
+	metricBlocks          = metric.NewCounterVec("chain_import_blocks_total", "number of blocks handled by chain import", "result")
+	metricBlockTxs        = metric.NewCounter("chain_import_txs_total", "number of txs in imported blocks")
+	metricBlockGas        = metric.NewCounter("chain_import_gas_total", "gas used by imported blocks")
+	metricBlockExecTime   = metric.NewHistogram("chain_import_exec_seconds", "time to execute an imported block", nil)
+	metricBlockCommitTime = metric.NewHistogram("chain_import_commit_seconds", "time to commit an imported block", nil)
+)
+
 type blockStats struct {
 	exec, commit, real         mclock.AbsTime
 	txs                        int
@@ -28,14 +38,26 @@ func (s *blockStats) UpdateProcessed(n int, txs int, exec, commit, real mclock.A
 	s.commit += commit
 	s.real += real
 	s.usedGas += usedGas
+
+	metricBlocks.WithLabelValues("processed").Add(float64(n))
+	metricBlockTxs.Add(float64(txs))
+	metricBlockGas.Add(float64(usedGas))
+}
+
+// ObserveBlock records the time elapsed to execute and commit a single block.
+func (s *blockStats) ObserveBlock(exec, commit mclock.AbsTime) {
+	metricBlockExecTime.Observe(time.Duration(exec).Seconds())
+	metricBlockCommitTime.Observe(time.Duration(commit).Seconds())
 }
 
 func (s *blockStats) UpdateIgnored(n int) {
 	s.ignored += n
+	metricBlocks.WithLabelValues("ignored").Add(float64(n))
 }
 
 func (s *blockStats) UpdateQueued(n int) {
 	s.queued += n
+	metricBlocks.WithLabelValues("queued").Add(float64(n))
 }
 
 func (s *blockStats) LogContext(last *block.Header) []interface{} {
@@ -87,3 +87,34 @@ func (ts *txStash) LoadAll() tx.Transactions {
 	}
 	return txs
 }
+
+// DropStashedTxs removes txs from the stash at the given path, whose block ref is beyond the given block number.
+// It's used when the chain is rewound, and returns the count of removed txs.
+func DropStashedTxs(path string, blockNum uint32) (int, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var (
+		batch leveldb.Batch
+		it    = db.NewIterator(util.BytesPrefix(nil), nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		var tx tx.Transaction
+		// undecodable ones are dropped as well
+		if err := rlp.DecodeBytes(it.Value(), &tx); err != nil || tx.BlockRef().Number() > blockNum {
+			batch.Delete(it.Key())
+		}
+	}
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+	if err := db.Write(&batch, nil); err != nil {
+		return 0, err
+	}
+	return batch.Len(), nil
+}
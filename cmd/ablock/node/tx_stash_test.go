@@ -8,6 +8,7 @@ package node
 import (
 	"bytes"
 	"math/rand"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -51,3 +52,33 @@ func TestTxStash(t *testing.T) {
 
 	assert.Equal(t, saved.RootHash(), loaded.RootHash())
 }
+
+func TestDropStashedTxs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tx.stash")
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stash := newTxStash(db, 10)
+	for i := 0; i < 10; i++ {
+		tx := new(tx.Builder).Nonce(rand.Uint64()).BlockRef(tx.NewBlockRef(uint32(i))).Build()
+		sig, _ := crypto.Sign(tx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		assert.Nil(t, stash.Save(tx.WithSignature(sig)))
+	}
+	db.Close()
+
+	n, err := DropStashedTxs(path, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+
+	db, err = leveldb.OpenFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	loaded := newTxStash(db, 10).LoadAll()
+	assert.Equal(t, 6, len(loaded))
+	for _, tx := range loaded {
+		assert.True(t, tx.BlockRef().Number() <= 5)
+	}
+}
@@ -32,24 +32,26 @@ const (
 
 // Optimizer is a background task to optimize tries.
 type Optimizer struct {
-	db     *muxdb.MuxDB
-	repo   *chain.Repository
-	ctx    context.Context
-	cancel func()
-	goes   co.Goes
+	db        *muxdb.MuxDB
+	repo      *chain.Repository
+	retention *Retention
+	ctx       context.Context
+	cancel    func()
+	goes      co.Goes
 }
 
-// New creates and starts the optimizer.
-func New(db *muxdb.MuxDB, repo *chain.Repository, prune bool) *Optimizer {
+// New creates and starts the optimizer. Tries are pruned according to the retention policy.
+func New(db *muxdb.MuxDB, repo *chain.Repository, retention *Retention) *Optimizer {
 	ctx, cancel := context.WithCancel(context.Background())
 	o := &Optimizer{
-		db:     db,
-		repo:   repo,
-		ctx:    ctx,
-		cancel: cancel,
+		db:        db,
+		repo:      repo,
+		retention: retention,
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 	o.goes.Go(func() {
-		if err := o.loop(prune); err != nil {
+		if err := o.loop(); err != nil {
 			if err != context.Canceled && errors.Cause(err) != context.Canceled {
 				log.Warn("optimizer interrupted", "error", err)
 			}
@@ -58,6 +60,13 @@ func New(db *muxdb.MuxDB, repo *chain.Repository, prune bool) *Optimizer {
 	return o
 }
 
+// SetBase sets the block number from which the optimizer starts. It's used when the
+// state is bootstrapped from a snapshot, since there's no history before the snapshot.
+func SetBase(db *muxdb.MuxDB, base uint32) error {
+	s := status{Base: base, PruneBase: base}
+	return s.Save(db.NewStore(propsStoreName))
+}
+
 // Stop stops the optimizer.
 func (p *Optimizer) Stop() {
 	p.cancel()
@@ -65,13 +74,12 @@ func (p *Optimizer) Stop() {
 }
 
 // loop is the main loop.
-func (p *Optimizer) loop(prune bool) error {
-	log.Info("optimizer started")
+func (p *Optimizer) loop() error {
+	log.Info("optimizer started", "retention", p.retention)
 
 	const (
-		period        = 2000  // the period to update leafbank.
-		prunePeriod   = 10000 // the period to prune tries.
-		pruneReserved = 70000 // must be > ablock.MaxStateHistory
+		period      = 2000  // the period to update leafbank.
+		prunePeriod = 10000 // the period to prune tries.
 	)
 
 	var (
@@ -99,8 +107,11 @@ func (p *Optimizer) loop(prune bool) error {
 		}
 
 		// prune index/account/storage tries
-		if prune && target > pruneReserved {
-			if pruneTarget := target - pruneReserved; pruneTarget >= status.PruneBase+prunePeriod {
+		if !p.retention.Archive && target > p.retention.Recent {
+			if pruneTarget := target - p.retention.Recent; pruneTarget >= status.PruneBase+prunePeriod {
+				if err := p.archiveCheckpoints(targetChain, &status, pruneTarget); err != nil {
+					return errors.Wrap(err, "archive checkpoints")
+				}
 				if err := p.pruneTries(targetChain, status.PruneBase, pruneTarget); err != nil {
 					return errors.Wrap(err, "prune tries")
 				}
@@ -218,6 +229,72 @@ func (p *Optimizer) dumpTrieNodes(targetChain *chain.Chain, base, target uint32)
 	return nil
 }
 
+// archiveCheckpoints archives state of checkpoints within [status.PruneBase, limit), which are about to be pruned.
+func (p *Optimizer) archiveCheckpoints(targetChain *chain.Chain, status *status, limit uint32) error {
+	if p.retention.Checkpoint == 0 {
+		return nil
+	}
+	from := status.PruneBase
+	if status.Checkpoint >= from {
+		from = status.Checkpoint + 1
+	}
+	stater := state.NewStater(p.db)
+	for cp := p.retention.nextCheckpoint(from); cp < limit; cp += p.retention.Checkpoint {
+		// nodes committed before the last checkpoint are already archived
+		base := uint32(0)
+		if status.Checkpoint > 0 {
+			base = status.Checkpoint + 1
+		}
+		if err := p.archiveTrieNodes(targetChain, base, cp); err != nil {
+			return err
+		}
+		if err := stater.MarkArchived(cp); err != nil {
+			return err
+		}
+		status.Checkpoint = cp
+		log.Info("archived checkpoint", "num", cp)
+	}
+	return nil
+}
+
+// archiveTrieNodes archives index/account/storage trie nodes of the checkpoint, committed within [base, checkpoint].
+func (p *Optimizer) archiveTrieNodes(targetChain *chain.Chain, base, checkpoint uint32) error {
+	summary, err := targetChain.GetBlockSummary(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	// archive index trie
+	indexTrie := p.db.NewNonCryptoTrie(chain.IndexTrieName, trie.NonCryptoNodeHash, summary.Header.Number(), summary.Conflicts)
+	indexTrie.SetNoFillCache(true)
+
+	if err := indexTrie.ArchiveNodes(p.ctx, base, nil); err != nil {
+		return err
+	}
+
+	// archive account trie
+	accTrie := p.db.NewTrie(state.AccountTrieName, summary.Header.StateRoot(), summary.Header.Number(), summary.Conflicts)
+	accTrie.SetNoFillCache(true)
+
+	var sTries []*muxdb.Trie
+	if err := accTrie.ArchiveNodes(p.ctx, base, func(leaf *trie.Leaf) {
+		if sTrie := p.newStorageTrieIfUpdated(leaf, base); sTrie != nil {
+			sTries = append(sTries, sTrie)
+		}
+	}); err != nil {
+		return err
+	}
+
+	// archive storage tries
+	for _, sTrie := range sTries {
+		sTrie.SetNoFillCache(true)
+		if err := sTrie.ArchiveNodes(p.ctx, base, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // pruneTries prunes index/account/storage tries in the range [base, target).
 func (p *Optimizer) pruneTries(targetChain *chain.Chain, base, target uint32) error {
 	if err := p.dumpTrieNodes(targetChain, base, target); err != nil {
@@ -250,16 +327,19 @@ func (p *Optimizer) awaitUntilSteady(target uint32) (*chain.Chain, error) {
 		best := p.repo.BestBlockSummary()
 		bestNum := best.Header.Number()
 		if bestNum > target+backoff {
-			var meanScore float64
+			// the mean score of the whole chain
+			meanScore := math.Round(float64(best.Header.TotalScore()) / float64(bestNum))
 			if bestNum > windowSize {
 				baseNum := bestNum - windowSize
 				baseHeader, err := p.repo.NewChain(best.Header.ID()).GetBlockHeader(baseNum)
 				if err != nil {
-					return nil, err
+					// blocks before the snapshot are absent if bootstrapped from a snapshot
+					if !p.repo.IsNotFound(err) {
+						return nil, err
+					}
+				} else {
+					meanScore = math.Round(float64(best.Header.TotalScore()-baseHeader.TotalScore()) / float64(windowSize))
 				}
-				meanScore = math.Round(float64(best.Header.TotalScore()-baseHeader.TotalScore()) / float64(windowSize))
-			} else {
-				meanScore = math.Round(float64(best.Header.TotalScore()) / float64(bestNum))
 			}
 			set := make(map[ablock.Address]struct{})
 			// reverse iterate the chain and collect signers.
@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package optimizer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MinRecentBlocks is the min number of recent blocks whose state must be retained.
+// It must be > ablock.MaxStateHistory.
+const MinRecentBlocks = 70000
+
+// Retention is the policy of retaining state history.
+type Retention struct {
+	Archive    bool   // retains the full history, nothing pruned
+	Recent     uint32 // the number of recent blocks whose state is retained
+	Checkpoint uint32 // the state of every Checkpoint-th block is also retained, 0 means none
+}
+
+// DefaultRetention returns the default retention policy, which retains recent blocks only.
+func DefaultRetention() *Retention {
+	return &Retention{Recent: MinRecentBlocks}
+}
+
+// ParseRetention parses the retention policy in the form of 'archive' or 'recent=<n>[,checkpoint=<k>]'.
+// The default policy returned if str is empty.
+func ParseRetention(str string) (*Retention, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return DefaultRetention(), nil
+	}
+	if str == "archive" {
+		return &Retention{Archive: true}, nil
+	}
+
+	r := DefaultRetention()
+	for _, item := range strings.Split(str, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid item '%v'", item)
+		}
+		n, err := strconv.ParseUint(kv[1], 10, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, kv[0])
+		}
+		switch kv[0] {
+		case "recent":
+			if n < MinRecentBlocks {
+				return nil, fmt.Errorf("recent: should be >= %v", MinRecentBlocks)
+			}
+			r.Recent = uint32(n)
+		case "checkpoint":
+			r.Checkpoint = uint32(n)
+		default:
+			return nil, fmt.Errorf("unknown item '%v'", kv[0])
+		}
+	}
+	return r, nil
+}
+
+// String returns the policy in the form accepted by ParseRetention.
+func (r *Retention) String() string {
+	if r.Archive {
+		return "archive"
+	}
+	if r.Checkpoint > 0 {
+		return fmt.Sprintf("recent=%v,checkpoint=%v", r.Recent, r.Checkpoint)
+	}
+	return fmt.Sprintf("recent=%v", r.Recent)
+}
+
+// nextCheckpoint returns the first checkpoint >= num.
+func (r *Retention) nextCheckpoint(num uint32) uint32 {
+	if num == 0 {
+		// genesis is always retained
+		num = 1
+	}
+	return (num + r.Checkpoint - 1) / r.Checkpoint * r.Checkpoint
+}
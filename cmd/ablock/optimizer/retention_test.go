@@ -0,0 +1,66 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		str  string
+		want *Retention
+	}{
+		{"", &Retention{Recent: MinRecentBlocks}},
+		{" archive ", &Retention{Archive: true}},
+		{"recent=100000", &Retention{Recent: 100000}},
+		{"checkpoint=10000", &Retention{Recent: MinRecentBlocks, Checkpoint: 10000}},
+		{"recent=100000, checkpoint=10000", &Retention{Recent: 100000, Checkpoint: 10000}},
+	}
+	for _, tt := range tests {
+		r, err := ParseRetention(tt.str)
+		assert.Nil(t, err, tt.str)
+		assert.Equal(t, tt.want, r, tt.str)
+
+		// round trip
+		r, err = ParseRetention(r.String())
+		assert.Nil(t, err, tt.str)
+		assert.Equal(t, tt.want, r, tt.str)
+	}
+
+	for _, str := range []string{
+		"full",
+		"recent",
+		"recent=100",
+		"recent=-1",
+		"recent=4294967296",
+		"checkpoint=abc",
+		"recent=100000,pruned=1",
+	} {
+		_, err := ParseRetention(str)
+		assert.NotNil(t, err, str)
+	}
+}
+
+func TestNextCheckpoint(t *testing.T) {
+	r := &Retention{Recent: MinRecentBlocks, Checkpoint: 100}
+	tests := []struct {
+		num  uint32
+		want uint32
+	}{
+		{0, 100},
+		{1, 100},
+		{99, 100},
+		{100, 100},
+		{101, 200},
+		{250, 300},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, r.nextCheckpoint(tt.num), "num %v", tt.num)
+	}
+}
@@ -12,8 +12,9 @@ import (
 )
 
 type status struct {
-	Base      uint32
-	PruneBase uint32
+	Base       uint32
+	PruneBase  uint32
+	Checkpoint uint32 // the last archived checkpoint
 }
 
 func (s *status) Load(getter kv.Getter) error {
@@ -0,0 +1,196 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/api/events"
+	"github.com/ashishaw/authorityblock/api/params"
+	"github.com/ashishaw/authorityblock/builtin"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/ablock"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// proposals of executor expire after one week.
+const proposalLifetime = 7 * 24 * time.Hour
+
+var paramsCommand = cli.Command{
+	Name:  "params",
+	Usage: "inspect and change governance params through the builtin Params contract",
+	Subcommands: []cli.Command{
+		{
+			Name:   "list",
+			Usage:  "list governance params and their history",
+			Flags:  []cli.Flag{nodeURLFlag, revisionFlag},
+			Action: paramsListAction,
+		},
+		{
+			Name:      "set",
+			Usage:     "propose to change a param",
+			ArgsUsage: "<name|key> <value>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    paramsSetAction,
+		},
+		{
+			Name:   "proposals",
+			Usage:  "list pending proposals to change params",
+			Flags:  []cli.Flag{nodeURLFlag},
+			Action: paramsProposalsAction,
+		},
+		{
+			Name:      "approve",
+			Usage:     "approve a proposal",
+			ArgsUsage: "<proposal-id>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityApproveAction,
+		},
+		{
+			Name:      "execute",
+			Usage:     "execute a proposal which has reached the quorum",
+			ArgsUsage: "<proposal-id>",
+			Flags:     []cli.Flag{nodeURLFlag, keystoreFlag, txGasMarginFlag},
+			Action:    authorityExecuteAction,
+		},
+	},
+}
+
+func paramsListAction(ctx *cli.Context) error {
+	node := newRemoteNode(ctx)
+	var ps []*params.Param
+	if err := node.get("/params?revision="+ctx.String(revisionFlag.Name), &ps); err != nil {
+		return err
+	}
+	for _, p := range ps {
+		fmt.Printf("%v = %v\n", p.Name, (*big.Int)(p.Value))
+		for _, c := range p.History {
+			fmt.Printf("    #%-10v %v  %v  tx %v\n",
+				c.Meta.BlockNumber,
+				time.Unix(int64(c.Meta.BlockTimestamp), 0).Format(time.RFC3339),
+				(*big.Int)(c.Value),
+				c.Meta.TxID)
+		}
+	}
+	return nil
+}
+
+func paramsSetAction(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("requires args <name|key> <value>")
+	}
+	key, err := params.ParseKey(ctx.Args().Get(0))
+	if err != nil {
+		return errors.WithMessage(err, "key")
+	}
+	value, ok := math.ParseBig256(ctx.Args().Get(1))
+	if !ok {
+		return errors.New("invalid value")
+	}
+	data := mustEncodeInput(builtin.Params.ABI, "set", common.Hash(key), value)
+	return propose(ctx, builtin.Params.Address, data)
+}
+
+func paramsProposalsAction(ctx *cli.Context) error {
+	return listParamsProposals(os.Stdout, newRemoteNode(ctx))
+}
+
+// listParamsProposals writes unexecuted proposals to set params, along with their approvers.
+func listParamsProposals(w io.Writer, node *remoteNode) error {
+	best, err := node.block("best")
+	if err != nil {
+		return err
+	}
+	revision := best.ID.String()
+
+	// only proposals raised in the lifetime window can still be approved and executed
+	ev, _ := builtin.Executor.ABI.EventByName("Proposal")
+	topic0 := ev.ID()
+	from := uint64(0)
+	if lifetime := uint64(proposalLifetime / time.Second); best.Timestamp > lifetime {
+		from = best.Timestamp - lifetime
+	}
+	var logs []*events.FilteredEvent
+	if err := node.post("/logs/event", &events.EventFilter{
+		CriteriaSet: []*events.EventCriteria{{
+			Address:  &builtin.Executor.Address,
+			TopicSet: events.TopicSet{Topic0: &topic0},
+		}},
+		Range: &events.Range{Unit: events.TimeRangeType, From: from, To: best.Timestamp},
+		Order: logdb.ASC,
+	}, &logs); err != nil {
+		return err
+	}
+
+	var (
+		ids       []ablock.Bytes32
+		approvals = make(map[ablock.Bytes32][]ablock.Address)
+	)
+	for _, l := range logs {
+		if len(l.Topics) < 2 {
+			continue
+		}
+		data, err := hexutil.Decode(l.Data)
+		if err != nil {
+			return err
+		}
+		var action common.Hash
+		if err := ev.Decode(data, &action); err != nil {
+			return err
+		}
+		id := *l.Topics[1]
+		// the action is a string literal, which is left aligned in bytes32
+		switch string(bytes.TrimRight(action[:], "\x00")) {
+		case "proposed":
+			ids = append(ids, id)
+		case "approved":
+			approvals[id] = append(approvals[id], l.Meta.TxOrigin)
+		}
+	}
+
+	setMethod, _ := builtin.Params.ABI.MethodByName("set")
+	for _, id := range ids {
+		var p struct {
+			TimeProposed  uint64
+			Proposer      common.Address
+			Quorum        uint8
+			ApprovalCount uint8
+			Executed      bool
+			Target        common.Address
+			Data          []byte
+		}
+		if err := callBuiltin(node, revision, builtin.Executor.Address, builtin.Executor.ABI, &p, "proposals", common.Hash(id)); err != nil {
+			return err
+		}
+		if p.Executed || ablock.Address(p.Target) != builtin.Params.Address {
+			continue
+		}
+		var args struct {
+			Key   common.Hash
+			Value *big.Int
+		}
+		if err := setMethod.DecodeInput(p.Data, &args); err != nil {
+			continue
+		}
+		fmt.Fprintf(w, "Proposal %v\n", id)
+		fmt.Fprintf(w, "    set %v = %v\n", params.KeyName(ablock.Bytes32(args.Key)), args.Value)
+		fmt.Fprintf(w, "    proposed by %v at %v\n", ablock.Address(p.Proposer), time.Unix(int64(p.TimeProposed), 0).Format(time.RFC3339))
+		fmt.Fprintf(w, "    approvals %v/%v\n", p.ApprovalCount, p.Quorum)
+		for _, approver := range approvals[id] {
+			fmt.Fprintf(w, "        %v\n", approver)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/api/accounts"
+	"github.com/ashishaw/authorityblock/api/blocks"
+	"github.com/ashishaw/authorityblock/api/events"
+	"github.com/ashishaw/authorityblock/builtin"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+type noBFT struct{}
+
+func (noBFT) Finalized() ablock.Bytes32 { return ablock.Bytes32{} }
+
+func TestListParamsProposals(t *testing.T) {
+	var (
+		db         = muxdb.NewMem()
+		stater     = state.NewStater(db)
+		accs       = genesis.DevAccounts()[:3]
+		forkConfig = ablock.NoFork
+		gen        = &genesis.CustomGenesis{
+			LaunchTime: uint64(time.Now().Unix()) - 3600,
+			Authority:  []genesis.Authority{{MasterAddress: accs[0].Address, EndorsorAddress: accs[0].Address, Identity: ablock.BytesToBytes32([]byte("master"))}},
+			ForkConfig: &forkConfig,
+		}
+	)
+	for _, acc := range accs {
+		amount := new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e18))
+		gen.Accounts = append(gen.Accounts, genesis.Account{
+			Address: acc.Address,
+			Balance: (*genesis.HexOrDecimal256)(amount),
+			Energy:  (*genesis.HexOrDecimal256)(amount),
+		})
+		gen.Executor.Approvers = append(gen.Executor.Approvers, genesis.Approver{
+			Address:  acc.Address,
+			Identity: ablock.BytesToBytes32(acc.Address.Bytes()),
+		})
+	}
+	gene, err := genesis.NewCustomNet(gen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b0, _, _, err := gene.Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logDB.Close()
+
+	router := mux.NewRouter()
+	accounts.New(repo, stater, math.MaxUint64, forkConfig).Mount(router, "/accounts")
+	blocks.New(repo, noBFT{}).Mount(router, "/blocks")
+	events.New(repo, logDB).Mount(router, "/logs/event")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	node := &remoteNode{url: ts.URL, client: http.DefaultClient}
+
+	nonce := uint64(0)
+	executorTx := func(signer genesis.DevAccount, method string, args ...interface{}) *tx.Transaction {
+		nonce++
+		trx := new(tx.Builder).
+			ChainTag(repo.ChainTag()).
+			Expiration(1000).
+			Gas(1000000).
+			Nonce(nonce).
+			Clause(tx.NewClause(&builtin.Executor.Address).WithData(mustEncodeInput(builtin.Executor.ABI, method, args...))).
+			Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), signer.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return trx.WithSignature(sig)
+	}
+	packBlock := func(txs ...*tx.Transaction) tx.Receipts {
+		best := repo.BestBlockSummary()
+		flow, err := packer.New(repo, stater, accs[0].Address, &accs[0].Address, forkConfig, ablock.DefaultConfig).
+			Mock(best, best.Header.Timestamp()+ablock.DefaultConfig.BlockInterval, best.Header.GasLimit())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, trx := range txs {
+			if err := flow.Adopt(trx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		b, stage, receipts, err := flow.Pack(accs[0].PrivateKey, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range receipts {
+			assert.False(t, r.Reverted)
+		}
+		if _, err := stage.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.AddBlock(b, receipts, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.SetBestBlockID(b.Header().ID()); err != nil {
+			t.Fatal(err)
+		}
+		w := logDB.NewWriter()
+		if err := w.Write(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		return receipts
+	}
+	list := func() string {
+		var buf bytes.Buffer
+		if err := listParamsProposals(&buf, node); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	data := mustEncodeInput(builtin.Params.ABI, "set", common.Hash(ablock.KeyBaseGasPrice), big.NewInt(1234))
+	receipts := packBlock(executorTx(accs[0], "propose", common.Address(builtin.Params.Address), data))
+	id := receipts[0].Outputs[0].Events[0].Topics[1]
+	proposedAt := time.Unix(int64(repo.BestBlockSummary().Header.Timestamp()), 0).Format(time.RFC3339)
+
+	want := fmt.Sprintf("Proposal %v\n    set base-gas-price = 1234\n    proposed by %v at %v\n    approvals 0/2\n",
+		id, accs[0].Address, proposedAt)
+	assert.Equal(t, want, list())
+
+	packBlock(executorTx(accs[1], "approve", common.Hash(id)), executorTx(accs[2], "approve", common.Hash(id)))
+	want = fmt.Sprintf("Proposal %v\n    set base-gas-price = 1234\n    proposed by %v at %v\n    approvals 2/2\n        %v\n        %v\n",
+		id, accs[0].Address, proposedAt, accs[1].Address, accs[2].Address)
+	assert.Equal(t, want, list())
+
+	// executed proposals are no longer listed
+	packBlock(executorTx(accs[0], "execute", common.Hash(id)))
+	assert.Equal(t, "", list())
+}
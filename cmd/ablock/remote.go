@@ -0,0 +1,242 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/api/accounts"
+	"github.com/ashishaw/authorityblock/api/blocks"
+	"github.com/ashishaw/authorityblock/api/transactions"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// remoteNode is a minimal client of the node's RESTful API, used by the
+// sub commands which interact with a running node.
+type remoteNode struct {
+	url       string
+	client    *http.Client
+	gasMargin uint64 // in percent
+}
+
+func newRemoteNode(ctx *cli.Context) *remoteNode {
+	gasMargin := txGasMarginFlag.Value
+	if ctx.IsSet(txGasMarginFlag.Name) {
+		gasMargin = ctx.Uint(txGasMarginFlag.Name)
+	}
+	return &remoteNode{
+		url:       strings.TrimSuffix(ctx.String(nodeURLFlag.Name), "/"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+		gasMargin: uint64(gasMargin),
+	}
+}
+
+func (n *remoteNode) do(method, path string, reqObj, respObj interface{}) error {
+	var body []byte
+	if reqObj != nil {
+		data, err := json.Marshal(reqObj)
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+	req, err := http.NewRequest(method, n.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if reqObj != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v %v: %v %v", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if respObj == nil {
+		return nil
+	}
+	return json.Unmarshal(data, respObj)
+}
+
+func (n *remoteNode) get(path string, respObj interface{}) error {
+	return n.do(http.MethodGet, path, nil, respObj)
+}
+
+func (n *remoteNode) post(path string, reqObj, respObj interface{}) error {
+	return n.do(http.MethodPost, path, reqObj, respObj)
+}
+
+// block fetches the summary of block at given revision.
+func (n *remoteNode) block(revision string) (*blocks.JSONCollapsedBlock, error) {
+	var b *blocks.JSONCollapsedBlock
+	if err := n.get("/blocks/"+revision, &b); err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("block %v not found", revision)
+	}
+	return b, nil
+}
+
+// call simulates clauses at given revision.
+func (n *remoteNode) call(revision string, caller *ablock.Address, clauses []*tx.Clause) (accounts.BatchCallResults, error) {
+	data := accounts.BatchCallData{Caller: caller}
+	for _, c := range clauses {
+		value := (*math.HexOrDecimal256)(c.Value())
+		data.Clauses = append(data.Clauses, accounts.Clause{
+			To:    c.To(),
+			Value: value,
+			Data:  hexutil.Encode(c.Data()),
+		})
+	}
+	var results accounts.BatchCallResults
+	if err := n.post("/accounts/*?revision="+revision, &data, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// sendTx submits the signed tx and returns its ID.
+func (n *remoteNode) sendTx(trx *tx.Transaction) (ablock.Bytes32, error) {
+	raw, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		return ablock.Bytes32{}, err
+	}
+	var result struct {
+		ID ablock.Bytes32 `json:"id"`
+	}
+	if err := n.post("/transactions", &transactions.RawTx{Raw: hexutil.Encode(raw)}, &result); err != nil {
+		return ablock.Bytes32{}, err
+	}
+	return result.ID, nil
+}
+
+// waitReceipt polls the receipt of the given tx until it's packed or timeout.
+func (n *remoteNode) waitReceipt(txID ablock.Bytes32, timeout time.Duration) (*transactions.Receipt, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var receipt *transactions.Receipt
+		if err := n.get("/transactions/"+txID.String()+"/receipt", &receipt); err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("tx %v not packed in %v", txID, timeout)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// buildTx builds an unsigned tx of given clauses, which refers to the best block of the node.
+// The gas is estimated by simulating the clauses with the given origin.
+func (n *remoteNode) buildTx(origin ablock.Address, clauses []*tx.Clause) (*tx.Transaction, error) {
+	genesis, err := n.block("0")
+	if err != nil {
+		return nil, err
+	}
+	best, err := n.block("best")
+	if err != nil {
+		return nil, err
+	}
+
+	builder := new(tx.Builder).
+		ChainTag(genesis.ID[31]).
+		BlockRef(tx.NewBlockRefFromID(best.ID)).
+		Expiration(720).
+		Nonce(uint64(time.Now().UnixNano()))
+	for _, c := range clauses {
+		builder.Clause(c)
+	}
+	gas, err := n.estimateGas(best.ID.String(), &origin, builder, clauses)
+	if err != nil {
+		return nil, err
+	}
+	return builder.Gas(gas).Build(), nil
+}
+
+// estimateGas estimates the gas of the tx being built with the given clauses, by simulating the clauses at
+// the revision. A call forwards at most 63/64 of the remaining gas, so the gas used by each clause is scaled
+// by 64/63 to leave room for nested calls, then the margin is added for state-dependent execution.
+func (n *remoteNode) estimateGas(revision string, origin *ablock.Address, builder *tx.Builder, clauses []*tx.Clause) (uint64, error) {
+	results, err := n.call(revision, origin, clauses)
+	if err != nil {
+		return 0, errors.WithMessage(err, "estimate gas")
+	}
+	var gas uint64
+	for i, r := range results {
+		if r.Reverted {
+			return 0, fmt.Errorf("clause #%v reverted: %v", i, r.VMError)
+		}
+		gas += (r.GasUsed*64 + 62) / 63
+	}
+	gas += gas * n.gasMargin / 100
+
+	intrinsicGas, err := builder.Build().IntrinsicGas()
+	if err != nil {
+		return 0, err
+	}
+	return intrinsicGas + gas, nil
+}
+
+// signAndSend signs the tx with given key and submits it to the node.
+func (n *remoteNode) signAndSend(trx *tx.Transaction, key *ecdsa.PrivateKey) (ablock.Bytes32, error) {
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), key)
+	if err != nil {
+		return ablock.Bytes32{}, err
+	}
+	return n.sendTx(trx.WithSignature(sig))
+}
+
+// loadKeystore decrypts the JSON keystore file specified by the keystore flag.
+func loadKeystore(ctx *cli.Context) (*ecdsa.PrivateKey, error) {
+	path := ctx.String(keystoreFlag.Name)
+	if path == "" {
+		return nil, fmt.Errorf("keystore file required, use -%s to specify", keystoreFlag.Name)
+	}
+	return loadKeystoreFile(path, "Enter passphrase: ")
+}
+
+// loadKeystoreFile decrypts the JSON keystore file with the passphrase read from tty.
+func loadKeystoreFile(path, prompt string) (*ecdsa.PrivateKey, error) {
+	keyjson, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read keystore")
+	}
+	password, err := readPasswordFromNewTTY(prompt)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyjson, password)
+	if err != nil {
+		return nil, errors.WithMessage(err, "decrypt")
+	}
+	return key.PrivateKey, nil
+}
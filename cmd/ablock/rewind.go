@@ -0,0 +1,101 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/cmd/ablock/node"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var rewindCommand = cli.Command{
+	Name:  "rewind",
+	Usage: "rewind the best block to an earlier block on the best chain, the node must be stopped",
+	Flags: []cli.Flag{
+		networkFlag,
+		dataDirFlag,
+		cacheFlag,
+		disablePrunerFlag,
+		stateRetentionFlag,
+		verbosityFlag,
+		rewindToFlag,
+		rewindForceFlag,
+	},
+	Action: rewindAction,
+}
+
+func rewindAction(ctx *cli.Context) error {
+	initLogger(ctx)
+
+	str := ctx.String(rewindToFlag.Name)
+	if str == "" {
+		return fmt.Errorf("target block required, use -%s to specify", rewindToFlag.Name)
+	}
+
+	inst, err := openInstance(ctx)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+
+	targetID, err := parseBestChainBlock(inst.repo, str)
+	if err != nil {
+		return errors.Wrap(err, rewindToFlag.Name)
+	}
+	target, err := inst.repo.GetBlockSummary(targetID)
+	if err != nil {
+		return errors.Wrap(err, "get target block")
+	}
+	targetNum := target.Header.Number()
+	if best := inst.repo.BestBlockSummary().Header; targetNum >= best.Number() {
+		return fmt.Errorf("target block %v is not below the best block %v", targetNum, best.Number())
+	}
+
+	engine, err := bft.NewEngine(inst.repo, inst.mainDB, inst.forkConfig, inst.config, ablock.Address{})
+	if err != nil {
+		return errors.Wrap(err, "load bft engine")
+	}
+	if finalized := engine.Finalized(); targetNum < block.Number(finalized) {
+		if !ctx.Bool(rewindForceFlag.Name) {
+			return fmt.Errorf("target block is below the finalized block %v, use -%s to force", block.Number(finalized), rewindForceFlag.Name)
+		}
+		log.Warn("rewinding below the finalized block, only blocks on its chain will be accepted", "finalized", finalized)
+	}
+
+	// the node continues from the state of the target block
+	if retained, err := state.NewStater(inst.mainDB).IsRetained(targetNum); err != nil {
+		return err
+	} else if !retained {
+		return errors.New("state of the target block is pruned")
+	}
+
+	w := inst.logDB.NewWriter()
+	if err := w.Truncate(targetNum + 1); err != nil {
+		return errors.Wrap(err, "truncate log db")
+	}
+	if err := w.Commit(); err != nil {
+		return errors.Wrap(err, "commit log db")
+	}
+
+	n, err := node.DropStashedTxs(filepath.Join(inst.dir, "tx.stash"), targetNum)
+	if err != nil {
+		return errors.Wrap(err, "drop stashed txs")
+	}
+	log.Debug("dropped stashed txs", "count", n)
+
+	if err := inst.repo.SetBestBlockID(targetID); err != nil {
+		return errors.Wrap(err, "set best block")
+	}
+	fmt.Printf("best block rewound to %v (%v)\n", targetNum, targetID)
+	return nil
+}
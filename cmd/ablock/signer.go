@@ -0,0 +1,129 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/co"
+	"github.com/ashishaw/authorityblock/signer"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var signerCommand = cli.Command{
+	Name:  "signer",
+	Usage: "run the reference signer daemon, which keeps the master key and signs blocks for the node",
+	Flags: []cli.Flag{
+		configDirFlag,
+		signerListenFlag,
+		signerTokenFileFlag,
+		signerStateFlag,
+		verbosityFlag,
+	},
+	Action: signerAction,
+}
+
+func signerAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	initLogger(ctx)
+
+	keyPath, err := masterKeyPath(ctx)
+	if err != nil {
+		return err
+	}
+	key, err := loadOrGeneratePrivateKey(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "load or generate master key")
+	}
+
+	token, err := readTokenFile(ctx.String(signerTokenFileFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	statePath, listen := ctx.String(signerStateFlag.Name), ctx.String(signerListenFlag.Name)
+	if statePath == "" || listen == "" {
+		configDir, err := makeConfigDir(ctx)
+		if err != nil {
+			return err
+		}
+		if statePath == "" {
+			statePath = filepath.Join(configDir, "signer.state")
+		}
+		if listen == "" {
+			listen = "unix://" + filepath.Join(configDir, "signer.sock")
+		}
+	}
+	s, err := signer.NewProtected(signer.NewKeySigner(key), statePath)
+	if err != nil {
+		return errors.Wrap(err, "load signer state")
+	}
+
+	network, addr := "tcp", listen
+	if !strings.HasPrefix(addr, "unix://") {
+		// anyone reachable could sign blocks with the master key
+		if token == "" {
+			return fmt.Errorf("a bearer token is required to listen on %v, use -%v", addr, signerTokenFileFlag.Name)
+		}
+	} else {
+		network, addr = "unix", strings.TrimPrefix(addr, "unix://")
+		// remove the stale socket file
+		if err := os.Remove(addr); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return errors.Wrapf(err, "listen signer addr [%v]", addr)
+	}
+	if network == "unix" {
+		if err := os.Chmod(addr, 0600); err != nil {
+			listener.Close()
+			return err
+		}
+	}
+
+	srv := &http.Server{Handler: signer.NewHandler(s, token)}
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	defer func() { log.Info("stopping signer..."); srv.Close(); goes.Wait() }()
+
+	fmt.Printf(`Signer started
+    Master       [ %v ]
+    Listening    [ %v://%v ]
+    State file   [ %v ]
+`, s.Address(), network, addr, statePath)
+
+	<-exitSignal.Done()
+	return nil
+}
+
+// readTokenFile reads the bearer token from the file, it returns empty token if path is empty.
+func readTokenFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "read token file")
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("empty token in file [%v]", path)
+	}
+	return token, nil
+}
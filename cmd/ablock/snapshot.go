@@ -0,0 +1,172 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/cmd/ablock/snapshot"
+	"github.com/ashishaw/authorityblock/ablock"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var snapshotCommand = cli.Command{
+	Name:  "snapshot",
+	Usage: "export the state snapshot, or bootstrap a fresh node from a trusted snapshot",
+	Subcommands: []cli.Command{
+		{
+			Name:      "export",
+			Usage:     "export the state at a finalized block, along with recent blocks into the file",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				networkFlag,
+				dataDirFlag,
+				cacheFlag,
+				disablePrunerFlag,
+				stateRetentionFlag,
+				verbosityFlag,
+				snapshotBlockFlag,
+				snapshotRecentBlocksFlag,
+			},
+			Action: snapshotExportAction,
+		},
+		{
+			Name:      "import",
+			Usage:     "bootstrap a fresh node from the snapshot file",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				networkFlag,
+				dataDirFlag,
+				cacheFlag,
+				disablePrunerFlag,
+				stateRetentionFlag,
+				verbosityFlag,
+			},
+			Action: snapshotImportAction,
+		},
+	},
+}
+
+func snapshotExportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("file path required")
+	}
+
+	inst, err := openInstance(ctx)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+
+	headID, err := snapshotHeadID(ctx, inst)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "create snapshot file")
+	}
+	w := bufio.NewWriter(file)
+
+	fmt.Println(">> Exporting snapshot <<")
+	summary, err := snapshot.Export(exitSignal, w, inst.repo, inst.mainDB, headID, uint32(ctx.Uint(snapshotRecentBlocksFlag.Name)), inst.config.CheckpointInterval)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return errors.Wrap(err, "export snapshot")
+	}
+	fmt.Printf("snapshot exported at block %v (%v)\n", summary.Header.Number(), summary.Header.ID())
+	return nil
+}
+
+// snapshotHeadID returns the block which the snapshot is exported at or before.
+func snapshotHeadID(ctx *cli.Context, inst *instance) (ablock.Bytes32, error) {
+	str := ctx.String(snapshotBlockFlag.Name)
+	if str == "" {
+		engine, err := bft.NewEngine(inst.repo, inst.mainDB, inst.forkConfig, inst.config, ablock.Address{})
+		if err != nil {
+			return ablock.Bytes32{}, errors.Wrap(err, "load bft engine")
+		}
+		finalized := engine.Finalized()
+		if block.Number(finalized) == 0 {
+			return ablock.Bytes32{}, fmt.Errorf("no finalized block, use -%s to specify", snapshotBlockFlag.Name)
+		}
+		return finalized, nil
+	}
+	return parseBestChainBlock(inst.repo, str)
+}
+
+// parseBestChainBlock parses the block number or id, and returns the id of the block on the best chain.
+func parseBestChainBlock(repo *chain.Repository, str string) (ablock.Bytes32, error) {
+	bestChain := repo.NewBestChain()
+	if len(str) == 66 || len(str) == 64 {
+		id, err := ablock.ParseBytes32(str)
+		if err != nil {
+			return ablock.Bytes32{}, errors.Wrap(err, "parse block id")
+		}
+		if has, err := bestChain.HasBlock(id); err != nil {
+			return ablock.Bytes32{}, err
+		} else if !has {
+			return ablock.Bytes32{}, errors.New("block not on the best chain")
+		}
+		return id, nil
+	}
+	num, err := strconv.ParseUint(str, 0, 32)
+	if err != nil {
+		return ablock.Bytes32{}, errors.Wrap(err, "parse block number")
+	}
+	return bestChain.GetBlockID(uint32(num))
+}
+
+func snapshotImportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	initLogger(ctx)
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("file path required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "open snapshot file")
+	}
+	defer file.Close()
+
+	inst, err := openInstance(ctx)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+
+	if inst.repo.BestBlockSummary().Header.Number() != 0 {
+		return errors.New("snapshot can only be imported into a fresh data dir")
+	}
+
+	fmt.Println(">> Importing snapshot <<")
+	summary, err := snapshot.Import(exitSignal, bufio.NewReader(file), inst.repo, inst.mainDB, inst.logDB)
+	if err != nil {
+		return errors.Wrap(err, "import snapshot (the data dir should be removed before retrying)")
+	}
+	fmt.Printf("snapshot imported at block %v (%v)\n", summary.Header.Number(), summary.Header.ID())
+	return nil
+}
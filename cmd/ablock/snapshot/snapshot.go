@@ -0,0 +1,311 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package snapshot exports the state of a block along with recent blocks into a portable file,
+// and bootstraps a fresh node from it.
+//
+// A snapshot is a gzip compressed stream of RLP items, in the order of:
+//
+//	header, base block, ids of blocks before the base block, blocks after the base block,
+//	bft qualities, state records terminated by an end record.
+package snapshot
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/cmd/ablock/optimizer"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+const version = 1
+
+// kinds of state records
+const (
+	recordEnd = iota
+	recordAccount
+	recordStorage
+)
+
+type header struct {
+	Version   uint
+	GenesisID ablock.Bytes32
+	BlockID   ablock.Bytes32 // id of the block whose state is exported
+	BaseNum   uint32         // number of the first exported block
+}
+
+type blockEntry struct {
+	Block    *block.Block
+	Receipts tx.Receipts
+}
+
+type stateRecord struct {
+	Kind  uint
+	Key   []byte
+	Value []byte
+	Extra []byte // code of account or key preimage of storage
+}
+
+// Export writes the snapshot into w. The state is taken at the end of the latest bft round no later than
+// the given head block, so that bft state can be computed right after importing. At least recentBlocks
+// blocks are exported fully, other blocks are exported as ids.
+func Export(ctx context.Context, w io.Writer, repo *chain.Repository, db *muxdb.MuxDB, headID ablock.Bytes32, recentBlocks, checkpointInterval uint32) (*chain.BlockSummary, error) {
+	num := block.Number(headID)
+	for !bft.IsStorePoint(num, checkpointInterval) {
+		if num == 0 {
+			return nil, errors.New("no bft round ended")
+		}
+		num--
+	}
+
+	c := repo.NewChain(headID)
+	summary, err := c.GetBlockSummary(num)
+	if err != nil {
+		return nil, err
+	}
+
+	baseNum := uint32(1)
+	if num+1 > recentBlocks+baseNum {
+		baseNum = num + 1 - recentBlocks
+	}
+	// the poa seeder needs the block of the previous epoch
+	if epoch := (num + 1) / ablock.SeederInterval; epoch > 1 {
+		if seedNum := (epoch - 1) * ablock.SeederInterval; seedNum < baseNum {
+			baseNum = seedNum
+		}
+	}
+
+	gw := gzip.NewWriter(w)
+	if err := rlp.Encode(gw, &header{
+		Version:   version,
+		GenesisID: repo.GenesisBlock().Header().ID(),
+		BlockID:   summary.Header.ID(),
+		BaseNum:   baseNum,
+	}); err != nil {
+		return nil, err
+	}
+
+	writeBlock := func(n uint32) error {
+		b, err := c.GetBlock(n)
+		if err != nil {
+			return err
+		}
+		receipts, err := repo.GetBlockReceipts(b.Header().ID())
+		if err != nil {
+			return err
+		}
+		return rlp.Encode(gw, &blockEntry{b, receipts})
+	}
+
+	if err := writeBlock(baseNum); err != nil {
+		return nil, err
+	}
+	for n := uint32(1); n < baseNum; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		id, err := c.GetBlockID(n)
+		if err != nil {
+			return nil, err
+		}
+		if err := rlp.Encode(gw, id); err != nil {
+			return nil, err
+		}
+	}
+	for n := baseNum + 1; n <= num; n++ {
+		if err := writeBlock(n); err != nil {
+			return nil, err
+		}
+	}
+
+	qualities, err := bft.ExportQualities(repo, db, summary.Header.ID(), checkpointInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "export bft qualities")
+	}
+	if err := rlp.Encode(gw, qualities); err != nil {
+		return nil, err
+	}
+
+	if err := state.NewStater(db).Dump(ctx, summary.Header.StateRoot(), num, summary.Conflicts, &stateWriter{gw}); err != nil {
+		return nil, errors.Wrap(err, "dump state")
+	}
+	if err := rlp.Encode(gw, &stateRecord{Kind: recordEnd}); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Import reads the snapshot from r, and bootstraps the fresh repository and databases from it.
+// The databases should be dropped if any error returned.
+func Import(ctx context.Context, r io.Reader, repo *chain.Repository, db *muxdb.MuxDB, logDB *logdb.LogDB) (*chain.BlockSummary, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	stream := rlp.NewStream(gr, 0)
+
+	var h header
+	if err := stream.Decode(&h); err != nil {
+		return nil, errors.Wrap(err, "decode header")
+	}
+	if h.Version != version {
+		return nil, errors.Errorf("unsupported version %v", h.Version)
+	}
+	if h.GenesisID != repo.GenesisBlock().Header().ID() {
+		return nil, errors.New("genesis mismatch")
+	}
+	num := block.Number(h.BlockID)
+	if h.BaseNum == 0 || h.BaseNum > num {
+		return nil, errors.New("invalid base block number")
+	}
+
+	logWriter := logDB.NewWriterSyncOff()
+	readBlock := func() (*blockEntry, uint32, error) {
+		var entry blockEntry
+		if err := stream.Decode(&entry); err != nil {
+			return nil, 0, errors.Wrap(err, "decode block")
+		}
+		header := entry.Block.Header()
+		if header.TxsRoot() != entry.Block.Transactions().RootHash() {
+			return nil, 0, errors.New("txs root mismatch")
+		}
+		if header.ReceiptsRoot() != entry.Receipts.RootHash() {
+			return nil, 0, errors.New("receipts root mismatch")
+		}
+		conflicts, err := repo.ScanConflicts(header.Number())
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := logWriter.Write(entry.Block, entry.Receipts); err != nil {
+			return nil, 0, err
+		}
+		if logWriter.UncommittedCount() > 2048 {
+			if err := logWriter.Commit(); err != nil {
+				return nil, 0, err
+			}
+		}
+		return &entry, conflicts, nil
+	}
+
+	// import blocks
+	base, conflicts, err := readBlock()
+	if err != nil {
+		return nil, err
+	}
+	if base.Block.Header().Number() != h.BaseNum {
+		return nil, errors.New("base block number mismatch")
+	}
+	if err := repo.ImportBaseBlock(base.Block, base.Receipts, conflicts, func(num uint32) (id ablock.Bytes32, err error) {
+		if err = ctx.Err(); err == nil {
+			err = stream.Decode(&id)
+		}
+		return
+	}); err != nil {
+		return nil, errors.Wrap(err, "import base block")
+	}
+	// blocks after the base block must link up to the exported block
+	lastID := base.Block.Header().ID()
+	for n := h.BaseNum + 1; n <= num; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entry, conflicts, err := readBlock()
+		if err != nil {
+			return nil, err
+		}
+		if header := entry.Block.Header(); header.Number() != n || header.ParentID() != lastID {
+			return nil, errors.Errorf("block %v not linked", n)
+		}
+		lastID = entry.Block.Header().ID()
+		if err := repo.AddBlock(entry.Block, entry.Receipts, conflicts); err != nil {
+			return nil, errors.Wrap(err, "add block")
+		}
+	}
+	if lastID != h.BlockID {
+		return nil, errors.New("exported block id mismatch")
+	}
+	summary, err := repo.GetBlockSummary(h.BlockID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get block")
+	}
+
+	var qualities []*bft.Quality
+	if err := stream.Decode(&qualities); err != nil {
+		return nil, errors.Wrap(err, "decode bft qualities")
+	}
+	if err := bft.ImportQualities(db, qualities); err != nil {
+		return nil, errors.Wrap(err, "import bft qualities")
+	}
+
+	// rebuild state
+	restorer := state.NewStater(db).NewRestorer(num, summary.Conflicts)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var rec stateRecord
+		if err := stream.Decode(&rec); err != nil {
+			return nil, errors.Wrap(err, "decode state record")
+		}
+		if rec.Kind == recordEnd {
+			break
+		}
+		switch rec.Kind {
+		case recordAccount:
+			err = restorer.AddAccount(rec.Key, rec.Value, rec.Extra)
+		case recordStorage:
+			err = restorer.AddStorage(rec.Key, rec.Value, rec.Extra)
+		default:
+			err = errors.Errorf("unknown state record kind %v", rec.Kind)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "restore state")
+		}
+	}
+	root, err := restorer.Finish()
+	if err != nil {
+		return nil, errors.Wrap(err, "restore state")
+	}
+	if root != summary.Header.StateRoot() {
+		return nil, errors.Errorf("state root mismatch, want %v, got %v", summary.Header.StateRoot(), root)
+	}
+
+	if err := optimizer.SetBase(db, num); err != nil {
+		return nil, errors.Wrap(err, "set optimizer base")
+	}
+	if err := logWriter.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit logs")
+	}
+	if err := repo.SetBestBlockID(summary.Header.ID()); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// stateWriter writes dumped state as records.
+type stateWriter struct {
+	w io.Writer
+}
+
+func (sw *stateWriter) OnAccount(hashedKey []byte, data []byte, code []byte) error {
+	return rlp.Encode(sw.w, &stateRecord{recordAccount, hashedKey, data, code})
+}
+
+func (sw *stateWriter) OnStorage(hashedKey []byte, value []byte, keyPreimage []byte) error {
+	return rlp.Encode(sw.w, &stateRecord{recordStorage, hashedKey, value, keyPreimage})
+}
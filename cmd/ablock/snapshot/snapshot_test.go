@@ -0,0 +1,163 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+type testNode struct {
+	db     *muxdb.MuxDB
+	repo   *chain.Repository
+	stater *state.Stater
+	logDB  *logdb.LogDB
+}
+
+func newTestNode(t *testing.T) *testNode {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testNode{db, repo, stater, logDB}
+}
+
+func (n *testNode) packBlock(t *testing.T, txs ...*tx.Transaction) {
+	master := genesis.DevAccounts()[0]
+	best := n.repo.BestBlockSummary()
+	flow, err := packer.New(n.repo, n.stater, master.Address, &master.Address, ablock.NoFork, ablock.DefaultConfig).
+		Mock(best, best.Header.Timestamp()+ablock.DefaultConfig.BlockInterval, best.Header.GasLimit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range txs {
+		if err := flow.Adopt(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conflicts, err := n.repo.ScanConflicts(best.Header.Number() + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, stage, receipts, err := flow.Pack(master.PrivateKey, conflicts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.repo.AddBlock(b, receipts, conflicts); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.repo.SetBestBlockID(b.Header().ID()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	var (
+		src       = newTestNode(t)
+		recipient = ablock.BytesToAddress([]byte("recipient"))
+	)
+
+	for i := 0; i < 200; i++ {
+		var txs []*tx.Transaction
+		if i == 175 {
+			trx := new(tx.Builder).
+				ChainTag(src.repo.ChainTag()).
+				Expiration(1000).
+				Gas(21000).
+				Clause(tx.NewClause(&recipient).WithValue(big.NewInt(1000))).
+				Build()
+			sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txs = append(txs, trx.WithSignature(sig))
+		}
+		src.packBlock(t, txs...)
+	}
+
+	var buf bytes.Buffer
+	exported, err := Export(context.Background(), &buf, src.repo, src.db, src.repo.BestBlockSummary().Header.ID(), 10, ablock.DefaultConfig.CheckpointInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the end of the latest bft round
+	assert.Equal(t, uint32(179), exported.Header.Number())
+
+	dst := newTestNode(t)
+	imported, err := Import(context.Background(), &buf, dst.repo, dst.db, dst.logDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, exported.Header.ID(), imported.Header.ID())
+	assert.Equal(t, exported.Header.ID(), dst.repo.BestBlockSummary().Header.ID())
+
+	// ids of all blocks are indexed, but only recent blocks saved
+	srcChain, dstChain := src.repo.NewChain(exported.Header.ID()), dst.repo.NewChain(imported.Header.ID())
+	for _, num := range []uint32{1, 100, 169, 170, 179} {
+		id, err := dstChain.GetBlockID(num)
+		assert.Nil(t, err)
+		expected, _ := srcChain.GetBlockID(num)
+		assert.Equal(t, expected, id)
+	}
+	_, err = dstChain.GetBlockSummary(169)
+	assert.True(t, dst.repo.IsNotFound(err))
+	_, err = dstChain.GetBlockSummary(170)
+	assert.Nil(t, err)
+
+	srcState := src.stater.NewState(exported.Header.StateRoot(), exported.Header.Number(), exported.Conflicts, exported.SteadyNum)
+	dstState := dst.stater.NewState(imported.Header.StateRoot(), imported.Header.Number(), imported.Conflicts, imported.SteadyNum)
+	for _, addr := range []ablock.Address{recipient, genesis.DevAccounts()[0].Address} {
+		expected, _ := srcState.GetBalance(addr)
+		balance, err := dstState.GetBalance(addr)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, balance)
+	}
+	balance, _ := dstState.GetBalance(recipient)
+	assert.Equal(t, big.NewInt(1000), balance)
+
+	newest, err := dst.logDB.NewestBlockID()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(176), block.Number(newest), "block with the transfer log")
+
+	// able to continue packing on the imported chain
+	dst.packBlock(t)
+	assert.Equal(t, uint32(180), dst.repo.BestBlockSummary().Header.Number())
+
+	// can't import into a non-empty repository
+	buf.Reset()
+	if _, err := Export(context.Background(), &buf, src.repo, src.db, src.repo.BestBlockSummary().Header.ID(), 10, ablock.DefaultConfig.CheckpointInterval); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Import(context.Background(), &buf, dst.repo, dst.db, dst.logDB)
+	assert.NotNil(t, err)
+}
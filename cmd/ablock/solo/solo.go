@@ -33,9 +33,12 @@ var log = log15.New("pkg", "solo")
 type Solo struct {
 	repo      *chain.Repository
 	txPool    *txpool.TxPool
+	ordering  txpool.Ordering
+	reports   *packer.Reports
 	packer    *packer.Packer
 	logDB     *logdb.LogDB
 	gasLimit  uint64
+	interval  uint64
 	bandwidth bandwidth.Bandwidth
 	onDemand  bool
 	skipLogs  bool
@@ -47,22 +50,29 @@ func New(
 	stater *state.Stater,
 	logDB *logdb.LogDB,
 	txPool *txpool.TxPool,
+	ordering txpool.Ordering,
+	reports *packer.Reports,
 	gasLimit uint64,
 	onDemand bool,
 	skipLogs bool,
 	forkConfig ablock.ForkConfig,
+	config ablock.Config,
 ) *Solo {
 	return &Solo{
-		repo:   repo,
-		txPool: txPool,
+		repo:     repo,
+		txPool:   txPool,
+		ordering: ordering,
+		reports:  reports,
 		packer: packer.New(
 			repo,
 			stater,
 			genesis.DevAccounts()[0].Address,
 			&genesis.DevAccounts()[0].Address,
-			forkConfig),
+			forkConfig,
+			config),
 		logDB:    logDB,
 		gasLimit: gasLimit,
+		interval: config.BlockInterval,
 		skipLogs: skipLogs,
 		onDemand: onDemand,
 	}
@@ -93,12 +103,12 @@ func (s *Solo) loop(ctx context.Context) {
 			log.Info("stopping interval packing service......")
 			return
 		case <-time.After(time.Duration(1) * time.Second):
-			if left := uint64(time.Now().Unix()) % ablock.BlockInterval; left == 0 {
-				if err := s.packing(s.txPool.Executables(), false); err != nil {
+			if left := uint64(time.Now().Unix()) % s.interval; left == 0 {
+				if err := s.packing(s.ordering.Order(s.txPool.ExecutableTxs()), false); err != nil {
 					log.Error("failed to pack block", "err", err)
 				}
 			} else if s.onDemand {
-				pendingTxs := s.txPool.Executables()
+				pendingTxs := s.ordering.Order(s.txPool.ExecutableTxs())
 				if len(pendingTxs) > 0 {
 					if err := s.packing(pendingTxs, true); err != nil {
 						log.Error("failed to pack block", "err", err)
@@ -192,6 +202,11 @@ func (s *Solo) packing(pendingTxs tx.Transactions, onDemand bool) error {
 		"et", fmt.Sprintf("%v|%v", common.PrettyDuration(execElapsed), common.PrettyDuration(commitElapsed)),
 		"id", fmt.Sprintf("[#%v…%x]", block.Number(blockID), blockID[28:]),
 	)
+	report := flow.Report()
+	report.ID = blockID
+	report.ExecTime = time.Duration(execElapsed)
+	s.reports.Add(report)
+	log.Debug("packing report", report.LogContext()...)
 	log.Debug(b.String())
 
 	return nil
@@ -0,0 +1,57 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/cmd/ablock/optimizer"
+	"github.com/ashishaw/authorityblock/comm"
+	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// stateSyncRecentBlocks is the number of recent blocks downloaded fully along with the state.
+const stateSyncRecentBlocks = 1000
+
+// syncStateFromPeers bootstraps the fresh node by downloading state from peers at the trusted pivot block.
+// It's skipped if the node is not fresh, so the flag can be kept across restarts.
+func syncStateFromPeers(
+	ctx context.Context,
+	pivot string,
+	p2pComm *comm.Communicator,
+	repo *chain.Repository,
+	mainDB *muxdb.MuxDB,
+	logDB *logdb.LogDB,
+	skipLogs bool,
+) error {
+	pivotID, err := ablock.ParseBytes32(pivot)
+	if err != nil {
+		return errors.Wrap(err, stateSyncFlag.Name)
+	}
+	if best := repo.BestBlockSummary().Header; best.Number() != 0 {
+		log.Info("state sync skipped, the node is not fresh", "best", best.Number())
+		return nil
+	}
+
+	log.Info("start state sync", "pivot", pivotID)
+	summary, err := p2pComm.SyncState(ctx, pivotID, stateSyncRecentBlocks)
+	if err != nil {
+		return errors.Wrap(err, "state sync (the data dir should be removed before retrying)")
+	}
+	if err := optimizer.SetBase(mainDB, summary.Header.Number()); err != nil {
+		return errors.Wrap(err, "set optimizer base")
+	}
+	log.Info("state sync done", "block", summary.Header.Number(), "id", summary.Header.ID())
+
+	if !skipLogs {
+		return syncLogDB(ctx, repo, logDB, false)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/pmezard/go-difflib/difflib"
@@ -27,8 +28,12 @@ func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB,
 	if err != nil {
 		return errors.Wrap(err, "seek log db sync position")
 	}
-	if verify && startPos > 0 {
-		if err := verifyLogDB(ctx, startPos-1, repo, logDB); err != nil {
+	firstNum, err := seekFirstBlockNum(repo)
+	if err != nil {
+		return errors.Wrap(err, "seek first block")
+	}
+	if verify && startPos > firstNum {
+		if err := verifyLogDB(ctx, firstNum, startPos-1, repo, logDB); err != nil {
 			return errors.Wrap(err, "verify log db")
 		}
 	}
@@ -41,9 +46,9 @@ func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB,
 		return nil
 	}
 
-	if startPos == 0 {
+	if startPos < firstNum {
 		fmt.Println(">> Rebuilding log db <<")
-		startPos = 1 // block 0 can be skipped
+		startPos = firstNum
 	} else {
 		fmt.Println(">> Syncing log db <<")
 	}
@@ -154,10 +159,36 @@ func seekLogDBSyncPosition(repo *chain.Repository, logDB *logdb.LogDB) (uint32,
 	return block.Number(header.ID()) + 1, nil
 }
 
-func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository, logDB *logdb.LogDB) error {
+// seekFirstBlockNum returns the number of the first block after genesis saved in the repository.
+// Blocks before the snapshot are absent if the repository is bootstrapped from a snapshot.
+func seekFirstBlockNum(repo *chain.Repository) (uint32, error) {
+	var (
+		best = repo.BestBlockSummary().Header
+		c    = repo.NewChain(best.ID())
+		err  error
+	)
+	if best.Number() == 0 {
+		return 1, nil
+	}
+	n := sort.Search(int(best.Number()), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		if _, e := c.GetBlockSummary(uint32(i) + 1); e != nil {
+			if !repo.IsNotFound(e) {
+				err = e
+			}
+			return err != nil
+		}
+		return true
+	})
+	return uint32(n) + 1, err
+}
+
+func verifyLogDB(ctx context.Context, startBlockNum, endBlockNum uint32, repo *chain.Repository, logDB *logdb.LogDB) error {
 	fmt.Println(">> Verifying log db <<")
 	pb := pb.New64(int64(endBlockNum)).
-		Set64(0).
+		Set64(int64(startBlockNum - 1)).
 		SetMaxWidth(90).
 		Start()
 	defer func() { pb.NotPrint = true }()
@@ -168,7 +199,7 @@ func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository
 		best        = repo.BestBlockSummary()
 		evLogs      []*logdb.Event
 		trLogs      []*logdb.Transfer
-		logLimit    = uint32(0)
+		logLimit    = startBlockNum - 1
 		splitEvLogs = func(id ablock.Bytes32) (logs []*logdb.Event) {
 			if len(evLogs) == 0 {
 				return
@@ -216,7 +247,7 @@ func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository
 	defer goes.Wait()
 	goes.Go(func() {
 		defer close(ch)
-		pumpErr = pumpBlockAndReceipts(ctx, repo, best.Header.ID(), 1, endBlockNum, ch)
+		pumpErr = pumpBlockAndReceipts(ctx, repo, best.Header.ID(), startBlockNum, endBlockNum, ch)
 	})
 
 	defer cancel()
@@ -0,0 +1,504 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/abi"
+	"github.com/ashishaw/authorityblock/api/delegator"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var txCommand = cli.Command{
+	Name:  "tx",
+	Usage: "build, sign, decode and send transactions",
+	Subcommands: []cli.Command{
+		{
+			Name:      "build",
+			Usage:     "build an unsigned tx from a JSON clause list, and print the raw tx",
+			ArgsUsage: "<clauses-file|->",
+			Flags: []cli.Flag{
+				nodeURLFlag,
+				txOriginFlag,
+				txGasFlag,
+				txGasMarginFlag,
+				txGasPriceCoefFlag,
+				txExpirationFlag,
+				txDependsOnFlag,
+				txDelegatedFlag,
+			},
+			Action: txBuildAction,
+		},
+		{
+			Name:      "sign",
+			Usage:     "sign a raw tx as the origin and/or the delegator, and print the signed raw tx",
+			ArgsUsage: "<raw-tx|->",
+			Flags:     []cli.Flag{keystoreFlag, delegatorKeystoreFlag, delegatorURLFlag},
+			Action:    txSignAction,
+		},
+		{
+			Name:      "decode",
+			Usage:     "decode a raw tx",
+			ArgsUsage: "<raw-tx|->",
+			Action:    txDecodeAction,
+		},
+		{
+			Name:      "send",
+			Usage:     "submit a signed raw tx to the node",
+			ArgsUsage: "<raw-tx|->",
+			Flags:     []cli.Flag{nodeURLFlag, txWaitFlag},
+			Action:    txSendAction,
+		},
+	},
+}
+
+// jsonClause is the clause in JSON form to build a tx. The call data is either given in hex,
+// or encoded from the method and args according to the ABI.
+//
+// e.g.
+//
+//	[{
+//	  "to": "0x0000000000000000000000000000456e65726779",
+//	  "abi": {"type": "function", "name": "transfer", "inputs": [{"name": "_to", "type": "address"}, {"name": "_amount", "type": "uint256"}]},
+//	  "args": ["0x7567d83b7b8d80addcb281a71d54fc7b3364ffed", "1000000000000000000"]
+//	}]
+type jsonClause struct {
+	To     *ablock.Address       `json:"to"`
+	Value  *math.HexOrDecimal256 `json:"value"`
+	Data   hexutil.Bytes         `json:"data"`
+	ABI    json.RawMessage       `json:"abi"`    // ABI of the method, or the whole contract ABI
+	Method string                `json:"method"` // name of the method, can be omitted if the ABI has only one method
+	Args   []interface{}         `json:"args"`
+}
+
+func (c *jsonClause) clause() (*tx.Clause, error) {
+	clause := tx.NewClause(c.To)
+	if c.Value != nil {
+		clause = clause.WithValue((*big.Int)(c.Value))
+	}
+	if len(c.ABI) == 0 {
+		if c.Method != "" || len(c.Args) > 0 {
+			return nil, errors.New("abi required to encode method call")
+		}
+		return clause.WithData(c.Data), nil
+	}
+	if len(c.Data) > 0 {
+		return nil, errors.New("data and abi are mutually exclusive")
+	}
+	data, err := encodeCall(c.ABI, c.Method, c.Args)
+	if err != nil {
+		return nil, err
+	}
+	return clause.WithData(data), nil
+}
+
+// encodeCall encodes the call data of the method with args in JSON form.
+func encodeCall(abiJSON json.RawMessage, name string, args []interface{}) ([]byte, error) {
+	if trimmed := bytes.TrimSpace(abiJSON); len(trimmed) > 0 && trimmed[0] == '{' {
+		abiJSON = append(append([]byte{'['}, trimmed...), ']')
+	}
+	contractABI, err := abi.New(abiJSON)
+	if err != nil {
+		return nil, errors.WithMessage(err, "abi")
+	}
+	// abi.Method hides the input types, which are required to convert the args
+	var fields []struct {
+		Type   string
+		Name   string
+		Inputs []ethabi.Argument
+	}
+	if err := json.Unmarshal(abiJSON, &fields); err != nil {
+		return nil, errors.WithMessage(err, "abi")
+	}
+	var (
+		matched string
+		inputs  []ethabi.Argument
+		found   int
+	)
+	for _, f := range fields {
+		if (f.Type == "function" || f.Type == "") && (name == "" || f.Name == name) {
+			matched, inputs = f.Name, f.Inputs
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("method '%v' not found in abi", name)
+	}
+	if found > 1 {
+		return nil, errors.New("method name required")
+	}
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("method '%v' requires %v args, got %v", matched, len(inputs), len(args))
+	}
+
+	values := make([]interface{}, 0, len(args))
+	for i, arg := range args {
+		v, err := convertABIArg(inputs[i].Type, arg)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "arg #%v", i)
+		}
+		values = append(values, v.Interface())
+	}
+	method, _ := contractABI.MethodByName(matched)
+	return method.EncodeInput(values...)
+}
+
+// convertABIArg converts the JSON value into the go value of the ABI type.
+// Numbers are given in decimal or hex string, and bytes in hex string.
+func convertABIArg(t ethabi.Type, v interface{}) (reflect.Value, error) {
+	str := func() (string, error) {
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case json.Number:
+			return v.String(), nil
+		}
+		return "", fmt.Errorf("expected string for %v, got %v", t, v)
+	}
+	switch t.T {
+	case ethabi.IntTy, ethabi.UintTy:
+		s, err := str()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		n, ok := math.ParseBig256(s)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid %v '%v'", t, s)
+		}
+		if t.T == ethabi.UintTy && n.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("negative %v '%v'", t, s)
+		}
+		if t.Type == reflect.TypeOf(&big.Int{}) {
+			// values of the signed type range in [-2^(size-1), 2^(size-1))
+			bits := n.BitLen()
+			if t.T == ethabi.IntTy {
+				if n.Sign() < 0 {
+					bits = new(big.Int).Not(n).BitLen()
+				}
+				bits++ // the sign bit
+			}
+			if bits > t.Size {
+				return reflect.Value{}, fmt.Errorf("%v overflow '%v'", t, s)
+			}
+			return reflect.ValueOf(n), nil
+		}
+		val := reflect.New(t.Type).Elem()
+		if t.T == ethabi.UintTy {
+			if !n.IsUint64() || val.OverflowUint(n.Uint64()) {
+				return reflect.Value{}, fmt.Errorf("%v overflow '%v'", t, s)
+			}
+			val.SetUint(n.Uint64())
+		} else {
+			if !n.IsInt64() || val.OverflowInt(n.Int64()) {
+				return reflect.Value{}, fmt.Errorf("%v overflow '%v'", t, s)
+			}
+			val.SetInt(n.Int64())
+		}
+		return val, nil
+	case ethabi.BoolTy:
+		b, ok := v.(bool)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected bool, got %v", v)
+		}
+		return reflect.ValueOf(b), nil
+	case ethabi.StringTy:
+		s, ok := v.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected string, got %v", v)
+		}
+		return reflect.ValueOf(s), nil
+	case ethabi.AddressTy:
+		s, err := str()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		addr, err := ablock.ParseAddress(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(common.Address(addr)), nil
+	case ethabi.BytesTy, ethabi.FixedBytesTy:
+		s, err := str()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == ethabi.BytesTy {
+			return reflect.ValueOf(b), nil
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %v bytes for %v, got %v", t.Size, t, len(b))
+		}
+		val := reflect.New(t.Type).Elem()
+		reflect.Copy(val, reflect.ValueOf(b))
+		return val, nil
+	case ethabi.SliceTy, ethabi.ArrayTy:
+		elems, ok := v.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected array for %v, got %v", t, v)
+		}
+		var val reflect.Value
+		if t.T == ethabi.SliceTy {
+			val = reflect.MakeSlice(t.Type, len(elems), len(elems))
+		} else {
+			if len(elems) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %v elements for %v, got %v", t.Size, t, len(elems))
+			}
+			val = reflect.New(t.Type).Elem()
+		}
+		for i, elem := range elems {
+			ev, err := convertABIArg(*t.Elem, elem)
+			if err != nil {
+				return reflect.Value{}, errors.WithMessagef(err, "element #%v", i)
+			}
+			val.Index(i).Set(ev)
+		}
+		return val, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %v", t)
+}
+
+// readArg reads the first arg, or reads from stdin if it's '-'.
+func readArg(ctx *cli.Context, name string) ([]byte, error) {
+	if ctx.NArg() != 1 {
+		return nil, fmt.Errorf("requires arg <%v>", name)
+	}
+	if arg := ctx.Args().First(); arg != "-" {
+		return []byte(arg), nil
+	}
+	return ioutil.ReadAll(os.Stdin)
+}
+
+// readRawTx decodes the raw tx in hex given by the first arg.
+func readRawTx(ctx *cli.Context) (*tx.Transaction, error) {
+	arg, err := readArg(ctx, "raw-tx")
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hexutil.Decode(strings.TrimSpace(string(arg)))
+	if err != nil {
+		return nil, errors.WithMessage(err, "raw tx")
+	}
+	var trx *tx.Transaction
+	if err := rlp.DecodeBytes(raw, &trx); err != nil {
+		return nil, errors.WithMessage(err, "decode tx")
+	}
+	return trx, nil
+}
+
+func printRawTx(trx *tx.Transaction) error {
+	raw, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hexutil.Encode(raw))
+	return nil
+}
+
+func txBuildAction(ctx *cli.Context) error {
+	path, err := readArg(ctx, "clauses-file")
+	if err != nil {
+		return err
+	}
+	data := path
+	if ctx.Args().First() != "-" {
+		if data, err = ioutil.ReadFile(string(path)); err != nil {
+			return errors.Wrap(err, "read clauses")
+		}
+	}
+	// keep big numbers in args precise
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var jsonClauses []*jsonClause
+	if err := dec.Decode(&jsonClauses); err != nil {
+		return errors.WithMessage(err, "decode clauses")
+	}
+
+	gasPriceCoef := ctx.Uint(txGasPriceCoefFlag.Name)
+	if gasPriceCoef > 255 {
+		return errors.New("gas price coef out of range")
+	}
+	builder := new(tx.Builder).
+		GasPriceCoef(uint8(gasPriceCoef)).
+		Expiration(uint32(ctx.Uint(txExpirationFlag.Name))).
+		Nonce(uint64(time.Now().UnixNano()))
+
+	clauses := make([]*tx.Clause, 0, len(jsonClauses))
+	for i, c := range jsonClauses {
+		clause, err := c.clause()
+		if err != nil {
+			return errors.WithMessagef(err, "clause #%v", i)
+		}
+		clauses = append(clauses, clause)
+		builder.Clause(clause)
+	}
+	if s := ctx.String(txDependsOnFlag.Name); s != "" {
+		dependsOn, err := ablock.ParseBytes32(s)
+		if err != nil {
+			return errors.WithMessage(err, "depends on")
+		}
+		builder.DependsOn(&dependsOn)
+	}
+	if ctx.Bool(txDelegatedFlag.Name) {
+		var features tx.Features
+		features.SetDelegated(true)
+		builder.Features(features)
+	}
+
+	node := newRemoteNode(ctx)
+	genesis, err := node.block("0")
+	if err != nil {
+		return err
+	}
+	best, err := node.block("best")
+	if err != nil {
+		return err
+	}
+	builder.ChainTag(genesis.ID[31]).BlockRef(tx.NewBlockRefFromID(best.ID))
+
+	gas := ctx.Uint64(txGasFlag.Name)
+	if gas == 0 {
+		// estimate by simulating clauses at the best block
+		var origin *ablock.Address
+		if s := ctx.String(txOriginFlag.Name); s != "" {
+			addr, err := ablock.ParseAddress(s)
+			if err != nil {
+				return errors.WithMessage(err, "origin")
+			}
+			origin = &addr
+		}
+		if gas, err = node.estimateGas(best.ID.String(), origin, builder, clauses); err != nil {
+			return err
+		}
+	}
+	return printRawTx(builder.Gas(gas).Build())
+}
+
+func txSignAction(ctx *cli.Context) error {
+	trx, err := readRawTx(ctx)
+	if err != nil {
+		return err
+	}
+	signingHash := trx.SigningHash()
+
+	// sign as the origin with the keystore, or keep the existing origin signature
+	var (
+		origin    ablock.Address
+		originSig []byte
+	)
+	if ctx.String(keystoreFlag.Name) != "" {
+		key, err := loadKeystore(ctx)
+		if err != nil {
+			return err
+		}
+		if originSig, err = crypto.Sign(signingHash.Bytes(), key); err != nil {
+			return err
+		}
+		origin = ablock.Address(crypto.PubkeyToAddress(key.PublicKey))
+	} else if sig := trx.Signature(); len(sig) >= 65 {
+		pub, err := crypto.SigToPub(signingHash.Bytes(), sig[:65])
+		if err != nil {
+			return errors.WithMessage(err, "recover origin")
+		}
+		originSig = sig[:65]
+		origin = ablock.Address(crypto.PubkeyToAddress(*pub))
+	} else {
+		return fmt.Errorf("tx not signed by the origin, use -%v to specify the keystore of the origin", keystoreFlag.Name)
+	}
+
+	var (
+		delegatorKeystore = ctx.String(delegatorKeystoreFlag.Name)
+		delegatorURL      = ctx.String(delegatorURLFlag.Name)
+		delegatorSig      []byte
+	)
+	if delegatorKeystore != "" && delegatorURL != "" {
+		return fmt.Errorf("-%v and -%v are mutually exclusive", delegatorKeystoreFlag.Name, delegatorURLFlag.Name)
+	}
+	if delegatorKeystore != "" || delegatorURL != "" {
+		if !trx.Features().IsDelegated() {
+			return errors.New("tx is not delegated")
+		}
+		if delegatorKeystore != "" {
+			key, err := loadKeystoreFile(delegatorKeystore, "Enter delegator passphrase: ")
+			if err != nil {
+				return err
+			}
+			if delegatorSig, err = crypto.Sign(trx.DelegatorSigningHash(origin).Bytes(), key); err != nil {
+				return err
+			}
+		} else {
+			raw, err := rlp.EncodeToBytes(trx.WithSignature(originSig))
+			if err != nil {
+				return err
+			}
+			var result delegator.SignResult
+			service := &remoteNode{url: delegatorURL, client: newRemoteNode(ctx).client}
+			if err := service.post("", &delegator.SignRequest{Raw: raw, Origin: origin}, &result); err != nil {
+				return errors.WithMessage(err, "delegator")
+			}
+			delegatorSig = result.Signature
+		}
+	}
+
+	return printRawTx(trx.WithSignature(append(append([]byte(nil), originSig...), delegatorSig...)))
+}
+
+func txDecodeAction(ctx *cli.Context) error {
+	trx, err := readRawTx(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Print(trx)
+	return nil
+}
+
+func txSendAction(ctx *cli.Context) error {
+	trx, err := readRawTx(ctx)
+	if err != nil {
+		return err
+	}
+	node := newRemoteNode(ctx)
+	txID, err := node.sendTx(trx)
+	if err != nil {
+		return err
+	}
+	fmt.Println(txID)
+
+	if !ctx.Bool(txWaitFlag.Name) {
+		return nil
+	}
+	receipt, err := node.waitReceipt(txID, time.Minute)
+	if err != nil {
+		return err
+	}
+	status := "succeeded"
+	if receipt.Reverted {
+		status = "reverted"
+	}
+	fmt.Printf("Transaction %v in block #%v %v, gas used %v\n", status, receipt.Meta.BlockNumber, receipt.Meta.BlockID, receipt.GasUsed)
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/abi"
+)
+
+const testABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"setTag","inputs":[{"name":"tag","type":"bytes4"}],"outputs":[]}
+]`
+
+func TestEncodeCall(t *testing.T) {
+	contractABI, err := abi.New([]byte(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+
+	transfer, _ := contractABI.MethodByName("transfer")
+	want, err := transfer.EncodeInput(to, big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := encodeCall(json.RawMessage(testABI), "transfer", []interface{}{to.Hex(), "100"})
+	assert.Nil(t, err)
+	assert.Equal(t, want, data)
+
+	setTag, _ := contractABI.MethodByName("setTag")
+	want, err = setTag.EncodeInput([4]byte{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = encodeCall(json.RawMessage(testABI), "setTag", []interface{}{"0x01020304"})
+	assert.Nil(t, err)
+	assert.Equal(t, want, data)
+
+	// the only method is chosen without the name
+	single := `{"type":"function","name":"setTag","inputs":[{"name":"tag","type":"bytes4"}],"outputs":[]}`
+	data, err = encodeCall(json.RawMessage(single), "", []interface{}{"0x01020304"})
+	assert.Nil(t, err)
+	assert.Equal(t, want, data)
+
+	_, err = encodeCall(json.RawMessage(testABI), "", []interface{}{"0x01020304"})
+	assert.EqualError(t, err, "method name required")
+
+	_, err = encodeCall(json.RawMessage(testABI), "approve", nil)
+	assert.EqualError(t, err, "method 'approve' not found in abi")
+
+	_, err = encodeCall(json.RawMessage(testABI), "transfer", []interface{}{to.Hex()})
+	assert.EqualError(t, err, "method 'transfer' requires 2 args, got 1")
+
+	_, err = encodeCall(json.RawMessage(testABI), "setTag", []interface{}{"0x0102"})
+	assert.NotNil(t, err)
+}
+
+func TestConvertABIArg(t *testing.T) {
+	tests := []struct {
+		typ  string
+		arg  interface{}
+		want interface{}
+	}{
+		{"uint8", "255", uint8(255)},
+		{"uint8", json.Number("0xff"), uint8(255)},
+		{"uint8", "256", nil},
+		{"uint8", "-1", nil},
+		{"int8", "-128", int8(-128)},
+		{"int8", "127", int8(127)},
+		{"int8", "128", nil},
+		{"int8", "-129", nil},
+		{"uint72", "0xffffffffffffffffff", new(big.Int).SetBytes(common.FromHex("0xffffffffffffffffff"))},
+		{"uint72", "0x1000000000000000000", nil},
+		{"int256", "-57896044618658097711785492504343953926634992332820282019728792003956564819968", new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))},
+		{"int256", "0x8000000000000000000000000000000000000000000000000000000000000000", nil},
+		{"uint256", "0x10000000000000000000000000000000000000000000000000000000000000000", nil},
+		{"bytes4", "0x01020304", [4]byte{1, 2, 3, 4}},
+		{"bytes4", "0x0102", nil},
+		{"bytes4", "0x0102030405", nil},
+		{"bytes", "0x0102", []byte{1, 2}},
+		{"bool", true, true},
+		{"bool", "true", nil},
+		{"uint8[2]", []interface{}{"1", "2"}, [2]uint8{1, 2}},
+		{"uint8[2]", []interface{}{"1"}, nil},
+		{"uint8[]", []interface{}{"1", "256"}, nil},
+	}
+	for _, tt := range tests {
+		typ, err := ethabi.NewType(tt.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := convertABIArg(typ, tt.arg)
+		if tt.want == nil {
+			assert.NotNil(t, err, "%v %v", tt.typ, tt.arg)
+			continue
+		}
+		if assert.Nil(t, err, "%v %v", tt.typ, tt.arg) {
+			assert.Equal(t, tt.want, v.Interface(), "%v %v", tt.typ, tt.arg)
+		}
+	}
+}
@@ -36,15 +36,21 @@ import (
 	"github.com/inconshreveable/log15"
 	tty "github.com/mattn/go-tty"
 	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/api/blocks"
+	"github.com/ashishaw/authorityblock/api/delegator"
 	"github.com/ashishaw/authorityblock/api/doc"
+	"github.com/ashishaw/authorityblock/block"
 	"github.com/ashishaw/authorityblock/chain"
 	"github.com/ashishaw/authorityblock/cmd/ablock/node"
+	"github.com/ashishaw/authorityblock/cmd/ablock/optimizer"
 	"github.com/ashishaw/authorityblock/co"
 	"github.com/ashishaw/authorityblock/comm"
 	"github.com/ashishaw/authorityblock/genesis"
 	"github.com/ashishaw/authorityblock/logdb"
+	"github.com/ashishaw/authorityblock/metric"
 	"github.com/ashishaw/authorityblock/muxdb"
 	"github.com/ashishaw/authorityblock/p2psrv"
+	"github.com/ashishaw/authorityblock/signer"
 	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
@@ -224,7 +230,6 @@ func selectGenesis(ctx *cli.Context) (*genesis.Genesis, ablock.ForkConfig, error
 		if err != nil {
 			return nil, ablock.ForkConfig{}, errors.Wrap(err, "build genesis")
 		}
-
 		return customGen, forkConfig, nil
 	}
 }
@@ -246,8 +251,12 @@ func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
 		return "", fmt.Errorf("unable to infer default data dir, use -%s to specify", dataDirFlag.Name)
 	}
 
+	retention, err := parseRetention(ctx)
+	if err != nil {
+		return "", err
+	}
 	suffix := ""
-	if ctx.Bool(disablePrunerFlag.Name) {
+	if retention.Archive {
 		suffix = "-full"
 	}
 
@@ -258,7 +267,32 @@ func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
 	return instanceDir, nil
 }
 
+// parseRetention parses the state retention policy. The disable-pruner flag is equivalent to 'archive'.
+func parseRetention(ctx *cli.Context) (*optimizer.Retention, error) {
+	retention, err := optimizer.ParseRetention(ctx.String(stateRetentionFlag.Name))
+	if err != nil {
+		return nil, errors.Wrap(err, stateRetentionFlag.Name)
+	}
+	if ctx.Bool(disablePrunerFlag.Name) {
+		if ctx.IsSet(stateRetentionFlag.Name) && !retention.Archive {
+			return nil, fmt.Errorf("%v conflicts with %v=%v", disablePrunerFlag.Name, stateRetentionFlag.Name, retention)
+		}
+		return &optimizer.Retention{Archive: true}, nil
+	}
+	return retention, nil
+}
+
 func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
+	return openMainDBAt(ctx, filepath.Join(dir, "main.db"), ctx.String(dbEngineFlag.Name))
+}
+
+// openMainDBAt opens the main database at the given path with the engine. The engine of the existing database
+// is used if engine is empty.
+func openMainDBAt(ctx *cli.Context, path, engine string) (*muxdb.MuxDB, error) {
+	retention, err := parseRetention(ctx)
+	if err != nil {
+		return nil, err
+	}
 	cacheMB := normalizeCacheSize(ctx.Int(cacheFlag.Name))
 	log.Debug("cache size(MB)", "size", cacheMB)
 
@@ -271,10 +305,12 @@ func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
 		TrieCachedNodeTTL:          30, // 5min
 		TrieLeafBankSlotCapacity:   256,
 		TrieDedupedPartitionFactor: math.MaxUint32,
-		TrieWillCleanHistory:       !ctx.Bool(disablePrunerFlag.Name),
+		TrieWillCleanHistory:       !retention.Archive,
+		TrieUseArchive:             !retention.Archive && retention.Checkpoint > 0,
 		OpenFilesCacheCapacity:     fdCache,
 		ReadCacheMB:                256, // rely on os page cache other than huge db read cache.
 		WriteBufferMB:              128,
+		Engine:                     engine,
 	}
 
 	// go-ethereum stuff
@@ -291,7 +327,6 @@ func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
 		opts.TrieHistPartitionFactor = 500000
 	}
 
-	path := filepath.Join(dir, "main.db")
 	db, err := muxdb.Open(path, &opts)
 	if err != nil {
 		return nil, errors.Wrapf(err, "open main database [%v]", path)
@@ -387,6 +422,14 @@ func beneficiary(ctx *cli.Context) (*ablock.Address, error) {
 	return &addr, nil
 }
 
+func txOrdering(ctx *cli.Context) (txpool.Ordering, error) {
+	lanes, err := txpool.ParseLanes(ctx.String(txPriorityLanesFlag.Name))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid priority lanes")
+	}
+	return txpool.NewOrdering(ctx.String(txOrderingFlag.Name), lanes)
+}
+
 func masterKeyPath(ctx *cli.Context) (string, error) {
 	configDir, err := makeConfigDir(ctx)
 	if err != nil {
@@ -396,19 +439,34 @@ func masterKeyPath(ctx *cli.Context) (string, error) {
 }
 
 func loadNodeMaster(ctx *cli.Context) (*node.Master, error) {
-	path, err := masterKeyPath(ctx)
-	if err != nil {
-		return nil, err
-	}
-	key, err := loadOrGeneratePrivateKey(path)
-	if err != nil {
-		return nil, errors.Wrap(err, "load or generate master key")
+	var master node.Master
+	if url := ctx.String(remoteSignerFlag.Name); url != "" {
+		token, err := readTokenFile(ctx.String(remoteSignerTokenFileFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		s, err := signer.NewClient(url, token)
+		if err != nil {
+			return nil, errors.Wrap(err, "connect remote signer")
+		}
+		master.Signer = s
+	} else {
+		path, err := masterKeyPath(ctx)
+		if err != nil {
+			return nil, err
+		}
+		key, err := loadOrGeneratePrivateKey(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "load or generate master key")
+		}
+		master.Signer = signer.NewKeySigner(key)
 	}
-	master := &node.Master{PrivateKey: key}
+
+	var err error
 	if master.Beneficiary, err = beneficiary(ctx); err != nil {
 		return nil, err
 	}
-	return master, nil
+	return &master, nil
 }
 
 type p2pComm struct {
@@ -418,7 +476,7 @@ type p2pComm struct {
 	enode          string
 }
 
-func newP2PComm(ctx *cli.Context, repo *chain.Repository, txPool *txpool.TxPool, instanceDir string) (*p2pComm, error) {
+func newP2PComm(ctx *cli.Context, repo *chain.Repository, mainDB *muxdb.MuxDB, txPool *txpool.TxPool, instanceDir string, config ablock.Config) (*p2pComm, error) {
 	configDir, err := makeConfigDir(ctx)
 	if err != nil {
 		return nil, err
@@ -470,7 +528,7 @@ func newP2PComm(ctx *cli.Context, repo *chain.Repository, txPool *txpool.TxPool,
 	}
 
 	return &p2pComm{
-		comm:           comm.New(repo, txPool),
+		comm:           comm.New(repo, txPool, mainDB, config),
 		p2pSrv:         p2psrv.New(opts),
 		peersCachePath: peersCachePath,
 		enode:          fmt.Sprintf("enode://%x@[extip]:%v", discover.PubkeyID(&key.PublicKey).Bytes(), ctx.Int(p2pPortFlag.Name)),
@@ -505,6 +563,46 @@ func (p *p2pComm) Stop() {
 	}
 }
 
+// newDelegator creates the delegator service if the key is given, or returns nil.
+func newDelegator(ctx *cli.Context, repo *chain.Repository, stater *state.Stater) (*delegator.Delegator, error) {
+	keyPath := ctx.String(delegatorKeyFlag.Name)
+	if keyPath == "" {
+		return nil, nil
+	}
+	key, err := crypto.LoadECDSA(keyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "load delegator key")
+	}
+	// never sponsor txs without restriction
+	path := ctx.String(delegatorPolicyFlag.Name)
+	if path == "" {
+		return nil, fmt.Errorf("delegator policy required, use -%v", delegatorPolicyFlag.Name)
+	}
+	policy, err := delegator.LoadPolicy(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load delegator policy")
+	}
+	d := delegator.New(repo, stater, key, policy)
+	log.Info("delegator service enabled", "address", d.Address())
+	return d, nil
+}
+
+func setBlocklistOptions(ctx *cli.Context, opt *txpool.Options, instanceDir string) error {
+	opt.BlocklistFilePath = ctx.String(blocklistFileFlag.Name)
+	if str := ctx.String(blocklistRegistryFlag.Name); str != "" {
+		addr, err := ablock.ParseAddress(str)
+		if err != nil {
+			return errors.Wrap(err, blocklistRegistryFlag.Name)
+		}
+		opt.BlocklistRegistry = &addr
+	}
+	if url := ctx.String(blocklistURLFlag.Name); url != "" {
+		opt.BlocklistFetchURL = url
+		opt.BlocklistCacheFilePath = filepath.Join(instanceDir, "blocklist.cache")
+	}
+	return nil
+}
+
 func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID ablock.Bytes32) (string, func(), error) {
 	addr := ctx.String(apiAddrFlag.Name)
 	listener, err := net.Listen("tcp", addr)
@@ -529,6 +627,63 @@ func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID ablock.Byt
 	}, nil
 }
 
+// startMetricsServer serves Prometheus metrics on a dedicated listener, and registers
+// metrics of components which are not aware of metric.
+func startMetricsServer(ctx *cli.Context, mainDB *muxdb.MuxDB, repo *chain.Repository, bft blocks.BFTEngine) (func(), error) {
+	addr := ctx.String(metricsAddrFlag.Name)
+	if addr == "" {
+		return func() {}, nil
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listen metrics addr [%v]", addr)
+	}
+
+	hitRatio := func(hit, miss int64) float64 {
+		if hit+miss == 0 {
+			return 0
+		}
+		return float64(hit) / float64(hit+miss)
+	}
+	unregisters := []func(){
+		metric.NewGaugeFunc("chain_best_block_number", "number of the best block", func() float64 {
+			return float64(repo.BestBlockSummary().Header.Number())
+		}),
+		metric.NewGaugeFunc("bft_finality_lag", "number of blocks between best block and finalized block", func() float64 {
+			best, finalized := repo.BestBlockSummary().Header.Number(), block.Number(bft.Finalized())
+			// the best block may be behind the finalized one while rewinding or syncing
+			if best < finalized {
+				return 0
+			}
+			return float64(best - finalized)
+		}),
+		metric.NewGaugeFunc("muxdb_trie_node_cache_hit_ratio", "hit ratio of trie node cache", func() float64 {
+			hit, miss, _, _ := mainDB.TrieCacheStats()
+			return hitRatio(hit, miss)
+		}),
+		metric.NewGaugeFunc("muxdb_trie_root_cache_hit_ratio", "hit ratio of trie root cache", func() float64 {
+			_, _, hit, miss := mainDB.TrieCacheStats()
+			return hitRatio(hit, miss)
+		}),
+	}
+
+	router := http.NewServeMux()
+	router.Handle("/metrics", metric.Handler())
+	srv := &http.Server{Handler: router}
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	log.Info("metrics service started", "url", "http://"+listener.Addr().String()+"/metrics")
+	return func() {
+		srv.Close()
+		goes.Wait()
+		for _, unregister := range unregisters {
+			unregister()
+		}
+	}, nil
+}
+
 func printStartupMessage1(
 	gene *genesis.Genesis,
 	repo *chain.Repository,
@@ -542,6 +697,7 @@ func printStartupMessage1(
     Network      [ %v %v ]
     Best block   [ %v #%v @%v ]
     Forks        [ %v ]
+    Config       [ %v ]
     Master       [ %v ]
     Beneficiary  [ %v ]
     Instance dir [ %v ]
@@ -550,6 +706,7 @@ func printStartupMessage1(
 		gene.ID(), gene.Name(),
 		bestBlock.Header.ID(), bestBlock.Header.Number(), time.Unix(int64(bestBlock.Header.Timestamp()), 0),
 		forkConfig,
+		gene.Config(),
 		master.Address(),
 		func() string {
 			if master.Beneficiary == nil {
@@ -625,3 +782,47 @@ func parseBootNode(ctx *cli.Context) []*discover.Node {
 	}
 	return nodes
 }
+
+// instance holds databases and the chain repository of the node instance, for offline commands.
+type instance struct {
+	dir        string
+	forkConfig ablock.ForkConfig
+	config     ablock.Config
+	mainDB     *muxdb.MuxDB
+	logDB      *logdb.LogDB
+	repo       *chain.Repository
+}
+
+// openInstance opens databases and the chain repository of the node instance selected by flags.
+func openInstance(ctx *cli.Context) (*instance, error) {
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return nil, err
+	}
+	mainDB, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return nil, err
+	}
+	logDB, err := openLogDB(ctx, instanceDir)
+	if err != nil {
+		mainDB.Close()
+		return nil, err
+	}
+	repo, err := initChainRepository(gene, mainDB, logDB)
+	if err != nil {
+		logDB.Close()
+		mainDB.Close()
+		return nil, err
+	}
+	return &instance{instanceDir, forkConfig, gene.Config(), mainDB, logDB, repo}, nil
+}
+
+// Close closes databases.
+func (i *instance) Close() {
+	i.logDB.Close()
+	i.mainDB.Close()
+}
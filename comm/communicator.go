@@ -20,7 +20,9 @@ import (
 	"github.com/ashishaw/authorityblock/chain"
 	"github.com/ashishaw/authorityblock/co"
 	"github.com/ashishaw/authorityblock/comm/proto"
+	"github.com/ashishaw/authorityblock/muxdb"
 	"github.com/ashishaw/authorityblock/p2psrv/discv5"
+	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
 	"github.com/ashishaw/authorityblock/txpool"
@@ -32,6 +34,9 @@ var log = log15.New("pkg", "comm")
 type Communicator struct {
 	repo           *chain.Repository
 	txPool         *txpool.TxPool
+	mainDB         *muxdb.MuxDB
+	stater         *state.Stater
+	config         ablock.Config
 	ctx            context.Context
 	cancel         context.CancelFunc
 	peerSet        *PeerSet
@@ -44,11 +49,14 @@ type Communicator struct {
 }
 
 // New create a new Communicator instance.
-func New(repo *chain.Repository, txPool *txpool.TxPool) *Communicator {
+func New(repo *chain.Repository, txPool *txpool.TxPool, mainDB *muxdb.MuxDB, config ablock.Config) *Communicator {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Communicator{
 		repo:           repo,
 		txPool:         txPool,
+		mainDB:         mainDB,
+		stater:         state.NewStater(mainDB),
+		config:         config,
 		ctx:            ctx,
 		cancel:         cancel,
 		peerSet:        newPeerSet(),
@@ -75,7 +83,7 @@ func (c *Communicator) Sync(ctx context.Context, handler HandleBlockStream) {
 	shouldSynced := func() bool {
 		bestBlockTime := c.repo.BestBlockSummary().Header.Timestamp()
 		now := uint64(time.Now().Unix())
-		if bestBlockTime+ablock.BlockInterval >= now {
+		if bestBlockTime+c.config.BlockInterval >= now {
 			return true
 		}
 		if syncCount > 2 {
@@ -125,15 +133,21 @@ func (c *Communicator) Sync(ctx context.Context, handler HandleBlockStream) {
 	}
 }
 
-// Protocols returns all supported protocols.
+// Protocols returns all supported protocols, the latest version first.
 func (c *Communicator) Protocols() []*p2p.Protocol {
-	return []*p2p.Protocol{
-		{
+	protocols := make([]*p2p.Protocol, 0, len(proto.Versions))
+	for _, version := range proto.Versions {
+		version := version
+		protocols = append(protocols, &p2p.Protocol{
 			Name:    proto.Name,
-			Version: proto.Version,
-			Length:  proto.Length,
-			Run:     c.servePeer,
-		}}
+			Version: version,
+			Length:  proto.Lengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return c.servePeer(p, rw, version)
+			},
+		})
+	}
+	return protocols
 }
 
 // DiscTopic returns the topic for p2p network discovery.
@@ -160,8 +174,8 @@ type txsToSync struct {
 	synced bool
 }
 
-func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := newPeer(p, rw)
+func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter, version uint) error {
+	peer := newPeer(p, rw, version, c.config.BlockInterval)
 	c.goes.Go(func() {
 		c.runPeer(peer)
 	})
@@ -196,17 +210,19 @@ func (c *Communicator) runPeer(peer *Peer) {
 	if localClock < remoteClock {
 		diff = remoteClock - localClock
 	}
-	if diff > ablock.BlockInterval*2 {
+	if diff > c.config.BlockInterval*2 {
 		peer.logger.Debug("failed to handshake", "err", "sys time diff too large")
 		return
 	}
 
 	peer.UpdateHead(status.BestBlockID, status.TotalScore)
 	c.peerSet.Add(peer)
+	metricPeerCount.Set(float64(c.peerSet.Len()))
 	peer.logger.Debug(fmt.Sprintf("peer added (%v)", c.peerSet.Len()))
 
 	defer func() {
 		c.peerSet.Remove(peer.ID())
+		metricPeerCount.Set(float64(c.peerSet.Len()))
 		peer.logger.Debug(fmt.Sprintf("peer removed (%v)", c.peerSet.Len()))
 	}()
 
@@ -12,9 +12,11 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
 	"github.com/ashishaw/authorityblock/block"
 	"github.com/ashishaw/authorityblock/comm/proto"
 	"github.com/ashishaw/authorityblock/metric"
+	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
 )
@@ -167,6 +169,103 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 			}
 			write(toSend)
 		}
+	case proto.MsgGetAccountRange, proto.MsgGetStorageRange:
+		var req proto.RangeRequest
+		if err := msg.Decode(&req); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+
+		const maxEntries = 4096
+		const maxSize = 512 * 1024
+		limit := int(req.Limit)
+		if limit > maxEntries {
+			limit = maxEntries
+		}
+		var result *state.Range
+		if summary, err := c.repo.GetBlockSummary(req.BlockID); err != nil {
+			if !c.repo.IsNotFound(err) {
+				log.Error("failed to get block summary", "err", err)
+			}
+		} else {
+			root, num, conflicts := summary.Header.StateRoot(), summary.Header.Number(), summary.Conflicts
+			if msg.Code == proto.MsgGetAccountRange {
+				result, err = c.stater.AccountRange(root, num, conflicts, req.Start, limit, maxSize)
+			} else {
+				result, err = c.stater.StorageRange(root, num, conflicts, req.AccountKey, req.Start, limit, maxSize)
+			}
+			if err != nil {
+				// the state may be pruned
+				log.Debug("failed to get state range", "err", err)
+			}
+		}
+		if result == nil {
+			result = &state.Range{}
+		}
+		write(result)
+	case proto.MsgGetCodes:
+		var hashes []ablock.Bytes32
+		if err := msg.Decode(&hashes); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+
+		const maxSize = 2 * 1024 * 1024
+		var (
+			result [][]byte
+			size   metric.StorageSize
+		)
+		for _, hash := range hashes {
+			if size >= maxSize {
+				break
+			}
+			code, err := c.stater.GetCode(hash)
+			if err != nil {
+				if !c.mainDB.IsNotFound(err) {
+					log.Error("failed to get code", "err", err)
+				}
+				break
+			}
+			result = append(result, code)
+			size += metric.StorageSize(len(code))
+		}
+		write(result)
+	case proto.MsgGetBlockReceipts:
+		var ids []ablock.Bytes32
+		if err := msg.Decode(&ids); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+
+		const maxBlocks = 256
+		var result []tx.Receipts
+		for _, id := range ids {
+			if len(result) >= maxBlocks {
+				break
+			}
+			receipts, err := c.repo.GetBlockReceipts(id)
+			if err != nil {
+				if !c.repo.IsNotFound(err) {
+					log.Error("failed to get block receipts", "err", err)
+				}
+				break
+			}
+			result = append(result, receipts)
+		}
+		write(result)
+	case proto.MsgGetBFTQualities:
+		var headID ablock.Bytes32
+		if err := msg.Decode(&headID); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+
+		var result []*bft.Quality
+		if _, err := c.repo.GetBlockSummary(headID); err != nil {
+			if !c.repo.IsNotFound(err) {
+				log.Error("failed to get block summary", "err", err)
+			}
+		} else if result, err = bft.ExportQualities(c.repo, c.mainDB, headID, c.config.CheckpointInterval); err != nil {
+			log.Error("failed to export bft qualities", "err", err)
+			result = nil
+		}
+		write(result)
 	default:
 		return fmt.Errorf("unknown message (%v)", msg.Code)
 	}
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ashishaw/authorityblock/comm/proto"
+	"github.com/ashishaw/authorityblock/metric"
+)
+
+var (
+	metricPeerCount = metric.NewGauge("p2p_peers", "number of connected peers")
+	metricMsgCount  = metric.NewCounterVec("p2p_messages_total", "number of p2p messages", "msg", "dir")
+	metricMsgBytes  = metric.NewCounterVec("p2p_message_bytes_total", "size of p2p messages in bytes", "msg", "dir")
+)
+
+// meteredMsgReadWriter counts traffic of each kind of message.
+type meteredMsgReadWriter struct {
+	p2p.MsgReadWriter
+}
+
+func (rw meteredMsgReadWriter) ReadMsg() (p2p.Msg, error) {
+	msg, err := rw.MsgReadWriter.ReadMsg()
+	if err == nil {
+		name := proto.MsgName(msg.Code)
+		metricMsgCount.WithLabelValues(name, "in").Inc()
+		metricMsgBytes.WithLabelValues(name, "in").Add(float64(msg.Size))
+	}
+	return msg, err
+}
+
+func (rw meteredMsgReadWriter) WriteMsg(msg p2p.Msg) error {
+	// the size is read before writing, since payload is consumed after written
+	name, size := proto.MsgName(msg.Code), msg.Size
+	err := rw.MsgReadWriter.WriteMsg(msg)
+	if err == nil {
+		metricMsgCount.WithLabelValues(name, "out").Inc()
+		metricMsgBytes.WithLabelValues(name, "out").Add(float64(size))
+	}
+	return err
+}
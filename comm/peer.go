@@ -34,17 +34,19 @@ type Peer struct {
 	*rpc.RPC
 	logger log15.Logger
 
-	createdTime mclock.AbsTime
-	knownTxs    *lru.Cache
-	knownBlocks *lru.Cache
-	head        struct {
+	createdTime   mclock.AbsTime
+	version       uint
+	blockInterval uint64
+	knownTxs      *lru.Cache
+	knownBlocks   *lru.Cache
+	head          struct {
 		sync.Mutex
 		id         ablock.Bytes32
 		totalScore uint64
 	}
 }
 
-func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter, version uint, blockInterval uint64) *Peer {
 	dir := "outbound"
 	if peer.Inbound() {
 		dir = "inbound"
@@ -56,15 +58,22 @@ func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 	knownTxs, _ := lru.New(maxKnownTxs)
 	knownBlocks, _ := lru.New(maxKnownBlocks)
 	return &Peer{
-		Peer:        peer,
-		RPC:         rpc.New(peer, rw),
-		logger:      log.New(ctx...),
-		createdTime: mclock.Now(),
-		knownTxs:    knownTxs,
-		knownBlocks: knownBlocks,
+		Peer:          peer,
+		RPC:           rpc.New(peer, meteredMsgReadWriter{rw}),
+		logger:        log.New(ctx...),
+		createdTime:   mclock.Now(),
+		version:       version,
+		blockInterval: blockInterval,
+		knownTxs:      knownTxs,
+		knownBlocks:   knownBlocks,
 	}
 }
 
+// Version returns the negotiated protocol version.
+func (p *Peer) Version() uint {
+	return p.version
+}
+
 // Head returns head block ID and total score.
 func (p *Peer) Head() (id ablock.Bytes32, totalScore uint64) {
 	p.head.Lock()
@@ -84,7 +93,7 @@ func (p *Peer) UpdateHead(id ablock.Bytes32, totalScore uint64) {
 // MarkTransaction marks a transaction to known.
 func (p *Peer) MarkTransaction(hash ablock.Bytes32) {
 	// that's 10~100 block intervals
-	expiration := mclock.AbsTime(time.Second * time.Duration(ablock.BlockInterval*uint64(rand.Intn(91)+10)))
+	expiration := mclock.AbsTime(time.Second * time.Duration(p.blockInterval*uint64(rand.Intn(91)+10)))
 
 	deadline := mclock.Now() + expiration
 	p.knownTxs.Add(hash, deadline)
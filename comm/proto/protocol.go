@@ -12,11 +12,17 @@ import (
 // Constants
 const (
 	Name              = "ablock"
-	Version    uint   = 1
-	Length     uint64 = 8
+	Version    uint   = 2 // the latest version, which adds messages for state sync
+	Length     uint64 = 13
 	MaxMsgSize        = 10 * 1024 * 1024
 )
 
+// Versions lists supported protocol versions, the latest first.
+var Versions = []uint{Version, 1}
+
+// Lengths maps protocol versions to their count of message codes.
+var Lengths = map[uint]uint64{Version: Length, 1: 8}
+
 // Protocol messages of ablock
 const (
 	MsgGetStatus = iota
@@ -27,6 +33,11 @@ const (
 	MsgGetBlockIDByNumber
 	MsgGetBlocksFromNumber // fetch blocks from given number (including given number)
 	MsgGetTxs
+	MsgGetAccountRange  // fetch a range of accounts with proof
+	MsgGetStorageRange  // fetch a range of storage entries of an account with proof
+	MsgGetCodes         // fetch codes by code hashes
+	MsgGetBlockReceipts // fetch receipts of blocks
+	MsgGetBFTQualities  // fetch bft qualities saved on the chain of given block
 )
 
 // MsgName convert msg code to string.
@@ -48,6 +59,16 @@ func MsgName(msgCode uint64) string {
 		return "MsgGetBlocksFromNumber"
 	case MsgGetTxs:
 		return "MsgGetTxs"
+	case MsgGetAccountRange:
+		return "MsgGetAccountRange"
+	case MsgGetStorageRange:
+		return "MsgGetStorageRange"
+	case MsgGetCodes:
+		return "MsgGetCodes"
+	case MsgGetBlockReceipts:
+		return "MsgGetBlockReceipts"
+	case MsgGetBFTQualities:
+		return "MsgGetBFTQualities"
 	default:
 		return fmt.Sprintf("unknown msg code(%v)", msgCode)
 	}
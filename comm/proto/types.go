@@ -9,7 +9,9 @@ import (
 	"context"
 
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ashishaw/authorityblock/bft"
 	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
 )
@@ -23,6 +25,14 @@ type (
 		BestBlockID    ablock.Bytes32
 		TotalScore     uint64
 	}
+
+	// RangeRequest is the argument of MsgGetAccountRange and MsgGetStorageRange.
+	RangeRequest struct {
+		BlockID    ablock.Bytes32 // the block whose state is requested
+		AccountKey []byte         // hashed key of the account, for storage range only
+		Start      []byte
+		Limit      uint32
+	}
 )
 
 // RPC defines RPC interface.
@@ -94,3 +104,52 @@ func GetTxs(ctx context.Context, rpc RPC) (tx.Transactions, error) {
 	}
 	return txs, nil
 }
+
+// GetAccountRange get a range of accounts with proof from remote peer.
+// The range has no proof if the remote peer doesn't have the state.
+func GetAccountRange(ctx context.Context, rpc RPC, blockID ablock.Bytes32, start []byte, limit uint32) (*state.Range, error) {
+	var r state.Range
+	if err := rpc.Call(ctx, MsgGetAccountRange, &RangeRequest{BlockID: blockID, Start: start, Limit: limit}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetStorageRange get a range of storage entries of the account with proof from remote peer.
+// The range has no proof if the remote peer doesn't have the state.
+func GetStorageRange(ctx context.Context, rpc RPC, blockID ablock.Bytes32, accountKey []byte, start []byte, limit uint32) (*state.Range, error) {
+	var r state.Range
+	if err := rpc.Call(ctx, MsgGetStorageRange, &RangeRequest{blockID, accountKey, start, limit}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetCodes get codes by code hashes from remote peer.
+// The result may be shorter than the given hashes.
+func GetCodes(ctx context.Context, rpc RPC, hashes []ablock.Bytes32) ([][]byte, error) {
+	var codes [][]byte
+	if err := rpc.Call(ctx, MsgGetCodes, hashes, &codes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// GetBlockReceipts get receipts of blocks from remote peer.
+// The result may be shorter than the given block IDs.
+func GetBlockReceipts(ctx context.Context, rpc RPC, ids []ablock.Bytes32) ([]tx.Receipts, error) {
+	var receipts []tx.Receipts
+	if err := rpc.Call(ctx, MsgGetBlockReceipts, ids, &receipts); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// GetBFTQualities get bft qualities saved on the chain of given block from remote peer.
+func GetBFTQualities(ctx context.Context, rpc RPC, headID ablock.Bytes32) ([]*bft.Quality, error) {
+	var qualities []*bft.Quality
+	if err := rpc.Call(ctx, MsgGetBFTQualities, headID, &qualities); err != nil {
+		return nil, err
+	}
+	return qualities, nil
+}
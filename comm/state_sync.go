@@ -0,0 +1,597 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/bft"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/co"
+	"github.com/ashishaw/authorityblock/comm/proto"
+	"github.com/ashishaw/authorityblock/proof"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+const (
+	stateSyncChunks       = 16   // the hashed key space of accounts is split into chunks to be downloaded in parallel
+	stateSyncRangeLimit   = 1024 // max entries per range request
+	stateSyncBatchSize    = 256  // max items per receipts or codes request
+	stateSyncMaxAttempts  = 16   // max attempts of a request before giving up
+	stateSyncQualityPeers = 5    // max peers asked for bft qualities
+)
+
+// badResponseError indicates the peer responded invalid data, or doesn't have the requested data.
+type badResponseError struct {
+	error
+}
+
+type stateItem struct {
+	isStorage bool
+	*state.RangeEntry
+}
+
+type stateSyncer struct {
+	comm *Communicator
+
+	lock      sync.Mutex
+	badPeers  map[discover.NodeID]bool
+	seenCodes map[ablock.Bytes32]bool
+}
+
+// SyncState bootstraps the fresh repository from peers, instead of executing all history blocks.
+// The state is downloaded at the end of the latest bft round no later than the trusted pivot block,
+// and verified against its state root. At least recentBlocks blocks before it are downloaded fully,
+// and ids of earlier blocks are left unindexed.
+// Normal synchronization continues from the returned block afterwards.
+func (c *Communicator) SyncState(ctx context.Context, pivotID ablock.Bytes32, recentBlocks uint32) (*chain.BlockSummary, error) {
+	if c.repo.BestBlockSummary().Header.Number() != 0 {
+		return nil, errors.New("repository not empty")
+	}
+	s := &stateSyncer{
+		comm:      c,
+		badPeers:  make(map[discover.NodeID]bool),
+		seenCodes: make(map[ablock.Bytes32]bool),
+	}
+	return s.run(ctx, pivotID, recentBlocks)
+}
+
+func (s *stateSyncer) run(ctx context.Context, pivotID ablock.Bytes32, recentBlocks uint32) (*chain.BlockSummary, error) {
+	repo := s.comm.repo
+
+	num := block.Number(pivotID)
+	for !bft.IsStorePoint(num, s.comm.config.CheckpointInterval) {
+		if num == 0 {
+			return nil, errors.New("no bft round ended before the pivot block")
+		}
+		num--
+	}
+	baseNum := uint32(1)
+	if num+1 > recentBlocks+baseNum {
+		baseNum = num + 1 - recentBlocks
+	}
+	// the poa seeder needs the block of the previous epoch
+	if epoch := (num + 1) / ablock.SeederInterval; epoch > 1 {
+		if seedNum := (epoch - 1) * ablock.SeederInterval; seedNum < baseNum {
+			baseNum = seedNum
+		}
+	}
+
+	log.Info("downloading blocks", "from", baseNum, "to", block.Number(pivotID))
+	blocks, err := s.fetchBlocks(ctx, baseNum, pivotID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetch blocks")
+	}
+	// blocks after the state block are dropped, they will be synced and executed normally
+	blocks = blocks[:num-baseNum+1]
+	header := blocks[len(blocks)-1].Header()
+
+	receipts, err := s.fetchReceipts(ctx, blocks)
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetch receipts")
+	}
+	qualities, err := s.fetchQualities(ctx, blocks)
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetch bft qualities")
+	}
+
+	conflicts, err := repo.ScanConflicts(num)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("downloading state", "block", num, "root", header.StateRoot())
+	restorer := state.NewStater(s.comm.mainDB).NewRestorer(num, conflicts)
+	if err := s.syncState(ctx, header, restorer); err != nil {
+		return nil, errors.WithMessage(err, "sync state")
+	}
+	root, err := restorer.Finish()
+	if err != nil {
+		return nil, errors.WithMessage(err, "restore state")
+	}
+	if root != header.StateRoot() {
+		return nil, errors.Errorf("state root mismatch, want %v, got %v", header.StateRoot(), root)
+	}
+
+	// import blocks
+	baseConflicts, err := repo.ScanConflicts(baseNum)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.ImportBaseBlock(blocks[0], receipts[0], baseConflicts, func(uint32) (ablock.Bytes32, error) {
+		// ancestors are unknown
+		return ablock.Bytes32{}, ctx.Err()
+	}); err != nil {
+		return nil, errors.WithMessage(err, "import base block")
+	}
+	for i := 1; i < len(blocks); i++ {
+		conflicts, err := repo.ScanConflicts(blocks[i].Header().Number())
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.AddBlock(blocks[i], receipts[i], conflicts); err != nil {
+			return nil, errors.WithMessage(err, "add block")
+		}
+	}
+	summary, err := repo.GetBlockSummary(header.ID())
+	if err != nil {
+		return nil, err
+	}
+	if summary.Conflicts != conflicts {
+		return nil, errors.New("block conflicts mismatch")
+	}
+	if err := bft.ImportQualities(s.comm.mainDB, qualities); err != nil {
+		return nil, errors.WithMessage(err, "import bft qualities")
+	}
+	if err := repo.SetBestBlockID(header.ID()); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// call calls fn with a random peer, and retries with other peers on failure.
+// Peers responded bad data are not picked again.
+func (s *stateSyncer) call(ctx context.Context, fn func(peer *Peer) error) error {
+	var err error
+	for i := 0; i < stateSyncMaxAttempts; i++ {
+		peer := s.pickPeer()
+		if peer == nil {
+			err = errors.New("no suitable peer")
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second * 2):
+			}
+			continue
+		}
+		if err = fn(peer); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		peer.logger.Debug("state sync request failed", "err", err)
+		if _, ok := err.(badResponseError); ok {
+			s.lock.Lock()
+			s.badPeers[peer.ID()] = true
+			s.lock.Unlock()
+		}
+	}
+	return err
+}
+
+func (s *stateSyncer) pickPeer() *Peer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	peers := s.peers()
+	if len(peers) == 0 {
+		return nil
+	}
+	return peers[rand.Intn(len(peers))]
+}
+
+// peers returns peers able to serve state sync, which negotiated the latest protocol version
+// and never responded bad data. The lock must be held.
+func (s *stateSyncer) peers() Peers {
+	return s.comm.peerSet.Slice().Filter(func(p *Peer) bool {
+		return p.Version() >= proto.Version && !s.badPeers[p.ID()]
+	})
+}
+
+// fetchBlocks fetches blocks from the given number to the pivot block, which are verified by parent links.
+func (s *stateSyncer) fetchBlocks(ctx context.Context, fromNum uint32, pivotID ablock.Bytes32) (blocks []*block.Block, err error) {
+	pivotNum := block.Number(pivotID)
+	err = s.call(ctx, func(peer *Peer) error {
+		blocks = make([]*block.Block, 0, pivotNum-fromNum+1)
+		for num := fromNum; num <= pivotNum; {
+			result, err := proto.GetBlocksFromNumber(ctx, peer, num)
+			if err != nil {
+				return err
+			}
+			if len(result) == 0 {
+				return badResponseError{errors.New("blocks unavailable")}
+			}
+			for _, raw := range result {
+				var blk block.Block
+				if err := rlp.DecodeBytes(raw, &blk); err != nil {
+					return badResponseError{errors.Wrap(err, "invalid block")}
+				}
+				if blk.Header().Number() != num {
+					return badResponseError{errors.New("broken sequence")}
+				}
+				if blk.Header().TxsRoot() != blk.Transactions().RootHash() {
+					return badResponseError{errors.New("txs root mismatch")}
+				}
+				blocks = append(blocks, &blk)
+				if num++; num > pivotNum {
+					break
+				}
+			}
+		}
+
+		// verify backward from the trusted pivot
+		expectedID := pivotID
+		for i := len(blocks) - 1; i >= 0; i-- {
+			if blocks[i].Header().ID() != expectedID {
+				return badResponseError{errors.New("block not on the chain of pivot")}
+			}
+			expectedID = blocks[i].Header().ParentID()
+		}
+		return nil
+	})
+	return
+}
+
+func (s *stateSyncer) fetchReceipts(ctx context.Context, blocks []*block.Block) ([]tx.Receipts, error) {
+	receipts := make([]tx.Receipts, 0, len(blocks))
+	for len(receipts) < len(blocks) {
+		var (
+			pending = blocks[len(receipts):]
+			ids     = make([]ablock.Bytes32, 0, stateSyncBatchSize)
+		)
+		for i := 0; i < len(pending) && i < stateSyncBatchSize; i++ {
+			ids = append(ids, pending[i].Header().ID())
+		}
+
+		if err := s.call(ctx, func(peer *Peer) error {
+			result, err := proto.GetBlockReceipts(ctx, peer, ids)
+			if err != nil {
+				return err
+			}
+			if len(result) == 0 || len(result) > len(ids) {
+				return badResponseError{errors.New("receipts unavailable")}
+			}
+			for i, r := range result {
+				if pending[i].Header().ReceiptsRoot() != r.RootHash() {
+					return badResponseError{errors.New("receipts root mismatch")}
+				}
+			}
+			receipts = append(receipts, result...)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return receipts, nil
+}
+
+// fetchQualities fetches bft qualities of rounds within the downloaded blocks. Qualities can't be verified,
+// so they are accepted only if agreed by a quorum of peers, which is at least two and more than half of
+// the responded peers.
+func (s *stateSyncer) fetchQualities(ctx context.Context, blocks []*block.Block) ([]*bft.Quality, error) {
+	ids := make(map[ablock.Bytes32]bool, len(blocks))
+	for _, b := range blocks {
+		ids[b.Header().ID()] = true
+	}
+	headID := blocks[len(blocks)-1].Header().ID()
+
+	s.lock.Lock()
+	peers := s.peers()
+	s.lock.Unlock()
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > stateSyncQualityPeers {
+		peers = peers[:stateSyncQualityPeers]
+	}
+
+	var (
+		responded int
+		votes     = make(map[ablock.Bytes32]int)
+		results   = make(map[ablock.Bytes32][]*bft.Quality)
+	)
+	for _, peer := range peers {
+		result, err := proto.GetBFTQualities(ctx, peer, headID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			peer.logger.Debug("failed to get bft qualities", "err", err)
+			continue
+		}
+		var filtered []*bft.Quality
+		for _, q := range result {
+			if ids[q.BlockID] {
+				filtered = append(filtered, q)
+			}
+		}
+		data, err := rlp.EncodeToBytes(filtered)
+		if err != nil {
+			return nil, err
+		}
+		hash := ablock.Blake2b(data)
+		responded++
+		votes[hash]++
+		results[hash] = filtered
+	}
+
+	for hash, n := range votes {
+		if n >= 2 && n*2 > responded {
+			return results[hash], nil
+		}
+	}
+	return nil, errors.Errorf("no quorum among %v peers", responded)
+}
+
+// syncState downloads chunks of state in parallel, and restores them in order of hashed key.
+func (s *stateSyncer) syncState(ctx context.Context, header *block.Header, restorer *state.Restorer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		goes     co.Goes
+		chunks   = make([]chan *stateItem, stateSyncChunks)
+		errLock  sync.Mutex
+		firstErr error
+	)
+	setErr := func(err error) {
+		errLock.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		errLock.Unlock()
+		cancel()
+	}
+
+	for i := range chunks {
+		var (
+			ch    = make(chan *stateItem, stateSyncRangeLimit)
+			start = make([]byte, 32)
+			end   []byte
+		)
+		start[0] = byte(i * 256 / stateSyncChunks)
+		if i+1 < stateSyncChunks {
+			end = make([]byte, 32)
+			end[0] = byte((i + 1) * 256 / stateSyncChunks)
+		}
+		chunks[i] = ch
+		goes.Go(func() {
+			defer close(ch)
+			if err := s.syncChunk(ctx, header, start, end, ch); err != nil {
+				setErr(err)
+			}
+		})
+	}
+
+	var (
+		accounts, entries int
+		lastLog           = time.Now()
+	)
+restore:
+	for _, ch := range chunks {
+		for item := range ch {
+			var err error
+			if item.isStorage {
+				err = restorer.AddStorage(item.Key, item.Value, item.Extra)
+				entries++
+			} else {
+				err = restorer.AddAccount(item.Key, item.Value, item.Extra)
+				accounts++
+			}
+			if err != nil {
+				setErr(errors.WithMessage(err, "restore state"))
+				break restore
+			}
+			if time.Since(lastLog) > time.Second*8 {
+				lastLog = time.Now()
+				log.Info("downloading state", "accounts", accounts, "storage", entries, "progress", fmtProgress(item.Key))
+			}
+		}
+	}
+	goes.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// syncChunk downloads accounts with keys in [start, end), along with their codes and storage entries.
+func (s *stateSyncer) syncChunk(ctx context.Context, header *block.Header, start, end []byte, out chan<- *stateItem) error {
+	send := func(item *stateItem) error {
+		select {
+		case out <- item:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for start != nil {
+		var (
+			r    *state.Range
+			more bool
+		)
+		if err := s.call(ctx, func(peer *Peer) error {
+			result, err := proto.GetAccountRange(ctx, peer, header.ID(), start, stateSyncRangeLimit)
+			if err != nil {
+				return err
+			}
+			if len(result.Proof) == 0 {
+				return badResponseError{errors.New("state unavailable")}
+			}
+			if more, err = proof.VerifyAccountRange(header, start, result); err != nil {
+				return badResponseError{err}
+			}
+			r = result
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		var (
+			accs  = make([]*state.Account, 0, len(r.Entries))
+			codes = make(map[ablock.Bytes32][]byte)
+		)
+		for _, e := range r.Entries {
+			if end != nil && bytes.Compare(e.Key, end) >= 0 {
+				break
+			}
+			var acc state.Account
+			if err := rlp.DecodeBytes(e.Value, &acc); err != nil {
+				return errors.Wrap(err, "decode account")
+			}
+			accs = append(accs, &acc)
+			if len(acc.CodeHash) > 0 {
+				hash := ablock.BytesToBytes32(acc.CodeHash)
+				s.lock.Lock()
+				if !s.seenCodes[hash] {
+					// the first account with the code brings it to the restorer
+					s.seenCodes[hash] = true
+					codes[hash] = nil
+				}
+				s.lock.Unlock()
+			}
+		}
+		if err := s.fetchCodes(ctx, codes); err != nil {
+			return err
+		}
+
+		for i, acc := range accs {
+			e := r.Entries[i]
+			var code []byte
+			if len(acc.CodeHash) > 0 {
+				hash := ablock.BytesToBytes32(acc.CodeHash)
+				code = codes[hash]
+				delete(codes, hash)
+			}
+			if err := send(&stateItem{false, &state.RangeEntry{Key: e.Key, Value: e.Value, Extra: code}}); err != nil {
+				return err
+			}
+			if len(acc.StorageRoot) > 0 {
+				if err := s.syncStorage(ctx, header.ID(), e.Key, acc, send); err != nil {
+					return err
+				}
+			}
+		}
+		if len(accs) < len(r.Entries) || !more {
+			// reached the end of chunk, the range proof ensures no account omitted before it
+			return nil
+		}
+		start = nextKey(r.Entries[len(r.Entries)-1].Key)
+	}
+	return nil
+}
+
+func (s *stateSyncer) syncStorage(ctx context.Context, blockID ablock.Bytes32, accountKey []byte, acc *state.Account, send func(*stateItem) error) error {
+	start := make([]byte, 32)
+	for start != nil {
+		var (
+			r    *state.Range
+			more bool
+		)
+		if err := s.call(ctx, func(peer *Peer) error {
+			result, err := proto.GetStorageRange(ctx, peer, blockID, accountKey, start, stateSyncRangeLimit)
+			if err != nil {
+				return err
+			}
+			if len(result.Proof) == 0 {
+				return badResponseError{errors.New("state unavailable")}
+			}
+			if more, err = proof.VerifyStorageRange(acc, start, result); err != nil {
+				return badResponseError{err}
+			}
+			r = result
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, e := range r.Entries {
+			if err := send(&stateItem{true, e}); err != nil {
+				return err
+			}
+		}
+		if !more {
+			return nil
+		}
+		start = nextKey(r.Entries[len(r.Entries)-1].Key)
+	}
+	return nil
+}
+
+// fetchCodes fills codes of the given code hashes.
+func (s *stateSyncer) fetchCodes(ctx context.Context, codes map[ablock.Bytes32][]byte) error {
+	var hashes []ablock.Bytes32
+	for hash := range codes {
+		hashes = append(hashes, hash)
+	}
+
+	for len(hashes) > 0 {
+		batch := hashes
+		if len(batch) > stateSyncBatchSize {
+			batch = batch[:stateSyncBatchSize]
+		}
+		if err := s.call(ctx, func(peer *Peer) error {
+			result, err := proto.GetCodes(ctx, peer, batch)
+			if err != nil {
+				return err
+			}
+			if len(result) == 0 || len(result) > len(batch) {
+				return badResponseError{errors.New("codes unavailable")}
+			}
+			for i, code := range result {
+				if ablock.BytesToBytes32(crypto.Keccak256(code)) != batch[i] {
+					return badResponseError{errors.New("code hash mismatch")}
+				}
+				codes[batch[i]] = code
+			}
+			hashes = hashes[len(result):]
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextKey returns the smallest key greater than the given key of the same length.
+// Nil returned if the key is the largest.
+func nextKey(key []byte) []byte {
+	next := append([]byte(nil), key...)
+	for i := len(next) - 1; i >= 0; i-- {
+		if next[i]++; next[i] != 0 {
+			return next
+		}
+	}
+	return nil
+}
+
+// fmtProgress estimates the progress by the position of hashed key.
+func fmtProgress(key []byte) string {
+	if len(key) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f%%", float64(uint32(key[0])<<8|uint32(key[1]))*100/65536)
+}
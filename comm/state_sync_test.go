@@ -0,0 +1,221 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/comm"
+	"github.com/ashishaw/authorityblock/comm/proto"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/packer"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+	"github.com/ashishaw/authorityblock/txpool"
+)
+
+type testNode struct {
+	id     discover.NodeID
+	repo   *chain.Repository
+	stater *state.Stater
+	comm   *comm.Communicator
+}
+
+func newTestNode(t *testing.T, id byte) *testNode {
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	b, _, _, err := genesis.NewDevnet().Build(stater)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := chain.NewRepository(db, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := txpool.New(repo, stater, txpool.Options{
+		Limit:           10000,
+		LimitPerAccount: 16,
+		MaxLifetime:     10 * time.Minute,
+	})
+	t.Cleanup(pool.Close)
+
+	c := comm.New(repo, pool, db, ablock.DefaultConfig)
+	t.Cleanup(c.Stop)
+	return &testNode{discover.NodeID{id}, repo, stater, c}
+}
+
+func (n *testNode) packBlock(t *testing.T, txs ...*tx.Transaction) {
+	master := genesis.DevAccounts()[0]
+	best := n.repo.BestBlockSummary()
+	flow, err := packer.New(n.repo, n.stater, master.Address, &master.Address, ablock.NoFork, ablock.DefaultConfig).
+		Mock(best, best.Header.Timestamp()+ablock.DefaultConfig.BlockInterval, best.Header.GasLimit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range txs {
+		if err := flow.Adopt(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conflicts, err := n.repo.ScanConflicts(best.Header.Number() + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, stage, receipts, err := flow.Pack(master.PrivateKey, conflicts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.repo.AddBlock(b, receipts, conflicts); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.repo.SetBestBlockID(b.Header().ID()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// bufferedRW reads the whole payload of msg, as rlpx does.
+type bufferedRW struct {
+	p2p.MsgReadWriter
+}
+
+func (rw bufferedRW) ReadMsg() (p2p.Msg, error) {
+	msg, err := rw.MsgReadWriter.ReadMsg()
+	if err != nil {
+		return msg, err
+	}
+	data, err := ioutil.ReadAll(msg.Payload)
+	if err != nil {
+		return msg, err
+	}
+	msg.Payload = bytes.NewReader(data)
+	return msg, nil
+}
+
+// omittingRW drops a leaf from account ranges it responds.
+type omittingRW struct {
+	p2p.MsgReadWriter
+}
+
+func (rw omittingRW) WriteMsg(msg p2p.Msg) error {
+	if msg.Code == proto.MsgGetAccountRange {
+		var data struct {
+			ID       uint32
+			IsResult bool
+			Range    *state.Range
+		}
+		if err := msg.Decode(&data); err != nil {
+			return err
+		}
+		if data.IsResult && data.Range != nil && len(data.Range.Entries) > 2 {
+			data.Range.Entries = append(data.Range.Entries[:1], data.Range.Entries[2:]...)
+		}
+		size, r, err := rlp.EncodeToReader(&data)
+		if err != nil {
+			return err
+		}
+		msg.Size, msg.Payload = uint32(size), r
+	}
+	return rw.MsgReadWriter.WriteMsg(msg)
+}
+
+func connect(a, b *testNode) {
+	rwA, rwB := p2p.MsgPipe()
+	go a.comm.Protocols()[0].Run(p2p.NewPeer(b.id, "b", nil), bufferedRW{rwA})
+	go b.comm.Protocols()[0].Run(p2p.NewPeer(a.id, "a", nil), bufferedRW{rwB})
+}
+
+// connectOmitting connects a to b, which omits a leaf in account ranges it responds.
+func connectOmitting(a, b *testNode) {
+	rwA, rwB := p2p.MsgPipe()
+	go a.comm.Protocols()[0].Run(p2p.NewPeer(b.id, "b", nil), bufferedRW{rwA})
+	go b.comm.Protocols()[0].Run(p2p.NewPeer(a.id, "a", nil), omittingRW{bufferedRW{rwB}})
+}
+
+func TestSyncState(t *testing.T) {
+	var (
+		src       = newTestNode(t, 1)
+		empty     = newTestNode(t, 2)
+		dst       = newTestNode(t, 3)
+		omitting  = newTestNode(t, 4)
+		recipient = ablock.BytesToAddress([]byte("recipient"))
+	)
+
+	for i := 0; i < 190; i++ {
+		var txs []*tx.Transaction
+		if i == 175 {
+			trx := new(tx.Builder).
+				ChainTag(src.repo.ChainTag()).
+				Expiration(1000).
+				Gas(21000).
+				Clause(tx.NewClause(&recipient).WithValue(big.NewInt(1000))).
+				Build()
+			sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txs = append(txs, trx.WithSignature(sig))
+		}
+		src.packBlock(t, txs...)
+		omitting.packBlock(t, txs...)
+	}
+	assert.Equal(t, src.repo.BestBlockSummary().Header.ID(), omitting.repo.BestBlockSummary().Header.ID())
+
+	// the peer without state and the peer omitting accounts are skipped
+	connect(dst, empty)
+	connect(dst, src)
+	connectOmitting(dst, omitting)
+	for dst.comm.PeerCount() < 3 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	pivot := src.repo.BestBlockSummary().Header
+	summary, err := dst.comm.SyncState(ctx, pivot.ID(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the end of the latest bft round
+	assert.Equal(t, uint32(179), summary.Header.Number())
+	assert.Equal(t, summary.Header.ID(), dst.repo.BestBlockSummary().Header.ID())
+
+	srcChain := src.repo.NewBestChain()
+	expected, _ := srcChain.GetBlockID(179)
+	assert.Equal(t, expected, summary.Header.ID())
+	_, err = dst.repo.NewBestChain().GetBlockSummary(170)
+	assert.Nil(t, err)
+	_, err = dst.repo.NewBestChain().GetBlockID(100)
+	assert.True(t, dst.repo.IsNotFound(err), "ancestors not indexed")
+
+	st := dst.stater.NewState(summary.Header.StateRoot(), summary.Header.Number(), summary.Conflicts, summary.SteadyNum)
+	balance, err := st.GetBalance(recipient)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1000), balance)
+
+	// able to continue packing on the synced chain
+	dst.packBlock(t)
+	assert.Equal(t, uint32(180), dst.repo.BestBlockSummary().Header.Number())
+
+	// can't sync into a non-empty repository
+	_, err = dst.comm.SyncState(ctx, pivot.ID(), 10)
+	assert.NotNil(t, err)
+}
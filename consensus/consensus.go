@@ -28,18 +28,20 @@ type Consensus struct {
 	stater               *state.Stater
 	seeder               *poa.Seeder
 	forkConfig           ablock.ForkConfig
+	config               ablock.Config
 	correctReceiptsRoots map[string]string
 	candidatesCache      *simplelru.LRU
 }
 
 // New create a Consensus instance.
-func New(repo *chain.Repository, stater *state.Stater, forkConfig ablock.ForkConfig) *Consensus {
+func New(repo *chain.Repository, stater *state.Stater, forkConfig ablock.ForkConfig, config ablock.Config) *Consensus {
 	candidatesCache, _ := simplelru.NewLRU(16, nil)
 	return &Consensus{
 		repo:                 repo,
 		stater:               stater,
 		seeder:               poa.NewSeeder(repo),
 		forkConfig:           forkConfig,
+		config:               config,
 		correctReceiptsRoots: ablock.LoadCorrectReceiptsRoots(),
 		candidatesCache:      candidatesCache,
 	}
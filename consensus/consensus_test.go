@@ -88,9 +88,9 @@ func newTestConsensus() (*testConsensus, error) {
 	forkConfig.VIP214 = 2
 
 	proposer := genesis.DevAccounts()[0]
-	p := packer.New(repo, stater, proposer.Address, &proposer.Address, forkConfig)
+	p := packer.New(repo, stater, proposer.Address, &proposer.Address, forkConfig, ablock.DefaultConfig)
 	parentSum, _ := repo.GetBlockSummary(parent.Header().ID())
-	flow, err := p.Schedule(parentSum, uint64(parent.Header().Timestamp()+100*ablock.BlockInterval))
+	flow, err := p.Schedule(parentSum, uint64(parent.Header().Timestamp()+100*ablock.DefaultConfig.BlockInterval))
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +100,7 @@ func newTestConsensus() (*testConsensus, error) {
 		return nil, err
 	}
 
-	con := New(repo, stater, forkConfig)
+	con := New(repo, stater, forkConfig, ablock.DefaultConfig)
 
 	if _, _, err := con.Process(parentSum, b1, flow.When(), 0); err != nil {
 		return nil, err
@@ -119,9 +119,9 @@ func newTestConsensus() (*testConsensus, error) {
 	}
 
 	proposer2 := genesis.DevAccounts()[1]
-	p2 := packer.New(repo, stater, proposer2.Address, &proposer2.Address, forkConfig)
+	p2 := packer.New(repo, stater, proposer2.Address, &proposer2.Address, forkConfig, ablock.DefaultConfig)
 	b1sum, _ := repo.GetBlockSummary(b1.Header().ID())
-	flow2, err := p2.Schedule(b1sum, uint64(b1.Header().Timestamp()+100*ablock.BlockInterval))
+	flow2, err := p2.Schedule(b1sum, uint64(b1.Header().Timestamp()+100*ablock.DefaultConfig.BlockInterval))
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +281,7 @@ func TestValidateBlockHeader(t *testing.T) {
 		{
 			"ErrFutureBlock", func(t *testing.T) {
 				builder := tc.builder(tc.original.Header())
-				blk, err := tc.sign(builder.Timestamp(tc.time + ablock.BlockInterval*2))
+				blk, err := tc.sign(builder.Timestamp(tc.time + ablock.DefaultConfig.BlockInterval*2))
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -96,11 +96,11 @@ func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Head
 		return consensusError(fmt.Sprintf("block timestamp behind parents: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
 	}
 
-	if (header.Timestamp()-parent.Timestamp())%ablock.BlockInterval != 0 {
+	if (header.Timestamp()-parent.Timestamp())%c.config.BlockInterval != 0 {
 		return consensusError(fmt.Sprintf("block interval not rounded: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
 	}
 
-	if header.Timestamp() > nowTimestamp+ablock.BlockInterval {
+	if header.Timestamp() > nowTimestamp+c.config.BlockInterval {
 		return errFutureBlock
 	}
 
@@ -185,14 +185,14 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 
 	var sched poa.Scheduler
 	if header.Number() < c.forkConfig.VIP214 {
-		sched, err = poa.NewSchedulerV1(signer, proposers, parent.Number(), parent.Timestamp())
+		sched, err = poa.NewSchedulerV1(signer, proposers, parent.Number(), parent.Timestamp(), c.config.BlockInterval)
 	} else {
 		var seed []byte
 		seed, err = c.seeder.Generate(header.ParentID())
 		if err != nil {
 			return nil, err
 		}
-		sched, err = poa.NewSchedulerV2(signer, proposers, parent.Number(), parent.Timestamp(), seed)
+		sched, err = poa.NewSchedulerV2(signer, proposers, parent.Number(), parent.Timestamp(), c.config.BlockInterval, seed)
 	}
 	if err != nil {
 		return nil, consensusError(fmt.Sprintf("block signer invalid: %v %v", signer, err))
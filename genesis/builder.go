@@ -8,6 +8,7 @@ package genesis
 import (
 	"math"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
 	"github.com/ashishaw/authorityblock/block"
 	"github.com/ashishaw/authorityblock/muxdb"
@@ -28,6 +29,7 @@ type Builder struct {
 	extraData  [28]byte
 
 	forkConfig ablock.ForkConfig
+	config     ablock.Config
 }
 
 type call struct {
@@ -71,6 +73,12 @@ func (b *Builder) ForkConfig(fc ablock.ForkConfig) *Builder {
 	return b
 }
 
+// Config set timing config. A non-default config is folded into the genesis ID.
+func (b *Builder) Config(c ablock.Config) *Builder {
+	b.config = c
+	return b
+}
+
 // ComputeID compute genesis ID.
 func (b *Builder) ComputeID() (ablock.Bytes32, error) {
 	db := muxdb.NewMem()
@@ -123,6 +131,15 @@ func (b *Builder) Build(stater *state.Stater) (blk *block.Block, events tx.Event
 
 	parentID := ablock.Bytes32{0xff, 0xff, 0xff, 0xff} //so, genesis number is 0
 	copy(parentID[4:], b.extraData[:])
+	// networks with different timing should never connect to each other
+	if config := b.config.WithDefaults(); config != ablock.DefaultConfig {
+		data, err := rlp.EncodeToBytes(&config)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "encode config")
+		}
+		hash := ablock.Blake2b(b.extraData[:], data)
+		copy(parentID[4:], hash[:])
+	}
 
 	return new(block.Builder).
 		ParentID(parentID).
@@ -29,6 +29,7 @@ type CustomGenesis struct {
 	Params     Params           `json:"params"`
 	Executor   Executor         `json:"executor"`
 	ForkConfig *ablock.ForkConfig `json:"forkConfig"`
+	Config     *ablock.Config   `json:"config"`
 }
 
 // NewCustomNet create custom network genesis.
@@ -38,6 +39,16 @@ func NewCustomNet(gen *CustomGenesis) (*Genesis, error) {
 	if gen.GasLimit == 0 {
 		gen.GasLimit = ablock.InitialGasLimit
 	}
+	if err := gen.ForkConfig.Validate(); err != nil {
+		return nil, err
+	}
+	var config ablock.Config
+	if gen.Config != nil {
+		config = *gen.Config
+	}
+	if err := config.Validate(*gen.ForkConfig); err != nil {
+		return nil, err
+	}
 	var executor ablock.Address
 	if gen.Params.ExecutorAddress != nil {
 		executor = *gen.Params.ExecutorAddress
@@ -49,6 +60,7 @@ func NewCustomNet(gen *CustomGenesis) (*Genesis, error) {
 		Timestamp(launchTime).
 		GasLimit(gen.GasLimit).
 		ForkConfig(*gen.ForkConfig).
+		Config(config).
 		State(func(state *state.State) error {
 			// alloc builtin contracts
 			if err := state.SetCode(builtin.Authority.Address, builtin.Authority.RuntimeBytecodes()); err != nil {
@@ -37,6 +37,11 @@ func (g *Genesis) ID() ablock.Bytes32 {
 	return g.id
 }
 
+// Config returns the timing config of the network.
+func (g *Genesis) Config() ablock.Config {
+	return g.builder.config.WithDefaults()
+}
+
 // Name returns network name.
 func (g *Genesis) Name() string {
 	return g.name
@@ -6,6 +6,8 @@
 package genesis_test
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,3 +30,37 @@ func TestTestnetGenesis(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, v)
 }
+
+func TestCustomNetConfig(t *testing.T) {
+	data, err := ioutil.ReadFile("example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newGenesis := func(config *ablock.Config) (*genesis.Genesis, error) {
+		var gen genesis.CustomGenesis
+		if err := json.Unmarshal(data, &gen); err != nil {
+			t.Fatal(err)
+		}
+		forkConfig := ablock.NoFork
+		gen.ForkConfig = &forkConfig
+		gen.Config = config
+		return genesis.NewCustomNet(&gen)
+	}
+
+	def, err := newGenesis(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ablock.DefaultConfig, def.Config())
+
+	// the default config doesn't change the genesis ID
+	gene, err := newGenesis(&ablock.Config{BlockInterval: ablock.DefaultConfig.BlockInterval})
+	assert.Nil(t, err)
+	assert.Equal(t, def.ID(), gene.ID())
+
+	gene, err = newGenesis(&ablock.Config{BlockInterval: 5})
+	assert.Nil(t, err)
+	assert.NotEqual(t, def.ID(), gene.ID())
+	assert.Equal(t, ablock.Config{BlockInterval: 5, CheckpointInterval: ablock.DefaultConfig.CheckpointInterval}, gene.Config())
+
+	_, err = newGenesis(&ablock.Config{CheckpointInterval: 1})
+	assert.NotNil(t, err)
+}
@@ -11,9 +11,11 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"time"
 
 	sqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/metric"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
 )
@@ -22,6 +24,8 @@ const (
 	refIDQuery = "(SELECT id FROM ref WHERE data=?)"
 )
 
+var metricCommitTime = metric.NewHistogram("logdb_commit_seconds", "time to commit written logs", nil)
+
 type LogDB struct {
 	path          string
 	driverVersion string
@@ -548,10 +552,12 @@ func (w *Writer) Commit() (err error) {
 		return nil
 	}
 
+	startTime := time.Now()
 	defer func() {
 		if err == nil {
 			w.tx = nil
 			w.uncommittedCount = 0
+			metricCommitTime.Observe(time.Since(startTime).Seconds())
 		}
 	}()
 	return w.tx.Commit()
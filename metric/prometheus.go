@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package metric
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixed to all metric names.
+const namespace = "ablock"
+
+var registry = prometheus.NewRegistry()
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler returns the http handler which exposes all metrics in Prometheus format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// NewCounter creates and registers a counter.
+func NewCounter(name, help string) prometheus.Counter {
+	c := prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help})
+	registry.MustRegister(c)
+	return c
+}
+
+// NewCounterVec creates and registers a counter partitioned by labels.
+func NewCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help}, labels)
+	registry.MustRegister(c)
+	return c
+}
+
+// NewGauge creates and registers a gauge.
+func NewGauge(name, help string) prometheus.Gauge {
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help})
+	registry.MustRegister(g)
+	return g
+}
+
+// NewGaugeFunc creates and registers a gauge whose value is evaluated by f on each collection.
+// It returns a function to unregister the gauge.
+func NewGaugeFunc(name, help string, f func() float64) (unregister func()) {
+	g := prometheus.NewGaugeFunc(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help}, f)
+	registry.MustRegister(g)
+	return func() { registry.Unregister(g) }
+}
+
+// NewHistogram creates and registers a histogram. Default buckets are used if buckets is nil.
+func NewHistogram(name, help string, buckets []float64) prometheus.Histogram {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Namespace: namespace, Name: name, Help: help, Buckets: buckets})
+	registry.MustRegister(h)
+	return h
+}
+
+// NewHistogramVec creates and registers a histogram partitioned by labels.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: namespace, Name: name, Help: help, Buckets: buckets}, labels)
+	registry.MustRegister(h)
+	return h
+}
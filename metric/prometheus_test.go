@@ -0,0 +1,42 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package metric_test
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/metric"
+)
+
+func TestHandler(t *testing.T) {
+	metric.NewCounter("test_counter", "test counter").Add(3)
+	metric.NewCounterVec("test_counter_vec", "test counter vec", "kind").WithLabelValues("a").Inc()
+	unregister := metric.NewGaugeFunc("test_gauge_func", "test gauge func", func() float64 { return 42 })
+
+	scrape := func() string {
+		ts := httptest.NewServer(metric.Handler())
+		defer ts.Close()
+		res, err := ts.Client().Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return string(body)
+	}
+
+	body := scrape()
+	assert.Contains(t, body, "ablock_test_counter 3")
+	assert.Contains(t, body, `ablock_test_counter_vec{kind="a"} 1`)
+	assert.Contains(t, body, "ablock_test_gauge_func 42")
+	assert.Contains(t, body, "go_goroutines")
+
+	unregister()
+	assert.NotContains(t, scrape(), "ablock_test_gauge_func")
+}
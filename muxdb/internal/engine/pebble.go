@@ -0,0 +1,221 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package engine
+
+import (
+	"io"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ashishaw/authorityblock/kv"
+)
+
+type pebbleEngine struct {
+	db *pebble.DB
+}
+
+// NewPebbleEngine creates pebble instance which implements the Engine interface.
+func NewPebbleEngine(db *pebble.DB) Engine {
+	return &pebbleEngine{db}
+}
+
+func (pdb *pebbleEngine) Close() error {
+	return pdb.db.Close()
+}
+
+func (pdb *pebbleEngine) IsNotFound(err error) bool {
+	return err == pebble.ErrNotFound
+}
+
+// getCopy copies the value out, since the value returned by pebble is only valid before the closer closed.
+func getCopy(val []byte, closer io.Closer, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	cpy := append([]byte{}, val...)
+	if err := closer.Close(); err != nil {
+		return nil, err
+	}
+	return cpy, nil
+}
+
+func (pdb *pebbleEngine) Get(key []byte) ([]byte, error) {
+	return getCopy(pdb.db.Get(key))
+}
+
+func (pdb *pebbleEngine) Has(key []byte) (bool, error) {
+	_, closer, err := pdb.db.Get(key)
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, closer.Close()
+}
+
+func (pdb *pebbleEngine) Put(key, val []byte) error {
+	return pdb.db.Set(key, val, pebble.NoSync)
+}
+
+func (pdb *pebbleEngine) Delete(key []byte) error {
+	return pdb.db.Delete(key, pebble.NoSync)
+}
+
+func (pdb *pebbleEngine) Snapshot() kv.Snapshot {
+	s := pdb.db.NewSnapshot()
+	return &struct {
+		kv.GetFunc
+		kv.HasFunc
+		kv.IsNotFoundFunc
+		kv.ReleaseFunc
+	}{
+		func(key []byte) ([]byte, error) {
+			return getCopy(s.Get(key))
+		},
+		func(key []byte) (bool, error) {
+			_, closer, err := s.Get(key)
+			if err != nil {
+				if err == pebble.ErrNotFound {
+					return false, nil
+				}
+				return false, err
+			}
+			return true, closer.Close()
+		},
+		pdb.IsNotFound,
+		func() {
+			s.Close()
+		},
+	}
+}
+
+func (pdb *pebbleEngine) Bulk() kv.Bulk {
+	const idealBatchSize = 128 * 1024
+	var batch *pebble.Batch
+
+	getBatch := func() *pebble.Batch {
+		if batch == nil {
+			batch = pdb.db.NewBatch()
+		}
+		return batch
+	}
+	flush := func(minSize int) error {
+		if batch != nil && len(batch.Repr()) >= minSize {
+			if batch.Count() > 0 {
+				if err := batch.Commit(pebble.NoSync); err != nil {
+					return err
+				}
+			}
+			if err := batch.Close(); err != nil {
+				return err
+			}
+			batch = nil
+		}
+		return nil
+	}
+	var autoFlush bool
+
+	return &struct {
+		kv.PutFunc
+		kv.DeleteFunc
+		kv.EnableAutoFlushFunc
+		kv.WriteFunc
+	}{
+		func(key, val []byte) error {
+			if err := getBatch().Set(key, val, nil); err != nil {
+				return err
+			}
+			if autoFlush {
+				return flush(idealBatchSize)
+			}
+			return nil
+		},
+		func(key []byte) error {
+			if err := getBatch().Delete(key, nil); err != nil {
+				return err
+			}
+			if autoFlush {
+				return flush(idealBatchSize)
+			}
+			return nil
+		},
+		func() { autoFlush = true },
+		func() error { return flush(0) },
+	}
+}
+
+func (pdb *pebbleEngine) Iterate(r kv.Range) kv.Iterator {
+	// bounds must be kept unchanged during iteration, so copy them
+	opts := pebble.IterOptions{}
+	if len(r.Start) > 0 {
+		opts.LowerBound = append([]byte{}, r.Start...)
+	}
+	if len(r.Limit) > 0 {
+		opts.UpperBound = append([]byte{}, r.Limit...)
+	}
+	return &pebbleIterator{iter: pdb.db.NewIter(&opts)}
+}
+
+// pebbleIterator adapts pebble iterator to act like leveldb iterator, which can be
+// moved by Next/Prev before positioned.
+type pebbleIterator struct {
+	iter       *pebble.Iterator
+	positioned bool
+	released   bool
+	err        error
+}
+
+func (i *pebbleIterator) First() bool {
+	i.positioned = true
+	return i.iter.First()
+}
+
+func (i *pebbleIterator) Last() bool {
+	i.positioned = true
+	return i.iter.Last()
+}
+
+func (i *pebbleIterator) Next() bool {
+	if !i.positioned {
+		return i.First()
+	}
+	return i.iter.Next()
+}
+
+func (i *pebbleIterator) Prev() bool {
+	if !i.positioned {
+		return i.Last()
+	}
+	return i.iter.Prev()
+}
+
+func (i *pebbleIterator) Key() []byte {
+	if !i.iter.Valid() {
+		return nil
+	}
+	return i.iter.Key()
+}
+
+func (i *pebbleIterator) Value() []byte {
+	if !i.iter.Valid() {
+		return nil
+	}
+	return i.iter.Value()
+}
+
+func (i *pebbleIterator) Release() {
+	if !i.released {
+		i.released = true
+		i.err = i.iter.Close()
+	}
+}
+
+func (i *pebbleIterator) Error() error {
+	if i.released {
+		return i.err
+	}
+	return i.iter.Error()
+}
@@ -183,6 +183,15 @@ func (c *Cache) GetRootNode(name string, seq uint64, peek bool) (trie.Node, bool
 	return trie.Node{}, false
 }
 
+// Stats returns the cumulative hit and miss counts of node cache and root cache.
+func (c *Cache) Stats() (nodeHit, nodeMiss, rootHit, rootMiss int64) {
+	if c == nil {
+		return
+	}
+	return atomic.LoadInt64(&c.nodeStats.hit), atomic.LoadInt64(&c.nodeStats.miss),
+		atomic.LoadInt64(&c.rootStats.hit), atomic.LoadInt64(&c.rootStats.miss)
+}
+
 type cacheStats struct {
 	hit, miss int64
 	flag      int32
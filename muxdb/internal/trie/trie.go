@@ -24,10 +24,12 @@ type Backend struct {
 	Cache    *Cache
 	LeafBank *LeafBank
 	HistSpace,
-	DedupedSpace byte
+	DedupedSpace,
+	ArchiveSpace byte
 	HistPtnFactor,
 	DedupedPtnFactor uint32
 	CachedNodeTTL uint16
+	UseArchive    bool // whether to look up nodes in the archive space
 }
 
 // sequence helps convert sequence number from/to commitNum & distinctNum.
@@ -101,6 +103,15 @@ func (t *Trie) makeDedupedNodeKey(dst []byte, seq sequence, path []byte) []byte
 	return dst
 }
 
+func (t *Trie) makeArchivedNodeKey(dst []byte, seq sequence, path []byte) []byte {
+	dst = append(dst, t.back.ArchiveSpace)     // space
+	dst = append(dst, t.name...)               // trie name
+	dst = encodePath(dst, path)                // path
+	dst = appendUint32(dst, seq.CommitNum())   // commit num
+	dst = appendUint32(dst, seq.DistinctNum()) // distinct num
+	return dst
+}
+
 // newDatabase creates a database instance for low-level trie construction.
 func (t *Trie) newDatabase() trie.Database {
 	var (
@@ -165,6 +176,16 @@ func (t *Trie) newDatabase() trie.Database {
 				}
 			}
 
+			// then from archive space, which must precede the deduped space, where nodes of the same path are overwritten
+			if t.back.UseArchive {
+				keyBuf = t.makeArchivedNodeKey(keyBuf[:0], thisSeq, thisPath)
+				if val, err := snapshot.Get(keyBuf); err == nil {
+					return append(dst, val...), nil
+				} else if !snapshot.IsNotFound(err) {
+					return nil, err
+				}
+			}
+
 			// then from deduped space
 			keyBuf = t.makeDedupedNodeKey(keyBuf[:0], thisSeq, thisPath)
 			if val, err := snapshot.Get(keyBuf); err == nil {
@@ -252,6 +273,25 @@ func (t *Trie) FastGet(key []byte, steadyCommitNum uint32) ([]byte, []byte, erro
 	return val, meta, nil
 }
 
+// LookupLeaf looks up the leaf bank for the key. The leaf of the returned record is nil if undetermined,
+// otherwise it's the value for key of any trie version within [CommitNum, SlotCommitNum] on the steady chain.
+func (t *Trie) LookupLeaf(key []byte) (*LeafRecord, error) {
+	if t.back.LeafBank == nil {
+		return &LeafRecord{}, nil
+	}
+	return t.back.LeafBank.Lookup(t.name, key)
+}
+
+// Prove constructs a merkle proof for key. The proof contains the encoded nodes
+// on the path to the key, in order from the root.
+func (t *Trie) Prove(key []byte) ([][]byte, error) {
+	var proof trie.ProofList
+	if err := t.ext.Prove(key, 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
 // Update associates key with value in the trie. Subsequent calls to
 // Get will return value. If value has length zero, any existing value
 // is deleted from the trie and calls to Get will return nil.
@@ -419,6 +459,44 @@ func (t *Trie) DumpNodes(ctx context.Context, baseCommitNum uint32, handleLeaf f
 	return bulk.Write()
 }
 
+// ArchiveNodes archives referenced nodes committed within [baseCommitNum, thisCommitNum], into the archive space.
+// Unlike deduped nodes, archived nodes are never overwritten by later commits, so the trie of this version
+// keeps accessible after its history cleaned.
+func (t *Trie) ArchiveNodes(ctx context.Context, baseCommitNum uint32, handleLeaf func(*trie.Leaf)) error {
+	if t.dirty {
+		return errors.New("dirty trie")
+	}
+	var (
+		checkContext = newContextChecker(ctx, 5000)
+		bulk         = t.back.Store.Bulk()
+		iter         = t.NodeIterator(nil, baseCommitNum)
+		buf          []byte
+	)
+	bulk.EnableAutoFlush()
+
+	for iter.Next(true) {
+		if err := checkContext(); err != nil {
+			return err
+		}
+
+		if err := iter.Node(func(blob []byte) error {
+			buf = t.makeArchivedNodeKey(buf[:0], sequence(iter.SeqNum()), iter.Path())
+			return bulk.Put(buf, blob)
+		}); err != nil {
+			return err
+		}
+		if handleLeaf != nil {
+			if leaf := iter.Leaf(); leaf != nil {
+				handleLeaf(leaf)
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return bulk.Write()
+}
+
 // CleanHistory cleans history nodes within [startCommitNum, limitCommitNum).
 func CleanHistory(ctx context.Context, back *Backend, startCommitNum, limitCommitNum uint32) error {
 	if limitCommitNum == 0 {
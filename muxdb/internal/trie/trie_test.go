@@ -31,6 +31,7 @@ func newBackend() *Backend {
 		LeafBank:         NewLeafBank(engine, 2, 100),
 		HistSpace:        0,
 		DedupedSpace:     1,
+		ArchiveSpace:     3,
 		HistPtnFactor:    1,
 		DedupedPtnFactor: 1,
 		CachedNodeTTL:    100,
@@ -119,4 +120,51 @@ func TestTrie(t *testing.T) {
 			}
 		}
 	})
+	t.Run("archive nodes", func(t *testing.T) {
+		back := newBackend()
+		back.UseArchive = true
+		tr := New(back, name, ablock.Bytes32{}, 0, 0, false)
+
+		var roots []ablock.Bytes32
+		for i := 0; i < 20; i++ {
+			for j := 0; j < 100; j++ {
+				key := []byte(strconv.Itoa(j))
+				val := []byte("v" + strconv.Itoa(j) + "_" + strconv.Itoa(i))
+				tr.Update(key, val, nil)
+			}
+			root, commit := tr.Stage(uint32(i), 0)
+			if err := commit(); err != nil {
+				t.Fatal(err)
+			}
+			roots = append(roots, root)
+		}
+
+		if err := New(back, name, roots[9], 9, 0, false).ArchiveNodes(context.Background(), 0, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := New(back, name, roots[18], 18, 0, false).DumpNodes(context.Background(), 0, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := CleanHistory(context.Background(), back, 1, 19); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, i := range []int{0, 9, 18, 19} {
+			tr := New(back, name, roots[i], uint32(i), 0, false)
+			for j := 0; j < 100; j++ {
+				val, _, err := tr.Get([]byte(strconv.Itoa(j)))
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("v"+strconv.Itoa(j)+"_"+strconv.Itoa(i)), val)
+			}
+		}
+
+		// neither dumped nor archived
+		_, _, err := New(back, name, roots[5], 5, 0, false).Get([]byte("0"))
+		assert.NotNil(t, err)
+
+		// archive space not looked up
+		back.UseArchive = false
+		_, _, err = New(back, name, roots[9], 9, 0, false).Get([]byte("0"))
+		assert.NotNil(t, err)
+	})
 }
@@ -9,8 +9,15 @@ package muxdb
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
 	"github.com/syndtr/goleveldb/leveldb"
 	dberrors "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
@@ -27,16 +34,27 @@ const (
 	trieDedupedSpace  = byte(1) // the key space for deduped trie nodes.
 	trieLeafBankSpace = byte(2) // the key space for the trie leaf bank.
 	namedStoreSpace   = byte(3) // the key space for named store.
+	trieArchiveSpace  = byte(4) // the key space for archived trie nodes.
 )
 
 const (
-	propStoreName = "muxdb.props"
-	configKey     = "config"
+	propStoreName       = "muxdb.props"
+	configKey           = "config"
+	trieHistoryLimitKey = "trieHistoryLimit"
+)
+
+// supported engines.
+const (
+	LevelDBEngine = "leveldb"
+	PebbleEngine  = "pebble"
 )
 
 // Trie is the managed trie.
 type Trie = trie.Trie
 
+// LeafRecord is the leaf record queried from the leaf bank.
+type LeafRecord = trie.LeafRecord
+
 // Options optional parameters for MuxDB.
 type Options struct {
 	// TrieNodeCacheSizeMB is the size of the cache for trie node blobs.
@@ -53,6 +71,8 @@ type Options struct {
 	TrieDedupedPartitionFactor uint32
 	// TrieWillCleanHistory is the hint to tell if historical nodes will be cleaned.
 	TrieWillCleanHistory bool
+	// TrieUseArchive is the hint to tell if trie nodes are archived, so that nodes are looked up in the archive space.
+	TrieUseArchive bool
 
 	// OpenFilesCacheCapacity is the capacity of open files caching for underlying database.
 	OpenFilesCacheCapacity int
@@ -60,6 +80,9 @@ type Options struct {
 	ReadCacheMB int
 	// WriteBufferMB is the size of write buffer for underlying database.
 	WriteBufferMB int
+	// Engine is the underlying database engine, LevelDBEngine or PebbleEngine.
+	// If empty, the engine of the existing database is used, or LevelDBEngine for a new one.
+	Engine string
 }
 
 // MuxDB is the database to efficiently store state trie and block-chain data.
@@ -70,34 +93,32 @@ type MuxDB struct {
 
 // Open opens or creates DB at the given path.
 func Open(path string, options *Options) (*MuxDB, error) {
-	// prepare leveldb options
-	ldbOpts := opt.Options{
-		OpenFilesCacheCapacity: options.OpenFilesCacheCapacity,
-		BlockCacheCapacity:     options.ReadCacheMB * opt.MiB,
-		WriteBuffer:            options.WriteBufferMB * opt.MiB,
-		Filter:                 filter.NewBloomFilter(10),
-		BlockSize:              1024 * 32, // balance performance of point reads and compression ratio.
-		CompactionTableSize:    4 * opt.MiB,
+	existing, err := DetectEngine(path)
+	if err != nil {
+		return nil, err
 	}
-
-	if options.TrieWillCleanHistory {
-		// this option gets disk space efficiently reclaimed.
-		// only set when pruner enabled.
-		ldbOpts.OverflowPrefix = []byte{trieHistSpace}
+	name := options.Engine
+	if name == "" {
+		if name = existing; name == "" {
+			name = LevelDBEngine
+		}
+	} else if existing != "" && existing != name {
+		return nil, fmt.Errorf("database engine mismatch, want %v but %v found, migration required", name, existing)
 	}
 
-	// open leveldb
-	ldb, err := leveldb.OpenFile(path, &ldbOpts)
-	if _, corrupted := err.(*dberrors.ErrCorrupted); corrupted {
-		ldb, err = leveldb.RecoverFile(path, &ldbOpts)
+	var engine engine.Engine
+	switch name {
+	case LevelDBEngine:
+		engine, err = openLevelEngine(path, options)
+	case PebbleEngine:
+		engine, err = openPebbleEngine(path, options)
+	default:
+		return nil, fmt.Errorf("unsupported database engine %v", name)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	// as engine
-	engine := engine.NewLevelEngine(ldb)
-
 	propStore := kv.Bucket(string(namedStoreSpace) + propStoreName).NewStore(engine)
 	// persists critical options to avoid corruption when tweaked.
 	cfg := config{
@@ -105,7 +126,7 @@ func Open(path string, options *Options) (*MuxDB, error) {
 		DedupedPtnFactor: options.TrieDedupedPartitionFactor,
 	}
 	if err := cfg.LoadOrSave(propStore); err != nil {
-		ldb.Close()
+		engine.Close()
 		return nil, err
 	}
 
@@ -126,13 +147,91 @@ func Open(path string, options *Options) (*MuxDB, error) {
 			LeafBank:         trieLeafBank,
 			HistSpace:        trieHistSpace,
 			DedupedSpace:     trieDedupedSpace,
+			ArchiveSpace:     trieArchiveSpace,
 			HistPtnFactor:    cfg.HistPtnFactor,
 			DedupedPtnFactor: cfg.DedupedPtnFactor,
 			CachedNodeTTL:    options.TrieCachedNodeTTL,
+			UseArchive:       options.TrieUseArchive,
 		},
 	}, nil
 }
 
+// DetectEngine returns the engine of the existing database at the given path.
+// Empty string returned if there's no database.
+func DetectEngine(path string) (string, error) {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	var hasCurrent bool
+	for _, e := range entries {
+		// pebble persists its options in files named OPTIONS-<num>, while leveldb never does
+		if strings.HasPrefix(e.Name(), "OPTIONS-") {
+			return PebbleEngine, nil
+		}
+		if e.Name() == "CURRENT" {
+			hasCurrent = true
+		}
+	}
+	if hasCurrent {
+		return LevelDBEngine, nil
+	}
+	return "", nil
+}
+
+func openLevelEngine(path string, options *Options) (engine.Engine, error) {
+	// prepare leveldb options
+	ldbOpts := opt.Options{
+		OpenFilesCacheCapacity: options.OpenFilesCacheCapacity,
+		BlockCacheCapacity:     options.ReadCacheMB * opt.MiB,
+		WriteBuffer:            options.WriteBufferMB * opt.MiB,
+		Filter:                 filter.NewBloomFilter(10),
+		BlockSize:              1024 * 32, // balance performance of point reads and compression ratio.
+		CompactionTableSize:    4 * opt.MiB,
+	}
+
+	if options.TrieWillCleanHistory {
+		// this option gets disk space efficiently reclaimed.
+		// only set when pruner enabled.
+		ldbOpts.OverflowPrefix = []byte{trieHistSpace}
+	}
+
+	// open leveldb
+	ldb, err := leveldb.OpenFile(path, &ldbOpts)
+	if _, corrupted := err.(*dberrors.ErrCorrupted); corrupted {
+		ldb, err = leveldb.RecoverFile(path, &ldbOpts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return engine.NewLevelEngine(ldb), nil
+}
+
+func openPebbleEngine(path string, options *Options) (engine.Engine, error) {
+	cache := pebble.NewCache(int64(options.ReadCacheMB) * opt.MiB)
+	defer cache.Unref()
+
+	opts := pebble.Options{
+		Cache:        cache,
+		MaxOpenFiles: options.OpenFilesCacheCapacity,
+		MemTableSize: options.WriteBufferMB * opt.MiB,
+		// mirrors the leveldb options
+		Levels: []pebble.LevelOptions{{
+			BlockSize:      1024 * 32,
+			FilterPolicy:   bloom.FilterPolicy(10),
+			TargetFileSize: 4 * opt.MiB,
+		}},
+	}
+	db, err := pebble.Open(path, &opts)
+	if err != nil {
+		return nil, err
+	}
+	return engine.NewPebbleEngine(db), nil
+}
+
 // NewMem creates a memory-backed DB.
 func NewMem() *MuxDB {
 	storage := storage.NewMemStorage()
@@ -147,6 +246,7 @@ func NewMem() *MuxDB {
 			LeafBank:         nil,
 			HistSpace:        trieHistSpace,
 			DedupedSpace:     trieDedupedSpace,
+			ArchiveSpace:     trieArchiveSpace,
 			HistPtnFactor:    1,
 			DedupedPtnFactor: 1,
 			CachedNodeTTL:    32,
@@ -190,8 +290,40 @@ func (db *MuxDB) NewNonCryptoTrie(name string, root ablock.Bytes32, commitNum, d
 }
 
 // CleanTrieHistory clean trie history within [startCommitNum, limitCommitNum).
+// The limit is recorded, see TrieHistoryLimit.
 func (db *MuxDB) CleanTrieHistory(ctx context.Context, startCommitNum, limitCommitNum uint32) error {
-	return trie.CleanHistory(ctx, db.trieBackend, startCommitNum, limitCommitNum)
+	if err := trie.CleanHistory(ctx, db.trieBackend, startCommitNum, limitCommitNum); err != nil {
+		return err
+	}
+	limit, err := db.TrieHistoryLimit()
+	if err != nil {
+		return err
+	}
+	if limitCommitNum > limit {
+		var val [4]byte
+		binary.BigEndian.PutUint32(val[:], limitCommitNum)
+		return db.NewStore(propStoreName).Put([]byte(trieHistoryLimitKey), val[:])
+	}
+	return nil
+}
+
+// TrieHistoryLimit returns the max limit commit number ever passed to CleanTrieHistory.
+// Trie versions committed before it are accessible only if dumped or archived.
+// Zero returned if trie history never cleaned.
+func (db *MuxDB) TrieHistoryLimit() (uint32, error) {
+	val, err := db.NewStore(propStoreName).Get([]byte(trieHistoryLimitKey))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(val), nil
+}
+
+// TrieCacheStats returns the cumulative hit and miss counts of trie node cache and root cache.
+func (db *MuxDB) TrieCacheStats() (nodeHit, nodeMiss, rootHit, rootMiss int64) {
+	return db.trieBackend.Cache.Stats()
 }
 
 // NewStore creates named kv-store.
@@ -199,6 +331,44 @@ func (db *MuxDB) NewStore(name string) kv.Store {
 	return kv.Bucket(string(namedStoreSpace) + name).NewStore(db.engine)
 }
 
+// CopyTo copies all data into the destination DB, which is typically backed by another engine.
+// The progress callback is called periodically with the count of copied entries.
+func (db *MuxDB) CopyTo(ctx context.Context, dst *MuxDB, progress func(n int)) error {
+	var (
+		iter = db.engine.Iterate(kv.Range{})
+		bulk = dst.engine.Bulk()
+		n    int
+	)
+	defer iter.Release()
+	bulk.EnableAutoFlush()
+
+	for iter.Next() {
+		if err := bulk.Put(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+		if n++; n%100000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			if progress != nil {
+				progress(n)
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if err := bulk.Write(); err != nil {
+		return err
+	}
+	if progress != nil {
+		progress(n)
+	}
+	return nil
+}
+
 // IsNotFound returns if the error indicates key not found.
 func (db *MuxDB) IsNotFound(err error) bool {
 	return db.engine.IsNotFound(err)
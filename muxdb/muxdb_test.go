@@ -0,0 +1,159 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package muxdb
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+var engines = []string{LevelDBEngine, PebbleEngine}
+
+func openTemp(tb testing.TB, path, engine string) *MuxDB {
+	db, err := Open(path, &Options{
+		TrieNodeCacheSizeMB:        1,
+		TrieRootCacheCapacity:      1,
+		TrieCachedNodeTTL:          1,
+		TrieLeafBankSlotCapacity:   1,
+		TrieHistPartitionFactor:    1000,
+		TrieDedupedPartitionFactor: 1000,
+		OpenFilesCacheCapacity:     100,
+		ReadCacheMB:                8,
+		WriteBufferMB:              4,
+		Engine:                     engine,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+// buildTrie commits n batches of m random entries into the trie, and returns roots of all versions.
+func buildTrie(tb testing.TB, db *MuxDB, n, m int) []ablock.Bytes32 {
+	var (
+		roots []ablock.Bytes32
+		root  ablock.Bytes32
+		key   [32]byte
+	)
+	for i := 0; i < n; i++ {
+		tr := db.NewTrie("t", root, uint32(i), 0)
+		for j := 0; j < m; j++ {
+			binary.BigEndian.PutUint64(key[:], uint64(i*m+j))
+			hashed := ablock.Blake2b(key[:])
+			if err := tr.Update(hashed[:], append([]byte(nil), key[:]...), nil); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		newRoot, commit := tr.Stage(uint32(i+1), 0)
+		if err := commit(); err != nil {
+			tb.Fatal(err)
+		}
+		root = newRoot
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+func TestEngines(t *testing.T) {
+	for _, engine := range engines {
+		t.Run(engine, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "main.db")
+			db := openTemp(t, path, engine)
+			roots := buildTrie(t, db, 10, 100)
+			assert.Nil(t, db.Close())
+
+			detected, err := DetectEngine(path)
+			assert.Nil(t, err)
+			assert.Equal(t, engine, detected)
+
+			// reopen with the detected engine
+			db = openTemp(t, path, "")
+			defer db.Close()
+			val, _, err := db.NewTrie("t", roots[9], 10, 0).Get(ablock.Blake2b(make([]byte, 32)).Bytes())
+			assert.Nil(t, err)
+			assert.Equal(t, make([]byte, 32), val)
+		})
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "main.db")
+		openTemp(t, path, LevelDBEngine).Close()
+
+		_, err := Open(path, &Options{Engine: PebbleEngine})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCopyTo(t *testing.T) {
+	dir := t.TempDir()
+	src := openTemp(t, filepath.Join(dir, "src"), LevelDBEngine)
+	defer src.Close()
+	dst := openTemp(t, filepath.Join(dir, "dst"), PebbleEngine)
+	defer dst.Close()
+
+	roots := buildTrie(t, src, 10, 100)
+	assert.Nil(t, src.NewStore("s").Put([]byte("k"), []byte("v")))
+
+	var copied int
+	assert.Nil(t, src.CopyTo(context.Background(), dst, func(n int) { copied = n }))
+	assert.True(t, copied > 0)
+
+	var key [32]byte
+	for i, root := range roots {
+		tr := dst.NewTrie("t", root, uint32(i+1), 0)
+		for j := 0; j < (i+1)*100; j += 37 {
+			binary.BigEndian.PutUint64(key[:], uint64(j))
+			val, _, err := tr.Get(ablock.Blake2b(key[:]).Bytes())
+			assert.Nil(t, err)
+			assert.Equal(t, key[:], val)
+		}
+	}
+	val, err := dst.NewStore("s").Get([]byte("k"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v"), val)
+}
+
+func BenchmarkTrieWrite(b *testing.B) {
+	for _, engine := range engines {
+		b.Run(engine, func(b *testing.B) {
+			db := openTemp(b, filepath.Join(b.TempDir(), "main.db"), engine)
+			defer db.Close()
+
+			b.ResetTimer()
+			buildTrie(b, db, b.N, 100)
+		})
+	}
+}
+
+func BenchmarkTrieRead(b *testing.B) {
+	const n, m = 100, 1000
+
+	for _, engine := range engines {
+		b.Run(engine, func(b *testing.B) {
+			db := openTemp(b, filepath.Join(b.TempDir(), "main.db"), engine)
+			defer db.Close()
+			roots := buildTrie(b, db, n, m)
+			tr := db.NewTrie("t", roots[n-1], n, 0)
+			tr.SetNoFillCache(true)
+
+			random := rand.New(rand.NewSource(0))
+			var key [32]byte
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				binary.BigEndian.PutUint64(key[:], uint64(random.Intn(n*m)))
+				if _, _, err := tr.Copy().Get(ablock.Blake2b(key[:]).Bytes()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
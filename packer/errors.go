@@ -12,6 +12,7 @@ var (
 	errTxNotAdoptableNow     = errors.New("tx not adoptable now")
 	errTxNotAdoptableForever = errors.New("tx not adoptable forever")
 	errKnownTx               = errors.New("known tx")
+	errOriginBlocked         = badTxError{"tx origin blocked"}
 )
 
 // IsGasLimitReached block if full of txs.
@@ -24,6 +25,16 @@ func IsTxNotAdoptableNow(err error) bool {
 	return errors.Cause(err) == errTxNotAdoptableNow
 }
 
+// IsTxNotAdoptableForever tx can never be adopted, since its dependency reverted.
+func IsTxNotAdoptableForever(err error) bool {
+	return errors.Cause(err) == errTxNotAdoptableForever
+}
+
+// IsBlockedOrigin the origin of tx is blocked. It's also a bad tx.
+func IsBlockedOrigin(err error) bool {
+	return errors.Cause(err) == errOriginBlocked
+}
+
 // IsBadTx not a valid tx.
 func IsBadTx(err error) bool {
 	_, ok := errors.Cause(err).(badTxError)
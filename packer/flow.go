@@ -7,15 +7,17 @@ package packer
 
 import (
 	"crypto/ecdsa"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 	"github.com/ashishaw/authorityblock/block"
 	"github.com/ashishaw/authorityblock/runtime"
+	"github.com/ashishaw/authorityblock/signer"
 	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
-	"github.com/ashishaw/authorityblock/vrf"
 )
 
 // Flow the flow of packing a new block.
@@ -28,6 +30,7 @@ type Flow struct {
 	txs          tx.Transactions
 	receipts     tx.Receipts
 	features     tx.Features
+	report       Report
 }
 
 func newFlow(
@@ -42,6 +45,11 @@ func newFlow(
 		runtime:      runtime,
 		processedTxs: make(map[ablock.Bytes32]bool),
 		features:     features,
+		report: Report{
+			Number:    runtime.Context().Number,
+			Timestamp: runtime.Context().Time,
+			GasLimit:  runtime.Context().GasLimit,
+		},
 	}
 }
 
@@ -79,13 +87,34 @@ func (f *Flow) findTx(txID ablock.Bytes32) (found bool, reverted bool, err error
 	return true, txMeta.Reverted, nil
 }
 
+// Report returns the statistics of the flow so far.
+func (f *Flow) Report() *Report {
+	r := f.report
+	r.GasUsed = f.gasUsed
+	return &r
+}
+
 // Adopt try to execute the given transaction.
 // If the tx is valid and can be executed on current state (regardless of VM error),
 // it will be adopted by the new block.
 func (f *Flow) Adopt(tx *tx.Transaction) error {
+	startTime := mclock.Now()
+	err := f.adopt(tx)
+
+	f.report.AdoptTime += time.Duration(mclock.Now() - startTime)
+	f.report.Candidates++
+	if err != nil {
+		f.report.Rejected.add(err)
+	} else {
+		f.report.Adopted++
+	}
+	return err
+}
+
+func (f *Flow) adopt(tx *tx.Transaction) error {
 	origin, _ := tx.Origin()
 	if f.Number() >= f.packer.forkConfig.BLOCKLIST && ablock.IsOriginBlocked(origin) {
-		return badTxError{"tx origin blocked"}
+		return errOriginBlocked
 	}
 
 	if err := tx.TestFeatures(f.features); err != nil {
@@ -148,6 +177,14 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, newBlockConflicts uint32, shou
 	if f.packer.nodeMaster != ablock.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
 		return nil, nil, nil, errors.New("private key mismatch")
 	}
+	return f.PackWithSigner(signer.NewKeySigner(privateKey), newBlockConflicts, shouldVote)
+}
+
+// PackWithSigner build and sign the new block with the given signer.
+func (f *Flow) PackWithSigner(s signer.Signer, newBlockConflicts uint32, shouldVote bool) (*block.Block, *state.Stage, tx.Receipts, error) {
+	if f.packer.nodeMaster != s.Address() {
+		return nil, nil, nil, errors.New("signer mismatch")
+	}
 
 	stage, err := f.runtime.State().Stage(f.Number(), newBlockConflicts)
 	if err != nil {
@@ -177,11 +214,19 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, newBlockConflicts uint32, shou
 	if f.Number() < f.packer.forkConfig.VIP214 {
 		newBlock := builder.Build()
 
-		sig, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
+		res, err := s.Sign(&signer.Request{
+			Number:      f.Number(),
+			Timestamp:   newBlock.Header().Timestamp(),
+			SigningHash: newBlock.Header().SigningHash(),
+		})
 		if err != nil {
 			return nil, nil, nil, err
 		}
-		return newBlock.WithSignature(sig), stage, f.receipts, nil
+		signed := newBlock.WithSignature(res.Signature)
+		if err := verifySigned(signed.Header(), s.Address()); err != nil {
+			return nil, nil, nil, err
+		}
+		return signed, stage, f.receipts, nil
 	} else {
 		parentBeta, err := f.parentHeader.Beta()
 		if err != nil {
@@ -197,20 +242,40 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, newBlockConflicts uint32, shou
 		}
 
 		newBlock := builder.Alpha(alpha).Build()
-		ec, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
+		res, err := s.Sign(&signer.Request{
+			Number:      f.Number(),
+			Timestamp:   newBlock.Header().Timestamp(),
+			SigningHash: newBlock.Header().SigningHash(),
+			Alpha:       alpha,
+		})
 		if err != nil {
 			return nil, nil, nil, err
 		}
 
-		_, proof, err := vrf.Prove(privateKey, alpha)
+		sig, err := block.NewComplexSignature(res.Signature, res.Proof)
 		if err != nil {
 			return nil, nil, nil, err
 		}
-		sig, err := block.NewComplexSignature(ec, proof)
-		if err != nil {
+
+		signed := newBlock.WithSignature(sig)
+		if err := verifySigned(signed.Header(), s.Address()); err != nil {
 			return nil, nil, nil, err
 		}
+		return signed, stage, f.receipts, nil
+	}
+}
 
-		return newBlock.WithSignature(sig), stage, f.receipts, nil
+// verifySigned verifies the signature and VRF proof made by the signer, which might be remote.
+func verifySigned(header *block.Header, master ablock.Address) error {
+	addr, err := header.Signer()
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	if addr != master {
+		return errors.New("signature mismatch")
 	}
+	if _, err := header.Beta(); err != nil {
+		return errors.Wrap(err, "invalid vrf proof")
+	}
+	return nil
 }
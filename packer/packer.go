@@ -25,6 +25,7 @@ type Packer struct {
 	beneficiary    *ablock.Address
 	targetGasLimit uint64
 	forkConfig     ablock.ForkConfig
+	config         ablock.Config
 	seeder         *poa.Seeder
 }
 
@@ -35,7 +36,8 @@ func New(
 	stater *state.Stater,
 	nodeMaster ablock.Address,
 	beneficiary *ablock.Address,
-	forkConfig ablock.ForkConfig) *Packer {
+	forkConfig ablock.ForkConfig,
+	config ablock.Config) *Packer {
 
 	return &Packer{
 		repo,
@@ -44,6 +46,7 @@ func New(
 		beneficiary,
 		0,
 		forkConfig,
+		config,
 		poa.NewSeeder(repo),
 	}
 }
@@ -99,14 +102,14 @@ func (p *Packer) Schedule(parent *chain.BlockSummary, nowTimestamp uint64) (flow
 	// calc the time when it's turn to produce block
 	var sched poa.Scheduler
 	if parent.Header.Number()+1 < p.forkConfig.VIP214 {
-		sched, err = poa.NewSchedulerV1(p.nodeMaster, proposers, parent.Header.Number(), parent.Header.Timestamp())
+		sched, err = poa.NewSchedulerV1(p.nodeMaster, proposers, parent.Header.Number(), parent.Header.Timestamp(), p.config.BlockInterval)
 	} else {
 		var seed []byte
 		seed, err = p.seeder.Generate(parent.Header.ID())
 		if err != nil {
 			return nil, err
 		}
-		sched, err = poa.NewSchedulerV2(p.nodeMaster, proposers, parent.Header.Number(), parent.Header.Timestamp(), seed)
+		sched, err = poa.NewSchedulerV2(p.nodeMaster, proposers, parent.Header.Number(), parent.Header.Timestamp(), p.config.BlockInterval, seed)
 	}
 	if err != nil {
 		return nil, err
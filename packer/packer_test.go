@@ -85,7 +85,7 @@ func TestP(t *testing.T) {
 
 	for {
 		best := repo.BestBlockSummary()
-		p := packer.New(repo, stater, a1.Address, &a1.Address, ablock.NoFork)
+		p := packer.New(repo, stater, a1.Address, &a1.Address, ablock.NoFork, ablock.DefaultConfig)
 		flow, err := p.Schedule(best, uint64(time.Now().Unix()))
 		if err != nil {
 			t.Fatal(err)
@@ -99,7 +99,7 @@ func TestP(t *testing.T) {
 		blk, stage, receipts, _ := flow.Pack(genesis.DevAccounts()[0].PrivateKey, 0, false)
 		root, _ := stage.Commit()
 		assert.Equal(t, root, blk.Header().StateRoot())
-		_, _, err = consensus.New(repo, stater, ablock.NoFork).Process(best, blk, uint64(time.Now().Unix()*2), 0)
+		_, _, err = consensus.New(repo, stater, ablock.NoFork, ablock.DefaultConfig).Process(best, blk, uint64(time.Now().Unix()*2), 0)
 		assert.Nil(t, err)
 
 		if err := repo.AddBlock(blk, receipts, 0); err != nil {
@@ -152,7 +152,7 @@ func TestForkVIP191(t *testing.T) {
 	fc.VIP191 = 1
 
 	best := repo.BestBlockSummary()
-	p := packer.New(repo, stater, a1.Address, &a1.Address, fc)
+	p := packer.New(repo, stater, a1.Address, &a1.Address, fc, ablock.DefaultConfig)
 	flow, err := p.Schedule(best, uint64(time.Now().Unix()))
 	if err != nil {
 		t.Fatal(err)
@@ -162,7 +162,7 @@ func TestForkVIP191(t *testing.T) {
 	root, _ := stage.Commit()
 	assert.Equal(t, root, blk.Header().StateRoot())
 
-	_, _, err = consensus.New(repo, stater, fc).Process(best, blk, uint64(time.Now().Unix()*2), 0)
+	_, _, err = consensus.New(repo, stater, fc, ablock.DefaultConfig).Process(best, blk, uint64(time.Now().Unix()*2), 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -202,7 +202,7 @@ func TestBlocklist(t *testing.T) {
 	ablock.MockBlocklist([]string{a0.Address.String()})
 
 	best := repo.BestBlockSummary()
-	p := packer.New(repo, stater, a0.Address, &a0.Address, forkConfig)
+	p := packer.New(repo, stater, a0.Address, &a0.Address, forkConfig, ablock.DefaultConfig)
 	flow, err := p.Schedule(best, uint64(time.Now().Unix()))
 	if err != nil {
 		t.Fatal(err)
@@ -226,4 +226,12 @@ func TestBlocklist(t *testing.T) {
 	if err != nil {
 		t.Fatal("adopt tx from non-blocked origin should not return error")
 	}
+	err = flow.Adopt(tx1)
+	assert.True(t, packer.IsKnownTx(err))
+
+	report := flow.Report()
+	assert.Equal(t, 3, report.Candidates)
+	assert.Equal(t, 1, report.Adopted)
+	assert.Equal(t, packer.Rejections{BlockedOrigin: 1, KnownTx: 1}, report.Rejected)
+	assert.True(t, report.GasUsed > 0 && report.GasUsed < report.GasLimit)
 }
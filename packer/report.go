@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// Rejections counts txs rejected by the flow, by reason.
+type Rejections struct {
+	BadTx               int
+	BlockedOrigin       int
+	NotAdoptableNow     int
+	NotAdoptableForever int
+	GasLimitReached     int
+	KnownTx             int
+	Other               int
+}
+
+func (r *Rejections) add(err error) {
+	switch {
+	case IsBlockedOrigin(err):
+		r.BlockedOrigin++
+	case IsBadTx(err):
+		r.BadTx++
+	case IsTxNotAdoptableNow(err):
+		r.NotAdoptableNow++
+	case IsTxNotAdoptableForever(err):
+		r.NotAdoptableForever++
+	case IsGasLimitReached(err):
+		r.GasLimitReached++
+	case IsKnownTx(err):
+		r.KnownTx++
+	default:
+		r.Other++
+	}
+}
+
+// Report is the statistics of packing a block.
+type Report struct {
+	Number     uint32
+	ID         ablock.Bytes32 // zero until the block is packed
+	Timestamp  uint64
+	Candidates int // txs offered to the flow
+	Adopted    int
+	Rejected   Rejections
+	AdoptTime  time.Duration // time spent in adopting txs
+	ExecTime   time.Duration // total time to execute and pack the block, set by the caller
+	GasUsed    uint64
+	GasLimit   uint64
+}
+
+// GasUtilization returns the ratio of gas used to gas limit.
+func (r *Report) GasUtilization() float64 {
+	if r.GasLimit == 0 {
+		return 0
+	}
+	return float64(r.GasUsed) / float64(r.GasLimit)
+}
+
+// LogContext returns the report in key-value pairs for structured logging.
+func (r *Report) LogContext() []interface{} {
+	return []interface{}{
+		"number", r.Number,
+		"candidates", r.Candidates,
+		"adopted", r.Adopted,
+		"badTx", r.Rejected.BadTx,
+		"blockedOrigin", r.Rejected.BlockedOrigin,
+		"notAdoptableNow", r.Rejected.NotAdoptableNow,
+		"notAdoptableForever", r.Rejected.NotAdoptableForever,
+		"gasLimitReached", r.Rejected.GasLimitReached,
+		"knownTx", r.Rejected.KnownTx,
+		"other", r.Rejected.Other,
+		"adoptTime", r.AdoptTime,
+		"execTime", r.ExecTime,
+		"gasUtilization", r.GasUtilization(),
+	}
+}
+
+// Reports keeps reports of recently packed blocks.
+type Reports struct {
+	lock  sync.Mutex
+	size  int
+	items []*Report
+}
+
+// NewReports creates Reports keeps at most size reports.
+func NewReports(size int) *Reports {
+	return &Reports{size: size}
+}
+
+// Add adds the report, and drops the oldest one if full.
+func (rs *Reports) Add(r *Report) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	rs.items = append(rs.items, r)
+	if len(rs.items) > rs.size {
+		rs.items = append([]*Report(nil), rs.items[len(rs.items)-rs.size:]...)
+	}
+}
+
+// Last returns the last n reports, latest first.
+func (rs *Reports) Last(n int) []*Report {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	if n <= 0 || n > len(rs.items) {
+		n = len(rs.items)
+	}
+	list := make([]*Report, 0, n)
+	for i := len(rs.items) - 1; i >= len(rs.items)-n; i-- {
+		r := *rs.items[i]
+		list = append(list, &r)
+	}
+	return list
+}
@@ -35,13 +35,13 @@ var (
 
 func TestSchedule(t *testing.T) {
 
-	_, err := poa.NewSchedulerV1(ablock.BytesToAddress([]byte("px")), proposers, 1, parentTime)
+	_, err := poa.NewSchedulerV1(ablock.BytesToAddress([]byte("px")), proposers, 1, parentTime, ablock.DefaultConfig.BlockInterval)
 	assert.NotNil(t, err)
 
-	sched, _ := poa.NewSchedulerV1(p1, proposers, 1, parentTime)
+	sched, _ := poa.NewSchedulerV1(p1, proposers, 1, parentTime, ablock.DefaultConfig.BlockInterval)
 
 	for i := uint64(0); i < 100; i++ {
-		now := parentTime + i*ablock.BlockInterval/2
+		now := parentTime + i*ablock.DefaultConfig.BlockInterval/2
 		nbt := sched.Schedule(now)
 		assert.True(t, nbt >= now)
 		assert.True(t, sched.IsTheTime(nbt))
@@ -49,15 +49,15 @@ func TestSchedule(t *testing.T) {
 }
 
 func TestIsTheTime(t *testing.T) {
-	sched, _ := poa.NewSchedulerV1(p2, proposers, 1, parentTime)
+	sched, _ := poa.NewSchedulerV1(p2, proposers, 1, parentTime, ablock.DefaultConfig.BlockInterval)
 
 	tests := []struct {
 		now  uint64
 		want bool
 	}{
 		{parentTime - 1, false},
-		{parentTime + ablock.BlockInterval/2, false},
-		{parentTime + ablock.BlockInterval, true},
+		{parentTime + ablock.DefaultConfig.BlockInterval/2, false},
+		{parentTime + ablock.DefaultConfig.BlockInterval, true},
 	}
 
 	for _, tt := range tests {
@@ -66,14 +66,14 @@ func TestIsTheTime(t *testing.T) {
 }
 
 func TestUpdates(t *testing.T) {
-	sched, _ := poa.NewSchedulerV1(p1, proposers, 1, parentTime)
+	sched, _ := poa.NewSchedulerV1(p1, proposers, 1, parentTime, ablock.DefaultConfig.BlockInterval)
 
 	tests := []struct {
 		newBlockTime uint64
 		want         uint64
 	}{
-		{parentTime + ablock.BlockInterval, 2},
-		{parentTime + ablock.BlockInterval*30, 1},
+		{parentTime + ablock.DefaultConfig.BlockInterval, 2},
+		{parentTime + ablock.DefaultConfig.BlockInterval*30, 1},
 	}
 
 	for _, tt := range tests {
@@ -87,13 +87,13 @@ func TestScheduleV2(t *testing.T) {
 	binary.BigEndian.PutUint32(parentID[:], 0)
 	parent := new(block.Builder).ParentID(parentID).Timestamp(parentTime).Build()
 
-	_, err := poa.NewSchedulerV2(ablock.BytesToAddress([]byte("p6")), proposers, parent.Header().Number(), parent.Header().Timestamp(), nil)
+	_, err := poa.NewSchedulerV2(ablock.BytesToAddress([]byte("p6")), proposers, parent.Header().Number(), parent.Header().Timestamp(), ablock.DefaultConfig.BlockInterval, nil)
 	assert.NotNil(t, err)
 
-	sched, _ := poa.NewSchedulerV2(p2, proposers, parent.Header().Number(), parent.Header().Timestamp(), nil)
+	sched, _ := poa.NewSchedulerV2(p2, proposers, parent.Header().Number(), parent.Header().Timestamp(), ablock.DefaultConfig.BlockInterval, nil)
 
 	for i := uint64(0); i < 100; i++ {
-		now := parentTime + i*ablock.BlockInterval/2
+		now := parentTime + i*ablock.DefaultConfig.BlockInterval/2
 		nbt := sched.Schedule(now)
 		assert.True(t, nbt >= now)
 		assert.True(t, sched.IsTheTime(nbt))
@@ -105,7 +105,7 @@ func TestIsTheTimeV2(t *testing.T) {
 	binary.BigEndian.PutUint32(parentID[:], 0)
 	parent := new(block.Builder).ParentID(parentID).Timestamp(parentTime).Build()
 
-	sched, err := poa.NewSchedulerV2(p2, proposers, parent.Header().Number(), parent.Header().Timestamp(), nil)
+	sched, err := poa.NewSchedulerV2(p2, proposers, parent.Header().Number(), parent.Header().Timestamp(), ablock.DefaultConfig.BlockInterval, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -115,8 +115,8 @@ func TestIsTheTimeV2(t *testing.T) {
 		want bool
 	}{
 		{parentTime - 1, false},
-		{parentTime + ablock.BlockInterval/2, false},
-		{parentTime + ablock.BlockInterval, true},
+		{parentTime + ablock.DefaultConfig.BlockInterval/2, false},
+		{parentTime + ablock.DefaultConfig.BlockInterval, true},
 	}
 
 	for _, tt := range tests {
@@ -129,7 +129,7 @@ func TestUpdatesV2(t *testing.T) {
 	binary.BigEndian.PutUint32(parentID[:], 0)
 	parent := new(block.Builder).ParentID(parentID).Timestamp(parentTime).Build()
 
-	sched, err := poa.NewSchedulerV2(p2, proposers, parent.Header().Number(), parent.Header().Timestamp(), nil)
+	sched, err := poa.NewSchedulerV2(p2, proposers, parent.Header().Number(), parent.Header().Timestamp(), ablock.DefaultConfig.BlockInterval, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -138,8 +138,8 @@ func TestUpdatesV2(t *testing.T) {
 		newBlockTime uint64
 		want         uint64
 	}{
-		{parentTime + ablock.BlockInterval*30, 1},
-		{parentTime + ablock.BlockInterval, 1},
+		{parentTime + ablock.DefaultConfig.BlockInterval*30, 1},
+		{parentTime + ablock.DefaultConfig.BlockInterval, 1},
 	}
 
 	for _, tt := range tests {
@@ -153,7 +153,7 @@ func TestActivateInV2(t *testing.T) {
 	binary.BigEndian.PutUint32(parentID[:], 0)
 	parent := new(block.Builder).ParentID(parentID).Timestamp(parentTime).Build()
 
-	sched, err := poa.NewSchedulerV2(p1, proposers, parent.Header().Number(), parent.Header().Timestamp(), nil)
+	sched, err := poa.NewSchedulerV2(p1, proposers, parent.Header().Number(), parent.Header().Timestamp(), ablock.DefaultConfig.BlockInterval, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -162,8 +162,8 @@ func TestActivateInV2(t *testing.T) {
 		newBlockTime uint64
 		want         uint64
 	}{
-		{parentTime + ablock.BlockInterval*30, 1},
-		{parentTime + ablock.BlockInterval, 2},
+		{parentTime + ablock.DefaultConfig.BlockInterval*30, 1},
+		{parentTime + ablock.DefaultConfig.BlockInterval, 2},
 	}
 
 	for _, tt := range tests {
@@ -19,6 +19,7 @@ import (
 type SchedulerV2 struct {
 	proposer        Proposer
 	parentBlockTime uint64
+	blockInterval   uint64
 	shuffled        []ablock.Address
 }
 
@@ -32,6 +33,7 @@ func NewSchedulerV2(
 	proposers []Proposer,
 	parentBlockNumber uint32,
 	parentBlockTime uint64,
+	blockInterval uint64,
 	seed []byte) (*SchedulerV2, error) {
 
 	var (
@@ -77,6 +79,7 @@ func NewSchedulerV2(
 	return &SchedulerV2{
 		proposer,
 		parentBlockTime,
+		blockInterval,
 		shuffled,
 	}, nil
 }
@@ -84,7 +87,7 @@ func NewSchedulerV2(
 // Schedule to determine time of the proposer to produce a block, according to `nowTime`.
 // `newBlockTime` is promised to be >= nowTime and > parentBlockTime
 func (s *SchedulerV2) Schedule(nowTime uint64) (newBlockTime uint64) {
-	const T = ablock.BlockInterval
+	T := s.blockInterval
 
 	newBlockTime = s.parentBlockTime + T
 	if nowTime > newBlockTime {
@@ -116,7 +119,7 @@ func (s *SchedulerV2) IsScheduled(blockTime uint64, proposer ablock.Address) boo
 		return false
 	}
 
-	T := ablock.BlockInterval
+	T := s.blockInterval
 	if (blockTime-s.parentBlockTime)%T != 0 {
 		// invalid block time
 		return false
@@ -128,7 +131,7 @@ func (s *SchedulerV2) IsScheduled(blockTime uint64, proposer ablock.Address) boo
 
 // Updates returns proposers whose status are changed, and the score when new block time is assumed to be newBlockTime.
 func (s *SchedulerV2) Updates(newBlockTime uint64) (updates []Proposer, score uint64) {
-	T := ablock.BlockInterval
+	T := s.blockInterval
 
 	for i := uint64(0); i < uint64(len(s.shuffled)); i++ {
 		if s.parentBlockTime+T+i*T >= newBlockTime {
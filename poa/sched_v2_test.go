@@ -19,7 +19,8 @@ var (
 	p4 = ablock.BytesToAddress([]byte("p4"))
 	p5 = ablock.BytesToAddress([]byte("p5"))
 
-	parentTime = uint64(0)
+	parentTime    = uint64(0)
+	blockInterval = ablock.DefaultConfig.BlockInterval
 )
 
 func TestSchedulerV2_Updates(t *testing.T) {
@@ -74,6 +75,7 @@ func TestSchedulerV2_Updates(t *testing.T) {
 			s := &SchedulerV2{
 				proposer:        tt.fields.proposer,
 				parentBlockTime: tt.fields.parentBlockTime,
+				blockInterval:   blockInterval,
 				shuffled:        tt.fields.shuffled,
 			}
 			gotUpdates, gotScore := s.Updates(tt.args.newBlockTime)
@@ -140,7 +142,7 @@ func TestNewSchedulerV2(t *testing.T) {
 				parentTime,
 				seed,
 			},
-			&SchedulerV2{Proposer{p1, true}, parentTime, []ablock.Address{p1, p4, p3, p2, p5}},
+			&SchedulerV2{Proposer{p1, true}, parentTime, blockInterval, []ablock.Address{p1, p4, p3, p2, p5}},
 			false,
 		},
 		{
@@ -152,7 +154,7 @@ func TestNewSchedulerV2(t *testing.T) {
 				parentTime,
 				seed,
 			},
-			&SchedulerV2{Proposer{p1, false}, parentTime, []ablock.Address{p1, p4, p3, p2, p5}},
+			&SchedulerV2{Proposer{p1, false}, parentTime, blockInterval, []ablock.Address{p1, p4, p3, p2, p5}},
 			false,
 		},
 		{
@@ -170,7 +172,7 @@ func TestNewSchedulerV2(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewSchedulerV2(tt.args.addr, tt.args.proposers, tt.args.parentBlockNumber, tt.args.parentBlockTime, tt.args.seed)
+			got, err := NewSchedulerV2(tt.args.addr, tt.args.proposers, tt.args.parentBlockNumber, tt.args.parentBlockTime, blockInterval, tt.args.seed)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewSchedulerV2() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -223,6 +225,7 @@ func TestSchedulerV2_Schedule(t *testing.T) {
 			s := &SchedulerV2{
 				proposer:        tt.fields.proposer,
 				parentBlockTime: tt.fields.parentBlockTime,
+				blockInterval:   blockInterval,
 				shuffled:        tt.fields.shuffled,
 			}
 			if gotNewBlockTime := s.Schedule(tt.args.nowTime); gotNewBlockTime != tt.wantNewBlockTime {
@@ -0,0 +1,41 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package proof verifies merkle proofs returned by untrusted nodes against trusted block headers.
+package proof
+
+import (
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/trie"
+)
+
+var errNodeNotFound = errors.New("not found")
+
+// nodes is the set of proof nodes, keyed by node hash.
+type nodes map[ablock.Bytes32][]byte
+
+func newNodes(proof [][]byte) nodes {
+	ns := make(nodes, len(proof))
+	for _, n := range proof {
+		ns[ablock.Blake2b(n)] = n
+	}
+	return ns
+}
+
+// Get implements trie.DatabaseReader.
+func (ns nodes) Get(key []byte) ([]byte, error) {
+	if n, ok := ns[ablock.BytesToBytes32(key)]; ok {
+		return n, nil
+	}
+	return nil, errNodeNotFound
+}
+
+// verify verifies the proof of key against the root, and returns the value.
+// Nil value returned if the proof proves the absence of the key.
+func verify(root ablock.Bytes32, key []byte, proof [][]byte) ([]byte, error) {
+	value, err, _ := trie.VerifyProof(root, key, newNodes(proof))
+	return value, err
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/trie"
+)
+
+// VerifyAccountRange verifies the range of accounts against the state root of the header.
+// It ensures entries are exactly all leaves of the account trie from the start key to the last entry,
+// and returns whether there are more accounts after the range.
+func VerifyAccountRange(header *block.Header, start []byte, r *state.Range) (bool, error) {
+	more, err := verifyRange(header.StateRoot(), start, r)
+	if err != nil {
+		return false, errors.WithMessage(err, "account range")
+	}
+	return more, nil
+}
+
+// VerifyStorageRange verifies the range of storage entries against the storage root of the account,
+// as well as the key preimages of entries. It returns whether there are more entries after the range.
+func VerifyStorageRange(acc *state.Account, start []byte, r *state.Range) (bool, error) {
+	if len(acc.StorageRoot) == 0 {
+		return false, errors.New("storage range: account has no storage")
+	}
+	more, err := verifyRange(ablock.BytesToBytes32(acc.StorageRoot), start, r)
+	if err != nil {
+		return false, errors.WithMessage(err, "storage range")
+	}
+	for _, e := range r.Entries {
+		if len(e.Extra) > 32 {
+			return false, errors.New("storage range: invalid key preimage")
+		}
+		if hashedKey := ablock.Blake2b(ablock.BytesToBytes32(e.Extra).Bytes()); !bytes.Equal(hashedKey[:], e.Key) {
+			return false, errors.New("storage range: key preimage mismatch")
+		}
+	}
+	return more, nil
+}
+
+// verifyRange rebuilds the sub-trie from entries and the edge proofs, and checks it against the root.
+func verifyRange(root ablock.Bytes32, start []byte, r *state.Range) (bool, error) {
+	keys := make([][]byte, 0, len(r.Entries))
+	values := make([][]byte, 0, len(r.Entries))
+	for _, e := range r.Entries {
+		keys = append(keys, e.Key)
+		values = append(values, e.Value)
+	}
+	return trie.VerifyRangeProof(root, start, keys, values, newNodes(r.Proof))
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/proof"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+func TestRangeProof(t *testing.T) {
+	db := muxdb.NewMem()
+	st := state.New(db, ablock.Bytes32{}, 0, 0, 0)
+
+	contract := ablock.BytesToAddress([]byte("contract"))
+	for i := 0; i < 100; i++ {
+		st.SetBalance(ablock.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+	}
+	st.SetCode(contract, []byte{1, 2, 3})
+	for i := 0; i < 100; i++ {
+		st.SetStorage(contract, ablock.BytesToBytes32([]byte{byte(i)}), ablock.BytesToBytes32([]byte{byte(i + 1)}))
+	}
+	stage, err := st.Stage(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := stage.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := new(block.Builder).StateRoot(root).Build().Header()
+	stater := state.NewStater(db)
+
+	// walk through all accounts page by page
+	var (
+		start = make([]byte, 32)
+		count = 0
+	)
+	for {
+		r, err := stater.AccountRange(root, 1, 0, start, 30, 1024*1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		more, err := proof.VerifyAccountRange(header, start, r)
+		assert.Nil(t, err)
+		count += len(r.Entries)
+		if !more {
+			break
+		}
+		start = incKey(r.Entries[len(r.Entries)-1].Key)
+	}
+	assert.Equal(t, 101, count)
+
+	// beyond the last account
+	last := bytes.Repeat([]byte{0xff}, 32)
+	r, _ := stater.AccountRange(root, 1, 0, last, 30, 1024*1024)
+	more, err := proof.VerifyAccountRange(header, last, r)
+	assert.Nil(t, err)
+	assert.False(t, more)
+	assert.Equal(t, 0, len(r.Entries))
+
+	// omitted leaf
+	r, _ = stater.AccountRange(root, 1, 0, make([]byte, 32), 10, 1024*1024)
+	r.Entries = append(r.Entries[:3], r.Entries[4:]...)
+	_, err = proof.VerifyAccountRange(header, make([]byte, 32), r)
+	assert.NotNil(t, err)
+
+	// omitted all leaves
+	r, _ = stater.AccountRange(root, 1, 0, make([]byte, 32), 10, 1024*1024)
+	r.Entries = nil
+	_, err = proof.VerifyAccountRange(header, make([]byte, 32), r)
+	assert.NotNil(t, err)
+
+	// tampered value
+	r, _ = stater.AccountRange(root, 1, 0, make([]byte, 32), 10, 1024*1024)
+	r.Entries[3].Value = append([]byte(nil), r.Entries[3].Value...)
+	r.Entries[3].Value[0]++
+	_, err = proof.VerifyAccountRange(header, make([]byte, 32), r)
+	assert.NotNil(t, err)
+
+	// out of order
+	r, _ = stater.AccountRange(root, 1, 0, make([]byte, 32), 10, 1024*1024)
+	r.Entries[3], r.Entries[4] = r.Entries[4], r.Entries[3]
+	_, err = proof.VerifyAccountRange(header, make([]byte, 32), r)
+	assert.NotNil(t, err)
+
+	// storage
+	hashedKey := ablock.Blake2b(contract[:])
+	r, err = stater.StorageRange(root, 1, 0, hashedKey[:], make([]byte, 32), 1000, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 100, len(r.Entries))
+	p, err := state.New(db, root, 1, 0, 0).ProveAccount(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err := proof.VerifyAccount(header, contract, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	more, err = proof.VerifyStorageRange(acc, make([]byte, 32), r)
+	assert.Nil(t, err)
+	assert.False(t, more)
+
+	// forged key preimage
+	r.Entries[0].Extra = []byte{200}
+	_, err = proof.VerifyStorageRange(acc, make([]byte, 32), r)
+	assert.NotNil(t, err)
+
+	// account without storage
+	hashedKey = ablock.Blake2b(ablock.BytesToAddress([]byte{1}).Bytes())
+	r, err = stater.StorageRange(root, 1, 0, hashedKey[:], make([]byte, 32), 1000, 1024*1024)
+	assert.Nil(t, err)
+	assert.Nil(t, r)
+}
+
+func incKey(key []byte) []byte {
+	key = append([]byte(nil), key...)
+	for i := len(key) - 1; i >= 0; i-- {
+		key[i]++
+		if key[i] != 0 {
+			break
+		}
+	}
+	return key
+}
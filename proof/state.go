@@ -0,0 +1,62 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// VerifyAccount verifies the account proof against the state root of the header,
+// and returns the proved account. An empty account is returned if the proof
+// proves the absence of the account.
+func VerifyAccount(header *block.Header, addr ablock.Address, proof [][]byte) (*state.Account, error) {
+	hashedKey := ablock.Blake2b(addr[:])
+	data, err := verify(header.StateRoot(), hashedKey[:], proof)
+	if err != nil {
+		return nil, errors.WithMessage(err, "account proof")
+	}
+	if len(data) == 0 {
+		return &state.Account{Balance: &big.Int{}, Energy: &big.Int{}}, nil
+	}
+	var acc state.Account
+	if err := rlp.DecodeBytes(data, &acc); err != nil {
+		return nil, errors.WithMessage(err, "decode account")
+	}
+	return &acc, nil
+}
+
+// VerifyStorage verifies the storage proof against the storage root of the proved account,
+// and returns the proved storage value. Zero value is returned if the proof proves
+// the absence of the key.
+func VerifyStorage(acc *state.Account, key ablock.Bytes32, proof [][]byte) (ablock.Bytes32, error) {
+	if len(acc.StorageRoot) == 0 {
+		// empty storage
+		return ablock.Bytes32{}, nil
+	}
+	hashedKey := ablock.Blake2b(key[:])
+	raw, err := verify(ablock.BytesToBytes32(acc.StorageRoot), hashedKey[:], proof)
+	if err != nil {
+		return ablock.Bytes32{}, errors.WithMessage(err, "storage proof")
+	}
+	if len(raw) == 0 {
+		return ablock.Bytes32{}, nil
+	}
+	// decoded in the same way as state.GetStorage
+	kind, content, _, err := rlp.Split(raw)
+	if err != nil {
+		return ablock.Bytes32{}, errors.WithMessage(err, "decode storage")
+	}
+	if kind == rlp.List {
+		return ablock.Blake2b(raw), nil
+	}
+	return ablock.BytesToBytes32(content), nil
+}
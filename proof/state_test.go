@@ -0,0 +1,109 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/proof"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+func TestStateProof(t *testing.T) {
+	db := muxdb.NewMem()
+	st := state.New(db, ablock.Bytes32{}, 0, 0, 0)
+
+	contract := ablock.BytesToAddress([]byte("contract"))
+	for i := 0; i < 100; i++ {
+		st.SetBalance(ablock.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+	}
+	st.SetCode(contract, []byte{1, 2, 3})
+	for i := 0; i < 100; i++ {
+		st.SetStorage(contract, ablock.BytesToBytes32([]byte{byte(i)}), ablock.BytesToBytes32([]byte{byte(i + 1)}))
+	}
+	stage, err := st.Stage(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := stage.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := new(block.Builder).StateRoot(root).Build().Header()
+	st = state.New(db, root, 1, 0, 0)
+
+	// existent account
+	addr := ablock.BytesToAddress([]byte{10})
+	p, err := st.ProveAccount(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err := proof.VerifyAccount(header, addr, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(11), acc.Balance)
+
+	// tampered proof
+	tampered := append([][]byte(nil), p...)
+	tampered[len(tampered)-1] = append([]byte(nil), tampered[len(tampered)-1]...)
+	tampered[len(tampered)-1][len(tampered[len(tampered)-1])-1]++
+	_, err = proof.VerifyAccount(header, addr, tampered)
+	assert.NotNil(t, err)
+
+	// proof of other account
+	_, err = proof.VerifyAccount(header, ablock.BytesToAddress([]byte{11}), p)
+	assert.NotNil(t, err)
+
+	// absent account
+	absent := ablock.BytesToAddress([]byte("absent"))
+	p, err = st.ProveAccount(absent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err = proof.VerifyAccount(header, absent, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, acc.IsEmpty())
+
+	// storage
+	p, err = st.ProveAccount(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err = proof.VerifyAccount(header, contract, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []byte{0, 50, 99, 200} {
+		key := ablock.BytesToBytes32([]byte{k})
+		p, err := st.ProveStorage(contract, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := proof.VerifyStorage(acc, key, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := st.GetStorage(contract, key)
+		assert.Equal(t, expected, value)
+	}
+
+	// missing proof
+	_, err = proof.VerifyAccount(header, addr, nil)
+	assert.NotNil(t, err)
+
+	// account without storage
+	value, err := proof.VerifyStorage(&state.Account{}, ablock.Bytes32{}, nil)
+	assert.Nil(t, err)
+	assert.True(t, value.IsZero())
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+var errNotIncluded = errors.New("not included")
+
+// verifyIndex verifies the proof of the item at index, and returns the RLP encoded item.
+func verifyIndex(root ablock.Bytes32, index uint64, proof [][]byte) ([]byte, error) {
+	key, _ := rlp.EncodeToBytes(uint(index))
+	data, err := verify(root, key, proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errNotIncluded
+	}
+	return data, nil
+}
+
+// VerifyTx verifies the inclusion proof of the tx at index against the txs root of the header,
+// and returns the proved tx.
+func VerifyTx(header *block.Header, index uint64, proof [][]byte) (*tx.Transaction, error) {
+	data, err := verifyIndex(header.TxsRoot(), index, proof)
+	if err != nil {
+		return nil, errors.WithMessage(err, "tx proof")
+	}
+	var trx *tx.Transaction
+	if err := rlp.DecodeBytes(data, &trx); err != nil {
+		return nil, errors.WithMessage(err, "decode tx")
+	}
+	return trx, nil
+}
+
+// VerifyReceipt verifies the inclusion proof of the receipt at index against the receipts root
+// of the header, and returns the proved receipt.
+func VerifyReceipt(header *block.Header, index uint64, proof [][]byte) (*tx.Receipt, error) {
+	data, err := verifyIndex(header.ReceiptsRoot(), index, proof)
+	if err != nil {
+		return nil, errors.WithMessage(err, "receipt proof")
+	}
+	var receipt tx.Receipt
+	if err := rlp.DecodeBytes(data, &receipt); err != nil {
+		return nil, errors.WithMessage(err, "decode receipt")
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/proof"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+func TestTxProof(t *testing.T) {
+	var (
+		txs      tx.Transactions
+		receipts tx.Receipts
+	)
+	builder := new(block.Builder)
+	for i := 0; i < 200; i++ {
+		trx := new(tx.Builder).Nonce(uint64(i)).Build()
+		txs = append(txs, trx)
+		receipts = append(receipts, &tx.Receipt{GasUsed: uint64(i)})
+		builder.Transaction(trx)
+	}
+	header := builder.ReceiptsRoot(receipts.RootHash()).Build().Header()
+
+	for _, i := range []int{0, 1, 127, 128, 199} {
+		txProof, err := txs.Prove(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trx, err := proof.VerifyTx(header, uint64(i), txProof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, txs[i].ID(), trx.ID())
+
+		receiptProof, err := receipts.Prove(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		receipt, err := proof.VerifyReceipt(header, uint64(i), receiptProof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, uint64(i), receipt.GasUsed)
+	}
+
+	txProof, _ := txs.Prove(1)
+	receiptProof, _ := receipts.Prove(1)
+
+	// proof of another index
+	_, err := proof.VerifyTx(header, 150, txProof)
+	assert.NotNil(t, err)
+
+	// index out of range
+	outProof, err := txs.Prove(200)
+	assert.Nil(t, err)
+	_, err = proof.VerifyTx(header, 200, outProof)
+	assert.NotNil(t, err)
+
+	// receipt proof against txs root
+	_, err = proof.VerifyTx(header, 1, receiptProof)
+	assert.NotNil(t, err)
+}
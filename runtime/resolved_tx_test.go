@@ -134,7 +134,7 @@ func (tr *testResolvedTransaction) TestBuyGas() {
 		return txBuilder(tr.repo.ChainTag())
 	}
 
-	targetTime := tr.repo.BestBlockSummary().Header.Timestamp() + ablock.BlockInterval
+	targetTime := tr.repo.BestBlockSummary().Header.Timestamp() + ablock.DefaultConfig.BlockInterval
 
 	buyGas := func(tx *tx.Transaction) ablock.Address {
 		resolve, err := runtime.ResolveTransaction(tx)
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+const (
+	unixScheme    = "unix://"
+	clientTimeout = 5 * time.Second
+)
+
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	addr    ablock.Address
+}
+
+// NewClient creates a client of the remote signer at the given URL, which is
+// either 'http://host:port' or 'unix:///path/to/socket'. The token, if not empty,
+// is sent as the bearer token.
+func NewClient(url string, token string) (Signer, error) {
+	c := &client{
+		baseURL: strings.TrimSuffix(url, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: clientTimeout},
+	}
+	if strings.HasPrefix(url, unixScheme) {
+		path := strings.TrimPrefix(url, unixScheme)
+		c.baseURL = "http://signer"
+		c.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+	}
+
+	if err := c.do(http.MethodGet, "/address", nil, &c.addr); err != nil {
+		return nil, errors.WithMessage(err, "get signer address")
+	}
+	return c, nil
+}
+
+func (c *client) do(method, path string, body interface{}, result interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = data
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("signer: %v %v", res.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (c *client) Address() ablock.Address {
+	return c.addr
+}
+
+func (c *client) Sign(req *Request) (*Signature, error) {
+	var sig Signature
+	if err := c.do(http.MethodPost, "/sign", req, &sig); err != nil {
+		return nil, err
+	}
+	return &sig, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// number of recent blocks kept for double-sign checking.
+const historySize = 1024
+
+type signedRecord struct {
+	Number      uint32         `json:"number"`
+	Timestamp   uint64         `json:"timestamp"`
+	SigningHash ablock.Bytes32 `json:"signingHash"`
+}
+
+type protectionState struct {
+	// blocks at or below the watermark are always refused, since their records were dropped.
+	Watermark uint32          `json:"watermark"`
+	Records   []*signedRecord `json:"records"`
+}
+
+// ErrDoubleSign is returned when signing another header at the height or slot already signed.
+type ErrDoubleSign struct {
+	Number    uint32
+	Timestamp uint64
+}
+
+func (e *ErrDoubleSign) Error() string {
+	return fmt.Sprintf("double sign refused: #%v @%v", e.Number, e.Timestamp)
+}
+
+// IsDoubleSign returns whether the error is caused by double-sign protection.
+func IsDoubleSign(err error) bool {
+	_, ok := err.(*ErrDoubleSign)
+	return ok
+}
+
+type protected struct {
+	Signer
+	path  string
+	lock  sync.Mutex
+	state protectionState
+}
+
+// NewProtected wraps the signer with double-sign protection, which refuses to sign
+// two different headers at the same height or the same slot.
+// The signed records are persisted in the file at path, if path is not empty.
+func NewProtected(s Signer, path string) (Signer, error) {
+	p := &protected{Signer: s, path: path}
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else if err := json.Unmarshal(data, &p.state); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *protected) Sign(req *Request) (*Signature, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if req.Number <= p.state.Watermark && p.state.Watermark > 0 {
+		return nil, &ErrDoubleSign{req.Number, req.Timestamp}
+	}
+	resign := false
+	for _, r := range p.state.Records {
+		if r.Number == req.Number || r.Timestamp == req.Timestamp {
+			if r.SigningHash != req.SigningHash {
+				return nil, &ErrDoubleSign{req.Number, req.Timestamp}
+			}
+			resign = true
+		}
+	}
+	if !resign {
+		// save before signing, so that nothing signed is left unrecorded
+		if err := p.record(&signedRecord{req.Number, req.Timestamp, req.SigningHash}); err != nil {
+			return nil, err
+		}
+	}
+	return p.Signer.Sign(req)
+}
+
+func (p *protected) record(r *signedRecord) error {
+	state := protectionState{
+		Watermark: p.state.Watermark,
+		Records:   append(p.state.Records, r),
+	}
+	// drop records too old
+	var max uint32
+	for _, r := range state.Records {
+		if r.Number > max {
+			max = r.Number
+		}
+	}
+	if max > historySize {
+		kept := state.Records[:0:0]
+		for _, r := range state.Records {
+			if r.Number+historySize > max {
+				kept = append(kept, r)
+			} else if r.Number > state.Watermark {
+				state.Watermark = r.Number
+			}
+		}
+		state.Records = kept
+	}
+
+	if p.path != "" {
+		data, err := json.Marshal(&state)
+		if err != nil {
+			return err
+		}
+		tmp := p.path + ".tmp"
+		if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, p.path); err != nil {
+			return err
+		}
+	}
+	p.state = state
+	return nil
+}
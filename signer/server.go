@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+)
+
+var log = log15.New("pkg", "signer")
+
+// NewHandler creates the http handler to serve the signer.
+// If token is not empty, requests must carry it as the bearer token.
+//
+//	GET  /address  returns the address of the signer
+//	POST /sign     signs the request
+func NewHandler(s Signer, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/address", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		addr := s.Address()
+		writeJSON(w, &addr)
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var r Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 64*1024)).Decode(&r); err != nil {
+			http.Error(w, "body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		sig, err := s.Sign(&r)
+		if err != nil {
+			if IsDoubleSign(err) {
+				log.Warn("refused to sign", "err", err)
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			log.Error("failed to sign", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Info("signed", "number", r.Number, "timestamp", r.Timestamp, "hash", r.SigningHash)
+		writeJSON(w, sig)
+	})
+	if token == "" {
+		return mux
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, req)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(obj)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package signer abstracts the signing of block proposals, so that the master key
+// can be kept out of the node process.
+package signer
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/vrf"
+)
+
+// Request is the request to sign a block header.
+type Request struct {
+	Number      uint32         `json:"number"`
+	Timestamp   uint64         `json:"timestamp"`
+	SigningHash ablock.Bytes32 `json:"signingHash"`
+	// Alpha is the input of VRF, which is only present since VIP214.
+	Alpha hexutil.Bytes `json:"alpha,omitempty"`
+}
+
+// Signature is the result of signing.
+type Signature struct {
+	// Signature the secp256k1 signature of the signing hash.
+	Signature hexutil.Bytes `json:"signature"`
+	// Proof the VRF proof of alpha, empty if alpha not given.
+	Proof hexutil.Bytes `json:"proof,omitempty"`
+}
+
+// Signer signs block headers on behalf of the node master.
+type Signer interface {
+	// Address returns the address of the master.
+	Address() ablock.Address
+	// Sign signs the request.
+	Sign(req *Request) (*Signature, error)
+}
+
+type keySigner struct {
+	key  *ecdsa.PrivateKey
+	addr ablock.Address
+}
+
+// NewKeySigner creates a signer holds the private key in memory.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{
+		key,
+		ablock.Address(crypto.PubkeyToAddress(key.PublicKey)),
+	}
+}
+
+func (s *keySigner) Address() ablock.Address {
+	return s.addr
+}
+
+func (s *keySigner) Sign(req *Request) (*Signature, error) {
+	sig, err := crypto.Sign(req.SigningHash.Bytes(), s.key)
+	if err != nil {
+		return nil, err
+	}
+	var proof []byte
+	if len(req.Alpha) > 0 {
+		if _, proof, err = vrf.Prove(s.key, req.Alpha); err != nil {
+			return nil, err
+		}
+	}
+	return &Signature{sig, proof}, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package signer_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/signer"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/vrf"
+)
+
+func TestKeySigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	s := signer.NewKeySigner(key)
+	assert.Equal(t, ablock.Address(crypto.PubkeyToAddress(key.PublicKey)), s.Address())
+
+	hash := ablock.Blake2b([]byte("header"))
+	sig, err := s.Sign(&signer.Request{Number: 1, Timestamp: 10, SigningHash: hash})
+	assert.Nil(t, err)
+	pub, err := crypto.SigToPub(hash.Bytes(), sig.Signature)
+	assert.Nil(t, err)
+	assert.Equal(t, key.PublicKey, *pub)
+	assert.Empty(t, sig.Proof)
+
+	alpha := []byte("alpha")
+	sig, err = s.Sign(&signer.Request{Number: 1, Timestamp: 10, SigningHash: hash, Alpha: alpha})
+	assert.Nil(t, err)
+	_, err = vrf.Verify(&key.PublicKey, alpha, sig.Proof)
+	assert.Nil(t, err)
+}
+
+func TestProtected(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state")
+
+	key, _ := crypto.GenerateKey()
+	s, err := signer.NewProtected(signer.NewKeySigner(key), path)
+	assert.Nil(t, err)
+
+	h1, h2 := ablock.Blake2b([]byte("h1")), ablock.Blake2b([]byte("h2"))
+
+	_, err = s.Sign(&signer.Request{Number: 1, Timestamp: 10, SigningHash: h1})
+	assert.Nil(t, err)
+	_, err = s.Sign(&signer.Request{Number: 1, Timestamp: 10, SigningHash: h1})
+	assert.Nil(t, err, "signing the same header again is allowed")
+
+	_, err = s.Sign(&signer.Request{Number: 1, Timestamp: 20, SigningHash: h2})
+	assert.True(t, signer.IsDoubleSign(err), "same height")
+	_, err = s.Sign(&signer.Request{Number: 2, Timestamp: 10, SigningHash: h2})
+	assert.True(t, signer.IsDoubleSign(err), "same slot")
+	_, err = s.Sign(&signer.Request{Number: 2, Timestamp: 20, SigningHash: h2})
+	assert.Nil(t, err)
+
+	// records survive restarts
+	s, err = signer.NewProtected(signer.NewKeySigner(key), path)
+	assert.Nil(t, err)
+	_, err = s.Sign(&signer.Request{Number: 2, Timestamp: 30, SigningHash: h1})
+	assert.True(t, signer.IsDoubleSign(err))
+
+	// old records are dropped, and the heights are refused
+	_, err = s.Sign(&signer.Request{Number: 2000, Timestamp: 20000, SigningHash: h1})
+	assert.Nil(t, err)
+	_, err = s.Sign(&signer.Request{Number: 1, Timestamp: 40, SigningHash: h2})
+	assert.True(t, signer.IsDoubleSign(err))
+}
+
+func TestClient(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	s, _ := signer.NewProtected(signer.NewKeySigner(key), "")
+
+	ts := httptest.NewServer(signer.NewHandler(s, "secret"))
+	defer ts.Close()
+
+	_, err := signer.NewClient(ts.URL, "")
+	assert.NotNil(t, err, "token required")
+	_, err = signer.NewClient(ts.URL, "wrong")
+	assert.NotNil(t, err, "token mismatch")
+
+	c, err := signer.NewClient(ts.URL, "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, s.Address(), c.Address())
+
+	hash := ablock.Blake2b([]byte("header"))
+	req := &signer.Request{Number: 1, Timestamp: 10, SigningHash: hash, Alpha: []byte("alpha")}
+	sig, err := c.Sign(req)
+	assert.Nil(t, err)
+	expected, _ := signer.NewKeySigner(key).Sign(req)
+	assert.Equal(t, expected, sig)
+
+	_, err = c.Sign(&signer.Request{Number: 1, Timestamp: 20, SigningHash: ablock.Blake2b([]byte("other"))})
+	assert.NotNil(t, err)
+
+	// over unix socket
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "signer.sock")
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: signer.NewHandler(s, "")}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	c, err = signer.NewClient("unix://"+sock, "")
+	assert.Nil(t, err)
+	assert.Equal(t, s.Address(), c.Address())
+	sig, err = c.Sign(req)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, sig)
+
+	_, err = signer.NewClient("unix://"+filepath.Join(dir, "none.sock"), "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// AccountReader reads an account at states of blocks on one chain.
+// The leaf record of the account is looked up once and shared among reads, so that states within the range
+// the account stays unchanged are resolved without trie access.
+type AccountReader struct {
+	db        *muxdb.MuxDB
+	addr      ablock.Address
+	hashedKey ablock.Bytes32
+	rec       *muxdb.LeafRecord
+
+	last     *Account
+	from, to uint32 // the block number range the last account stays unchanged
+}
+
+// NewAccountReader creates an account reader for the address.
+func (s *Stater) NewAccountReader(addr ablock.Address) *AccountReader {
+	return &AccountReader{
+		db:        s.db,
+		addr:      addr,
+		hashedKey: ablock.Blake2b(addr[:]),
+	}
+}
+
+// Get returns the account at the state of the block. The returned account should not be modified.
+func (r *AccountReader) Get(root ablock.Bytes32, blockNum, blockConflicts, steadyBlockNum uint32) (*Account, error) {
+	if r.last != nil && blockNum >= r.from && blockNum <= r.to {
+		return r.last, nil
+	}
+
+	trie := r.db.NewTrie(AccountTrieName, root, blockNum, blockConflicts)
+	if r.rec == nil {
+		rec, err := trie.LookupLeaf(r.hashedKey[:])
+		if err != nil {
+			return nil, err
+		}
+		r.rec = rec
+	}
+
+	// the leaf is valid within [CommitNum, SlotCommitNum] of the steady chain
+	to := r.rec.SlotCommitNum
+	if to > steadyBlockNum {
+		to = steadyBlockNum
+	}
+	if r.rec.Leaf != nil && blockNum >= r.rec.CommitNum && blockNum <= to {
+		acc := emptyAccount()
+		if len(r.rec.Value) > 0 {
+			if err := rlp.DecodeBytes(r.rec.Value, acc); err != nil {
+				return nil, err
+			}
+		}
+		r.last, r.from, r.to = acc, r.rec.CommitNum, to
+		return acc, nil
+	}
+
+	acc, _, err := loadAccount(trie, r.addr, steadyBlockNum)
+	if err != nil {
+		return nil, err
+	}
+	r.last, r.from, r.to = acc, blockNum, blockNum
+	return acc, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/trie"
+)
+
+func TestAccountReader(t *testing.T) {
+	db, err := muxdb.Open(t.TempDir(), &muxdb.Options{
+		TrieNodeCacheSizeMB:        1,
+		TrieRootCacheCapacity:      1,
+		TrieCachedNodeTTL:          1,
+		TrieLeafBankSlotCapacity:   1,
+		TrieHistPartitionFactor:    1000,
+		TrieDedupedPartitionFactor: 1000,
+		OpenFilesCacheCapacity:     100,
+		ReadCacheMB:                8,
+		WriteBufferMB:              4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		stater = NewStater(db)
+		addr   = ablock.BytesToAddress([]byte("account"))
+		other  = ablock.BytesToAddress([]byte("other"))
+		roots  = []ablock.Bytes32{{}}
+	)
+	// balance of the account changes at block 1 and 4, while the other account changes at every block
+	for n := uint32(1); n <= 6; n++ {
+		st := stater.NewState(roots[n-1], n-1, 0, 0)
+		switch n {
+		case 1:
+			st.SetBalance(addr, big.NewInt(1))
+		case 4:
+			st.SetBalance(addr, big.NewInt(2))
+		}
+		st.SetBalance(other, big.NewInt(int64(n)))
+		stage, err := st.Stage(n, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := stage.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+
+	verify := func(t *testing.T, steadyNum uint32) {
+		reader := stater.NewAccountReader(addr)
+		for n := uint32(0); n <= 6; n++ {
+			acc, err := reader.Get(roots[n], n, 0, steadyNum)
+			assert.Nil(t, err)
+			want, _ := stater.NewState(roots[n], n, 0, steadyNum).GetBalance(addr)
+			assert.Equal(t, want, acc.Balance, n)
+		}
+	}
+
+	t.Run("trie", func(t *testing.T) {
+		verify(t, 0)
+	})
+
+	tr := db.NewTrie(AccountTrieName, roots[6], 6, 0)
+	if err := tr.DumpLeaves(context.Background(), 0, 6, func(leaf *trie.Leaf) *trie.Leaf { return leaf }); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("leaf bank", func(t *testing.T) {
+		verify(t, 6)
+
+		// the account is unchanged since block 4, so the later block is resolved without the trie
+		reader := stater.NewAccountReader(addr)
+		acc, err := reader.Get(roots[4], 4, 0, 6)
+		assert.Nil(t, err)
+		assert.Equal(t, big.NewInt(2), acc.Balance)
+		acc, err = reader.Get(ablock.BytesToBytes32([]byte("missing root")), 6, 1, 6)
+		assert.Nil(t, err)
+		assert.Equal(t, big.NewInt(2), acc.Balance)
+
+		// beyond the steady block, the trie is read
+		_, err = stater.NewAccountReader(addr).Get(ablock.BytesToBytes32([]byte("missing root")), 6, 1, 5)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// ProveAccount returns the merkle proof of the account against the state root.
+// Changes not committed are not reflected.
+func (s *State) ProveAccount(addr ablock.Address) ([][]byte, error) {
+	hashedKey := ablock.Blake2b(addr[:])
+	proof, err := s.trie.Prove(hashedKey[:])
+	if err != nil {
+		return nil, &Error{err}
+	}
+	return proof, nil
+}
+
+// ProveStorage returns the merkle proof of the storage value against the storage root of the account.
+// Changes not committed are not reflected. An empty proof is returned if the account has no storage.
+func (s *State) ProveStorage(addr ablock.Address, key ablock.Bytes32) ([][]byte, error) {
+	obj, err := s.getCachedObject(addr)
+	if err != nil {
+		return nil, &Error{err}
+	}
+	trie := obj.getOrCreateStorageTrie()
+	if trie == nil {
+		return nil, nil
+	}
+	hashedKey := ablock.Blake2b(key[:])
+	proof, err := trie.Prove(hashedKey[:])
+	if err != nil {
+		return nil, &Error{err}
+	}
+	return proof, nil
+}
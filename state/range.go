@@ -0,0 +1,127 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// RangeEntry is a leaf of the account trie or a storage trie.
+type RangeEntry struct {
+	Key   []byte // the hashed key
+	Value []byte
+	Extra []byte // key preimage of storage entry, empty for account
+}
+
+// Range is a continuous range of trie leaves starting from a given key, along with the
+// merkle proofs of the start key and the last leaf, which are the edges of the range.
+type Range struct {
+	Entries []*RangeEntry
+	Proof   [][]byte
+}
+
+// AccountRange returns at most limit accounts whose hashed keys are not less than start,
+// from the state at the given root. The returned range is also bounded by maxSize in bytes.
+func (s *Stater) AccountRange(root ablock.Bytes32, blockNum, blockConflicts uint32, start []byte, limit int, maxSize int) (*Range, error) {
+	accTrie := s.db.NewTrie(AccountTrieName, root, blockNum, blockConflicts)
+	accTrie.SetNoFillCache(true)
+	return dumpRange(accTrie, start, limit, maxSize, false)
+}
+
+// StorageRange returns at most limit storage entries whose hashed keys are not less than start,
+// of the account with the given hashed key. Nil range returned if the account has no storage.
+func (s *Stater) StorageRange(root ablock.Bytes32, blockNum, blockConflicts uint32, accountKey []byte, start []byte, limit int, maxSize int) (*Range, error) {
+	accTrie := s.db.NewTrie(AccountTrieName, root, blockNum, blockConflicts)
+	accTrie.SetNoFillCache(true)
+
+	data, meta, err := accTrie.Get(accountKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(meta) == 0 {
+		return nil, nil
+	}
+	var acc Account
+	if err := rlp.DecodeBytes(data, &acc); err != nil {
+		return nil, errors.Wrap(err, "decode account")
+	}
+	if len(acc.StorageRoot) == 0 {
+		return nil, nil
+	}
+	var am AccountMetadata
+	if err := rlp.DecodeBytes(meta, &am); err != nil {
+		return nil, errors.Wrap(err, "decode account metadata")
+	}
+
+	sTrie := s.db.NewTrie(
+		StorageTrieName(am.StorageID),
+		ablock.BytesToBytes32(acc.StorageRoot),
+		am.StorageCommitNum,
+		am.StorageDistinctNum)
+	sTrie.SetNoFillCache(true)
+	return dumpRange(sTrie, start, limit, maxSize, true)
+}
+
+// GetCode returns the code with the given code hash.
+func (s *Stater) GetCode(codeHash ablock.Bytes32) ([]byte, error) {
+	return s.db.NewStore(codeStoreName).Get(codeHash[:])
+}
+
+func dumpRange(trie *muxdb.Trie, start []byte, limit int, maxSize int, withMeta bool) (*Range, error) {
+	var (
+		r    Range
+		size int
+		seen = make(map[string]bool)
+	)
+	addProof := func(key []byte) error {
+		proof, err := trie.Prove(key)
+		if err != nil {
+			return err
+		}
+		for _, node := range proof {
+			if !seen[string(node)] {
+				seen[string(node)] = true
+				r.Proof = append(r.Proof, node)
+			}
+		}
+		return nil
+	}
+
+	// the proof of start key ensures the range is taken from the expected trie, even if it's empty
+	if err := addProof(start); err != nil {
+		return nil, err
+	}
+
+	it := trie.NodeIterator(start, 0)
+	for len(r.Entries) < limit && size < maxSize && it.Next(true) {
+		leaf := it.Leaf()
+		if leaf == nil {
+			continue
+		}
+		entry := &RangeEntry{
+			Key:   append([]byte(nil), it.LeafKey()...),
+			Value: leaf.Value,
+		}
+		if withMeta {
+			entry.Extra = leaf.Meta
+		}
+		r.Entries = append(r.Entries, entry)
+		size += len(entry.Key) + len(entry.Value) + len(entry.Extra)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	// the proof of the last leaf closes the range, so that no leaf can be omitted
+	if n := len(r.Entries); n > 0 {
+		if err := addProof(r.Entries[n-1].Key); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"encoding/binary"
+
+	"github.com/ashishaw/authorityblock/muxdb"
+)
+
+const (
+	archivedStoreName = "state.archived"
+	propStoreName     = "state.props"
+	restoredBaseKey   = "restored-base"
+)
+
+// MarkArchived marks the state of the block as archived, which means it's still retained after
+// the trie history cleaned.
+func (s *Stater) MarkArchived(blockNum uint32) error {
+	var key [4]byte
+	binary.BigEndian.PutUint32(key[:], blockNum)
+	return s.db.NewStore(archivedStoreName).Put(key[:], nil)
+}
+
+// saveRestoredBase records the block number of the state restored by Restorer.
+func saveRestoredBase(db *muxdb.MuxDB, blockNum uint32) error {
+	var val [4]byte
+	binary.BigEndian.PutUint32(val[:], blockNum)
+	return db.NewStore(propStoreName).Put([]byte(restoredBaseKey), val[:])
+}
+
+// IsRetained returns whether the state of the block on the canonical chain is retained.
+// The state is pruned if it's neither the genesis, nor above the history cleaned by the optimizer,
+// nor archived. States before the restored one are absent if the state is bootstrapped by Restorer.
+func (s *Stater) IsRetained(blockNum uint32) (bool, error) {
+	if blockNum == 0 {
+		return true, nil
+	}
+	props := s.db.NewStore(propStoreName)
+	if val, err := props.Get([]byte(restoredBaseKey)); err != nil {
+		if !props.IsNotFound(err) {
+			return false, err
+		}
+	} else if blockNum < binary.BigEndian.Uint32(val) {
+		return false, nil
+	}
+
+	limit, err := s.db.TrieHistoryLimit()
+	if err != nil {
+		return false, err
+	}
+	// the version right before the limit is dumped before history cleaned
+	if blockNum+1 >= limit {
+		return true, nil
+	}
+
+	var key [4]byte
+	binary.BigEndian.PutUint32(key[:], blockNum)
+	store := s.db.NewStore(archivedStoreName)
+	if _, err := store.Get(key[:]); err != nil {
+		if store.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/muxdb"
+)
+
+func TestRetention(t *testing.T) {
+	db := muxdb.NewMem()
+	stater := NewStater(db)
+
+	isRetained := func(num uint32) bool {
+		retained, err := stater.IsRetained(num)
+		assert.Nil(t, err)
+		return retained
+	}
+
+	// nothing cleaned
+	assert.True(t, isRetained(50))
+
+	assert.Nil(t, db.CleanTrieHistory(context.Background(), 1, 100))
+	assert.True(t, isRetained(0))
+	assert.False(t, isRetained(50))
+	assert.False(t, isRetained(98))
+	assert.True(t, isRetained(99))
+
+	assert.Nil(t, stater.MarkArchived(50))
+	assert.True(t, isRetained(50))
+
+	// the limit never goes back
+	assert.Nil(t, db.CleanTrieHistory(context.Background(), 1, 80))
+	assert.False(t, isRetained(98))
+
+	// states before the restored one
+	restorer := stater.NewRestorer(200, 0)
+	_, err := restorer.Finish()
+	assert.Nil(t, err)
+	assert.False(t, isRetained(150))
+	assert.True(t, isRetained(200))
+}
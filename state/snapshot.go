@@ -0,0 +1,241 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/ashishaw/authorityblock/kv"
+	"github.com/ashishaw/authorityblock/lowrlp"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+// restoreBatchSize is the number of trie updates to be committed at once while restoring.
+const restoreBatchSize = 50000
+
+// DumpHandler handles accounts and storage entries dumped from a state.
+type DumpHandler interface {
+	// OnAccount is called for each account. The code is given only at the first time the code hash appears.
+	OnAccount(hashedKey []byte, data []byte, code []byte) error
+	// OnStorage is called for each storage entry of the account last passed to OnAccount.
+	OnStorage(hashedKey []byte, value []byte, keyPreimage []byte) error
+}
+
+// Dump iterates all accounts of the state at the given root in order of hashed key,
+// along with their storage entries and code.
+func (s *Stater) Dump(ctx context.Context, root ablock.Bytes32, blockNum, blockConflicts uint32, h DumpHandler) error {
+	var (
+		accTrie   = s.db.NewTrie(AccountTrieName, root, blockNum, blockConflicts)
+		codes     = s.db.NewStore(codeStoreName)
+		seenCodes = make(map[ablock.Bytes32]bool)
+	)
+	accTrie.SetNoFillCache(true)
+
+	it := accTrie.NodeIterator(nil, 0)
+	for it.Next(true) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		leaf := it.Leaf()
+		if leaf == nil {
+			continue
+		}
+
+		var acc Account
+		if err := rlp.DecodeBytes(leaf.Value, &acc); err != nil {
+			return errors.Wrap(err, "decode account")
+		}
+
+		var code []byte
+		if len(acc.CodeHash) > 0 {
+			if hash := ablock.BytesToBytes32(acc.CodeHash); !seenCodes[hash] {
+				var err error
+				if code, err = codes.Get(acc.CodeHash); err != nil {
+					return errors.Wrap(err, "get code")
+				}
+				seenCodes[hash] = true
+			}
+		}
+		if err := h.OnAccount(it.LeafKey(), leaf.Value, code); err != nil {
+			return err
+		}
+
+		if len(acc.StorageRoot) > 0 && len(leaf.Meta) > 0 {
+			var meta AccountMetadata
+			if err := rlp.DecodeBytes(leaf.Meta, &meta); err != nil {
+				return errors.Wrap(err, "decode account metadata")
+			}
+			sTrie := s.db.NewTrie(
+				StorageTrieName(meta.StorageID),
+				ablock.BytesToBytes32(acc.StorageRoot),
+				meta.StorageCommitNum,
+				meta.StorageDistinctNum)
+			sTrie.SetNoFillCache(true)
+
+			sIt := sTrie.NodeIterator(nil, 0)
+			for sIt.Next(true) {
+				if sLeaf := sIt.Leaf(); sLeaf != nil {
+					if err := h.OnStorage(sIt.LeafKey(), sLeaf.Value, sLeaf.Meta); err != nil {
+						return err
+					}
+				}
+			}
+			if err := sIt.Error(); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+// Restorer rebuilds a state from dumped accounts and storage entries.
+// All tries are committed as of the given block.
+type Restorer struct {
+	db             *muxdb.MuxDB
+	blockNum       uint32
+	blockConflicts uint32
+	accTrie        *muxdb.Trie
+	accUpdates     int
+	codes          kv.Bulk
+
+	// the account being restored
+	hashedKey   []byte
+	acc         *Account
+	sTrie       *muxdb.Trie
+	sUpdates    int
+	sTrieCount  uint64
+	accountData []byte
+}
+
+// NewRestorer creates a restorer to rebuild the state of the given block.
+// The state must be restored into a database without the state of the block.
+func (s *Stater) NewRestorer(blockNum, blockConflicts uint32) *Restorer {
+	accTrie := s.db.NewTrie(AccountTrieName, ablock.Bytes32{}, blockNum, blockConflicts)
+	accTrie.SetNoFillCache(true)
+
+	codes := s.db.NewStore(codeStoreName).Bulk()
+	codes.EnableAutoFlush()
+	return &Restorer{
+		db:             s.db,
+		blockNum:       blockNum,
+		blockConflicts: blockConflicts,
+		accTrie:        accTrie,
+		codes:          codes,
+	}
+}
+
+// AddAccount adds an account. Accounts must be added in the order they are dumped.
+func (r *Restorer) AddAccount(hashedKey []byte, data []byte, code []byte) error {
+	if err := r.flushAccount(); err != nil {
+		return err
+	}
+
+	var acc Account
+	if err := rlp.DecodeBytes(data, &acc); err != nil {
+		return errors.Wrap(err, "decode account")
+	}
+	if len(code) > 0 {
+		if err := r.codes.Put(acc.CodeHash, code); err != nil {
+			return err
+		}
+	}
+
+	r.hashedKey = append([]byte(nil), hashedKey...)
+	r.accountData = append([]byte(nil), data...)
+	r.acc = &acc
+	if len(acc.StorageRoot) > 0 {
+		// generate storage id in the same way as Stage
+		var enc lowrlp.Encoder
+		enc.EncodeUint(uint64(r.blockNum))
+		enc.EncodeUint(uint64(r.blockConflicts))
+		enc.EncodeUint(r.sTrieCount)
+		r.sTrieCount++
+
+		r.sTrie = r.db.NewTrie(StorageTrieName(enc.ToBytes()), ablock.Bytes32{}, r.blockNum, r.blockConflicts)
+		r.sTrie.SetNoFillCache(true)
+	}
+	return nil
+}
+
+// AddStorage adds a storage entry of the last added account.
+func (r *Restorer) AddStorage(hashedKey []byte, value []byte, keyPreimage []byte) error {
+	if r.sTrie == nil {
+		return errors.New("unexpected storage entry")
+	}
+	if err := r.sTrie.Update(
+		append([]byte(nil), hashedKey...),
+		append([]byte(nil), value...),
+		append([]byte(nil), keyPreimage...),
+	); err != nil {
+		return err
+	}
+	return r.commitIfFull(r.sTrie, &r.sUpdates)
+}
+
+// Finish commits all remaining changes and returns the root of the restored state.
+func (r *Restorer) Finish() (ablock.Bytes32, error) {
+	if err := r.flushAccount(); err != nil {
+		return ablock.Bytes32{}, err
+	}
+	root, commit := r.accTrie.Stage(r.blockNum, r.blockConflicts)
+	if err := commit(); err != nil {
+		return ablock.Bytes32{}, err
+	}
+	if err := r.codes.Write(); err != nil {
+		return ablock.Bytes32{}, err
+	}
+	// states before the restored one are absent
+	if err := saveRestoredBase(r.db, r.blockNum); err != nil {
+		return ablock.Bytes32{}, err
+	}
+	return root, nil
+}
+
+// flushAccount commits the storage trie of the pending account, and writes the account into account trie.
+func (r *Restorer) flushAccount() error {
+	if r.acc == nil {
+		return nil
+	}
+
+	var meta []byte
+	if r.sTrie != nil {
+		root, commit := r.sTrie.Stage(r.blockNum, r.blockConflicts)
+		if root != ablock.BytesToBytes32(r.acc.StorageRoot) {
+			return errors.Errorf("storage root mismatch, want %v, got %v", ablock.BytesToBytes32(r.acc.StorageRoot), root)
+		}
+		if err := commit(); err != nil {
+			return err
+		}
+
+		var err error
+		if meta, err = rlp.EncodeToBytes(&AccountMetadata{
+			StorageID:          []byte(r.sTrie.Name()[len(StorageTrieNamePrefix):]),
+			StorageCommitNum:   r.blockNum,
+			StorageDistinctNum: r.blockConflicts,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := r.accTrie.Update(r.hashedKey, r.accountData, meta); err != nil {
+		return err
+	}
+	r.acc, r.sTrie, r.sUpdates = nil, nil, 0
+	return r.commitIfFull(r.accTrie, &r.accUpdates)
+}
+
+// commitIfFull commits the trie once updates reach the batch size, to bound memory usage.
+func (r *Restorer) commitIfFull(trie *muxdb.Trie, updates *int) error {
+	if *updates++; *updates < restoreBatchSize {
+		return nil
+	}
+	*updates = 0
+	_, commit := trie.Stage(r.blockNum, r.blockConflicts)
+	return commit()
+}
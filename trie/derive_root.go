@@ -15,6 +15,21 @@ type DerivableList interface {
 }
 
 func DeriveRoot(list DerivableList) ablock.Bytes32 {
+	return deriveTrie(list).Hash()
+}
+
+// DeriveProof returns the merkle proof of the i-th item of the list, against the root
+// derived by DeriveRoot. The item is keyed by the RLP encoded index.
+func DeriveProof(list DerivableList, i int) ([][]byte, error) {
+	key, _ := rlp.EncodeToBytes(uint(i))
+	var proof ProofList
+	if err := deriveTrie(list).Prove(key, 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+func deriveTrie(list DerivableList) *Trie {
 	keybuf := new(bytes.Buffer)
 	trie := new(Trie)
 	for i := 0; i < list.Len(); i++ {
@@ -22,5 +37,14 @@ func DeriveRoot(list DerivableList) ablock.Bytes32 {
 		rlp.Encode(keybuf, uint(i))
 		trie.Update(keybuf.Bytes(), list.GetRlp(i))
 	}
-	return trie.Hash()
+	return trie
+}
+
+// ProofList implements DatabaseWriter to collect proof nodes in order.
+type ProofList [][]byte
+
+// Put appends a copy of the node.
+func (l *ProofList) Put(_, value []byte) error {
+	*l = append(*l, append([]byte(nil), value...))
+	return nil
 }
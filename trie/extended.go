@@ -145,6 +145,12 @@ func (e *ExtendedTrie) Update(key, value, meta []byte) error {
 	return nil
 }
 
+// Prove constructs a merkle proof for key. See Trie.Prove.
+func (e *ExtendedTrie) Prove(key []byte, fromLevel uint, proofDb DatabaseWriter) error {
+	t := &e.trie
+	return t.Prove(key, fromLevel, proofDb)
+}
+
 // Hash returns the root hash of the trie. It does not write to the
 // database and can be used even if the trie doesn't have one.
 func (e *ExtendedTrie) Hash() ablock.Bytes32 {
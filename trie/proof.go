@@ -18,6 +18,7 @@ package trie
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -35,7 +36,8 @@ import (
 // absence of the key.
 func (t *Trie) Prove(key []byte, fromLevel uint, proofDb DatabaseWriter) error {
 	// Collect all nodes on the path to key.
-	key = keybytesToHex(key)
+	hexKey := keybytesToHex(key)
+	key = hexKey
 	nodes := []node{}
 	tn := t.root
 	for len(key) > 0 && tn != nil {
@@ -55,7 +57,8 @@ func (t *Trie) Prove(key []byte, fromLevel uint, proofDb DatabaseWriter) error {
 			nodes = append(nodes, n)
 		case *hashNode:
 			var err error
-			tn, err = t.resolveHash(n, nil)
+			// nodes may be stored by path, so pass the path consumed
+			tn, err = t.resolveHash(n, hexKey[:len(hexKey)-len(key)])
 			if err != nil {
 				log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
 				return err
@@ -107,7 +110,7 @@ func VerifyProof(rootHash ablock.Bytes32, key []byte, proofDb DatabaseReader) (v
 		if err != nil {
 			return nil, fmt.Errorf("bad proof node %d: %v", i, err), i
 		}
-		keyrest, cld := get(n, key)
+		keyrest, cld := get(n, key, true)
 		switch cld := cld.(type) {
 		case nil:
 			// The trie doesn't contain the key.
@@ -121,7 +124,9 @@ func VerifyProof(rootHash ablock.Bytes32, key []byte, proofDb DatabaseReader) (v
 	}
 }
 
-func get(tn node, key []byte) ([]byte, node) {
+// get returns the child node along the key and the remaining key. If skipResolved is true,
+// it steps through resolved nodes until reaching a hash node, value node or nil.
+func get(tn node, key []byte, skipResolved bool) ([]byte, node) {
 	for {
 		switch n := tn.(type) {
 		case *shortNode:
@@ -130,9 +135,15 @@ func get(tn node, key []byte) ([]byte, node) {
 			}
 			tn = n.Val
 			key = key[len(n.Key):]
+			if !skipResolved {
+				return key, tn
+			}
 		case *fullNode:
 			tn = n.Children[key[0]]
 			key = key[1:]
+			if !skipResolved {
+				return key, tn
+			}
 		case *hashNode:
 			return key, n
 		case nil:
@@ -144,3 +155,326 @@ func get(tn node, key []byte) ([]byte, node) {
 		}
 	}
 }
+
+// VerifyRangeProof checks whether the given leaves are exactly all the leaves of the trie with keys in
+// [firstKey, the last key of leaves], against the root hash. The proof must contain the proof of firstKey
+// and the proof of the last key, from which the sub-trie covering the range is rebuilt. If no leaf given,
+// the proof must prove there is no leaf with key not less than firstKey.
+// It returns whether there are more leaves after the range. All keys must be of the same length.
+func VerifyRangeProof(rootHash ablock.Bytes32, firstKey []byte, keys [][]byte, values [][]byte, proofDb DatabaseReader) (bool, error) {
+	if len(keys) != len(values) {
+		return false, fmt.Errorf("inconsistent proof data, keys: %d, values: %d", len(keys), len(values))
+	}
+	// the leaves must be monotonic increasing and contain no deletion
+	for i, key := range keys {
+		if len(key) != len(firstKey) {
+			return false, errors.New("inconsistent key length")
+		}
+		if i == 0 && bytes.Compare(key, firstKey) < 0 {
+			return false, errors.New("key before the first key")
+		}
+		if i > 0 && bytes.Compare(keys[i-1], key) >= 0 {
+			return false, errors.New("range is not monotonically increasing")
+		}
+	}
+	for _, value := range values {
+		if len(value) == 0 {
+			return false, errors.New("range contains deletion")
+		}
+	}
+
+	// no leaf, the proof of firstKey must prove the absence of the rest
+	if len(keys) == 0 {
+		root, val, err := proofToPath(rootHash, nil, firstKey, proofDb, true)
+		if err != nil {
+			return false, err
+		}
+		if val != nil || hasRightElement(root, firstKey) {
+			return false, errors.New("more entries available")
+		}
+		return false, nil
+	}
+	lastKey := keys[len(keys)-1]
+
+	// only one leaf at the first key, two edge paths are the same
+	if bytes.Equal(firstKey, lastKey) {
+		root, val, err := proofToPath(rootHash, nil, firstKey, proofDb, false)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(val, values[0]) {
+			return false, errors.New("correct proof but invalid data")
+		}
+		return hasRightElement(root, firstKey), nil
+	}
+
+	// resolve both edge paths, the first key is allowed to be absent
+	root, _, err := proofToPath(rootHash, nil, firstKey, proofDb, true)
+	if err != nil {
+		return false, err
+	}
+	if root, _, err = proofToPath(rootHash, root, lastKey, proofDb, false); err != nil {
+		return false, err
+	}
+	// remove all references between the edge paths, and refill them by the leaves
+	empty, err := unsetInternal(root, firstKey, lastKey)
+	if err != nil {
+		return false, err
+	}
+	tr := &Trie{root: root, db: missingDatabase{}}
+	if empty {
+		tr.root = nil
+	}
+	for i, key := range keys {
+		if err := tr.TryUpdate(key, values[i]); err != nil {
+			return false, err
+		}
+	}
+	if hash := tr.Hash(); hash != rootHash {
+		return false, fmt.Errorf("invalid proof, want hash %v, got %v", rootHash, hash)
+	}
+	return hasRightElement(tr.root, lastKey), nil
+}
+
+// proofToPath resolves the path of the key from the proof, and leaves other nodes as hash nodes.
+// If root is not nil, the path is merged into it. The proof of absence is allowed if allowNonExistent is true.
+func proofToPath(rootHash ablock.Bytes32, root node, key []byte, proofDb DatabaseReader, allowNonExistent bool) (node, []byte, error) {
+	resolveNode := func(hash ablock.Bytes32) (node, error) {
+		buf, _ := proofDb.Get(hash[:])
+		if buf == nil {
+			return nil, fmt.Errorf("proof node (hash %v) missing", hash)
+		}
+		n, err := decodeNode(&hashNode{Hash: hash}, buf, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %v", err)
+		}
+		return n, nil
+	}
+	if root == nil {
+		n, err := resolveNode(rootHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		root = n
+	}
+
+	var (
+		err           error
+		child, parent node
+		keyrest       []byte
+		valnode       []byte
+	)
+	key, parent = keybytesToHex(key), root
+	for {
+		keyrest, child = get(parent, key, false)
+		switch cld := child.(type) {
+		case nil:
+			// the trie doesn't contain the key, the resolved nodes are still
+			// enough to prove the range
+			if allowNonExistent {
+				return root, nil, nil
+			}
+			return nil, nil, errors.New("the node is not contained in trie")
+		case *shortNode, *fullNode:
+			key, parent = keyrest, child // already resolved
+			continue
+		case *hashNode:
+			if child, err = resolveNode(cld.Hash); err != nil {
+				return nil, nil, err
+			}
+		case *valueNode:
+			valnode = cld.Value
+		}
+		// link the parent and child
+		switch pnode := parent.(type) {
+		case *shortNode:
+			pnode.Val = child
+		case *fullNode:
+			pnode.Children[key[0]] = child
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", pnode, pnode))
+		}
+		if len(valnode) > 0 {
+			return root, valnode, nil // the whole path is resolved
+		}
+		key, parent = keyrest, child
+	}
+}
+
+// unsetInternal removes all internal node references between the left and right paths.
+// It returns true if the whole trie should be refilled by the range.
+func unsetInternal(n node, left []byte, right []byte) (bool, error) {
+	left, right = keybytesToHex(left), keybytesToHex(right)
+
+	// step down to the fork point, which is a short node if either key
+	// doesn't match it, or a full node if the paths split
+	var (
+		pos    = 0
+		parent node
+
+		// 0 means no fork, -1 means the key is less, 1 means the key is greater
+		shortForkLeft, shortForkRight int
+	)
+findFork:
+	for {
+		switch rn := n.(type) {
+		case *shortNode:
+			rn.flags = nodeFlag{dirty: true}
+			if len(left)-pos < len(rn.Key) {
+				shortForkLeft = bytes.Compare(left[pos:], rn.Key)
+			} else {
+				shortForkLeft = bytes.Compare(left[pos:pos+len(rn.Key)], rn.Key)
+			}
+			if len(right)-pos < len(rn.Key) {
+				shortForkRight = bytes.Compare(right[pos:], rn.Key)
+			} else {
+				shortForkRight = bytes.Compare(right[pos:pos+len(rn.Key)], rn.Key)
+			}
+			if shortForkLeft != 0 || shortForkRight != 0 {
+				break findFork
+			}
+			parent = n
+			n, pos = rn.Val, pos+len(rn.Key)
+		case *fullNode:
+			rn.flags = nodeFlag{dirty: true}
+			leftnode, rightnode := rn.Children[left[pos]], rn.Children[right[pos]]
+			if leftnode == nil || rightnode == nil || leftnode != rightnode {
+				break findFork
+			}
+			parent = n
+			n, pos = rn.Children[left[pos]], pos+1
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+		}
+	}
+
+	switch rn := n.(type) {
+	case *shortNode:
+		if shortForkLeft == -1 && shortForkRight == -1 {
+			return false, errors.New("empty range")
+		}
+		if shortForkLeft == 1 && shortForkRight == 1 {
+			return false, errors.New("empty range")
+		}
+		if shortForkLeft != 0 && shortForkRight != 0 {
+			// the short node is entirely in the range
+			if parent == nil {
+				return true, nil
+			}
+			parent.(*fullNode).Children[left[pos-1]] = nil
+			return false, nil
+		}
+		// only one key doesn't match the short node
+		if shortForkRight != 0 {
+			if _, ok := rn.Val.(*valueNode); ok {
+				if parent == nil {
+					return true, nil
+				}
+				parent.(*fullNode).Children[left[pos-1]] = nil
+				return false, nil
+			}
+			return false, unset(rn, rn.Val, left[pos:], len(rn.Key), false)
+		}
+		if shortForkLeft != 0 {
+			if _, ok := rn.Val.(*valueNode); ok {
+				if parent == nil {
+					return true, nil
+				}
+				parent.(*fullNode).Children[right[pos-1]] = nil
+				return false, nil
+			}
+			return false, unset(rn, rn.Val, right[pos:], len(rn.Key), true)
+		}
+		return false, nil
+	case *fullNode:
+		for i := left[pos] + 1; i < right[pos]; i++ {
+			rn.Children[i] = nil
+		}
+		if err := unset(rn, rn.Children[left[pos]], left[pos:], 1, false); err != nil {
+			return false, err
+		}
+		if err := unset(rn, rn.Children[right[pos]], right[pos:], 1, true); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// unset removes node references on the right side of the path if removeLeft is false,
+// or on the left side otherwise. The path is allowed to be absent in the trie.
+func unset(parent node, child node, key []byte, pos int, removeLeft bool) error {
+	switch cld := child.(type) {
+	case *fullNode:
+		if removeLeft {
+			for i := 0; i < int(key[pos]); i++ {
+				cld.Children[i] = nil
+			}
+		} else {
+			for i := key[pos] + 1; i < 16; i++ {
+				cld.Children[i] = nil
+			}
+		}
+		cld.flags = nodeFlag{dirty: true}
+		return unset(cld, cld.Children[key[pos]], key, pos+1, removeLeft)
+	case *shortNode:
+		if len(key[pos:]) < len(cld.Key) || !bytes.Equal(cld.Key, key[pos:pos+len(cld.Key)]) {
+			// the path forks here, unset the branch if it's in the range,
+			// otherwise keep it with the cached hash
+			if removeLeft {
+				if bytes.Compare(cld.Key, key[pos:]) < 0 {
+					parent.(*fullNode).Children[key[pos-1]] = nil
+				}
+			} else {
+				if bytes.Compare(cld.Key, key[pos:]) > 0 {
+					parent.(*fullNode).Children[key[pos-1]] = nil
+				}
+			}
+			return nil
+		}
+		if _, ok := cld.Val.(*valueNode); ok {
+			parent.(*fullNode).Children[key[pos-1]] = nil
+			return nil
+		}
+		cld.flags = nodeFlag{dirty: true}
+		return unset(cld, cld.Val, key, pos+len(cld.Key), removeLeft)
+	case nil:
+		// a child of the fork point full node, it's an absent branch
+		return nil
+	default:
+		panic("it shouldn't happen") // hash node, value node
+	}
+}
+
+// hasRightElement returns whether there are leaves on the right side of the resolved path of the key.
+func hasRightElement(node node, key []byte) bool {
+	pos, key := 0, keybytesToHex(key)
+	for node != nil {
+		switch rn := node.(type) {
+		case *fullNode:
+			for i := key[pos] + 1; i < 16; i++ {
+				if rn.Children[i] != nil {
+					return true
+				}
+			}
+			node, pos = rn.Children[key[pos]], pos+1
+		case *shortNode:
+			if len(key)-pos < len(rn.Key) || !bytes.Equal(rn.Key, key[pos:pos+len(rn.Key)]) {
+				return bytes.Compare(rn.Key, key[pos:]) > 0
+			}
+			node, pos = rn.Val, pos+len(rn.Key)
+		case *valueNode:
+			return false // the whole path is resolved
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", node, node)) // hash node
+		}
+	}
+	return false
+}
+
+// missingDatabase is used to rebuild the trie from a range proof, all nodes out of the range are missing.
+type missingDatabase struct{}
+
+func (missingDatabase) Get(key []byte) ([]byte, error) { return nil, errors.New("missing node") }
+func (missingDatabase) Put(key, value []byte) error    { return errors.New("read only") }
@@ -49,6 +49,11 @@ func (rs Receipts) RootHash() ablock.Bytes32 {
 	return trie.DeriveRoot(derivableReceipts(rs))
 }
 
+// Prove returns the merkle proof of the i-th receipt against the root hash.
+func (rs Receipts) Prove(i int) ([][]byte, error) {
+	return trie.DeriveProof(derivableReceipts(rs), i)
+}
+
 // implements DerivableList
 type derivableReceipts Receipts
 
@@ -27,6 +27,11 @@ func (txs Transactions) RootHash() ablock.Bytes32 {
 	return trie.DeriveRoot(derivableTxs(txs))
 }
 
+// Prove returns the merkle proof of the i-th tx against the root hash.
+func (txs Transactions) Prove(i int) ([][]byte, error) {
+	return trie.DeriveProof(derivableTxs(txs), i)
+}
+
 // implements types.DerivableList
 type derivableTxs Transactions
 
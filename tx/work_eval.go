@@ -8,8 +8,6 @@ package tx
 import (
 	"math"
 	"math/big"
-
-	"github.com/ashishaw/authorityblock/ablock"
 )
 
 var (
@@ -18,6 +16,10 @@ var (
 	big104     = big.NewInt(104) // Moore's law monthly rate (percentage)
 )
 
+// blocksPerMonth is the number of blocks a month at the nominal 10s interval. The decay is counted
+// in blocks, so that it's consistent among networks regardless of the timing config.
+const blocksPerMonth = 3600 * 24 * 30 / 10
+
 // workToGas exchange proved work to gas.
 // The decay curve follows Moore's law.
 func workToGas(work *big.Int, blockNum uint32) uint64 {
@@ -26,7 +28,7 @@ func workToGas(work *big.Int, blockNum uint32) uint64 {
 		return 0
 	}
 
-	months := new(big.Int).SetUint64(uint64(blockNum) * ablock.BlockInterval / 3600 / 24 / 30)
+	months := new(big.Int).SetUint64(uint64(blockNum) / blocksPerMonth)
 	if months.Sign() != 0 {
 		x := &big.Int{}
 		gas.Mul(gas, x.Exp(big100, months, nil))
@@ -20,8 +20,10 @@ import (
 )
 
 // blocklist is a address list contains addresses that are blocked.
+// An address prefixed with '!' in the list is explicitly allowed, which overrides blocklists
+// of lower precedence.
 type blocklist struct {
-	list map[ablock.Address]bool
+	list map[ablock.Address]bool // false if allowed
 	lock sync.Mutex
 }
 
@@ -53,16 +55,20 @@ func (bl *blocklist) Save(path string) error {
 	}
 	defer file.Close()
 
-	var listToSave []ablock.Address
+	var listToSave []string
 
 	bl.lock.Lock()
-	for addr := range bl.list {
-		listToSave = append(listToSave, addr)
+	for addr, blocked := range bl.list {
+		if blocked {
+			listToSave = append(listToSave, addr.String())
+		} else {
+			listToSave = append(listToSave, "!"+addr.String())
+		}
 	}
 	bl.lock.Unlock()
 
-	for _, addr := range listToSave {
-		if _, err := file.WriteString(addr.String() + "\n"); err != nil {
+	for _, line := range listToSave {
+		if _, err := file.WriteString(line + "\n"); err != nil {
 			return err
 		}
 	}
@@ -108,7 +114,7 @@ func (bl *blocklist) Fetch(ctx context.Context, url string, eTag *string) error
 	return nil
 }
 
-// Contains returns whether the given address is listed.
+// Contains returns whether the given address is blocked.
 func (bl *blocklist) Contains(addr ablock.Address) bool {
 	bl.lock.Lock()
 	defer bl.lock.Unlock()
@@ -116,6 +122,27 @@ func (bl *blocklist) Contains(addr ablock.Address) bool {
 	return bl.list[addr]
 }
 
+// Lookup returns whether the given address is listed, and blocked or allowed.
+func (bl *blocklist) Lookup(addr ablock.Address) (blocked bool, listed bool) {
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+
+	blocked, listed = bl.list[addr]
+	return
+}
+
+// Set replaces the list with blocked addresses.
+func (bl *blocklist) Set(addrs []ablock.Address) {
+	newList := make(map[ablock.Address]bool, len(addrs))
+	for _, addr := range addrs {
+		newList[addr] = true
+	}
+
+	bl.lock.Lock()
+	bl.list = newList
+	bl.lock.Unlock()
+}
+
 func (bl *blocklist) Len() int {
 	bl.lock.Lock()
 	defer bl.lock.Unlock()
@@ -132,11 +159,16 @@ func (bl *blocklist) readList(r io.Reader) (map[ablock.Address]bool, error) {
 		if addrStr == "" {
 			continue
 		}
+		blocked := true
+		if strings.HasPrefix(addrStr, "!") {
+			blocked = false
+			addrStr = strings.TrimSpace(addrStr[1:])
+		}
 		addr, err := ablock.ParseAddress(addrStr)
 		if err != nil {
 			return nil, err
 		}
-		list[addr] = true
+		list[addr] = blocked
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
@@ -0,0 +1,191 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ashishaw/authorityblock/abi"
+	"github.com/ashishaw/authorityblock/chain"
+	"github.com/ashishaw/authorityblock/runtime"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+	"github.com/ashishaw/authorityblock/xenv"
+)
+
+// Sources of blocklist, in descending precedence.
+const (
+	BlocklistSourceBuiltin  = "builtin"  // compiled-in list
+	BlocklistSourceFile     = "file"     // local file, reloaded once modified
+	BlocklistSourceRegistry = "registry" // on-chain registry contract
+	BlocklistSourceURL      = "url"      // fetched from remote url
+)
+
+const fileWatchInterval = 3 * time.Second
+
+// the registry contract is expected to implement 'function blocklist() view returns (address[])'
+var registryABI, _ = abi.New([]byte(`[{
+	"constant": true,
+	"inputs": [],
+	"name": "blocklist",
+	"outputs": [{"name": "", "type": "address[]"}],
+	"payable": false,
+	"stateMutability": "view",
+	"type": "function"
+}]`))
+
+// blocklists merges blocklists from multiple sources.
+type blocklists struct {
+	file     blocklist
+	registry blocklist
+	url      blocklist
+}
+
+// BlockedBy returns the source which blocks the address, or empty string if not blocked.
+// An address explicitly allowed in local file is not blocked by registry and url lists.
+func (bls *blocklists) BlockedBy(addr ablock.Address) string {
+	if ablock.IsOriginBlocked(addr) {
+		return BlocklistSourceBuiltin
+	}
+	if blocked, listed := bls.file.Lookup(addr); listed {
+		if blocked {
+			return BlocklistSourceFile
+		}
+		return ""
+	}
+	if bls.registry.Contains(addr) {
+		return BlocklistSourceRegistry
+	}
+	if bls.url.Contains(addr) {
+		return BlocklistSourceURL
+	}
+	return ""
+}
+
+// IsBlocked returns whether the address is blocked by any source.
+func (bls *blocklists) IsBlocked(addr ablock.Address) bool {
+	return bls.BlockedBy(addr) != ""
+}
+
+func (p *TxPool) watchBlocklistFileLoop() {
+	path := p.options.BlocklistFilePath
+
+	var lastModTime time.Time
+	var lastSize int64 = -1
+	check := func() {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) && lastSize >= 0 {
+				lastModTime, lastSize = time.Time{}, -1
+				p.blocklists.file.Set(nil)
+				log.Info("blocklist file removed", "path", path)
+			}
+			return
+		}
+		if info.ModTime().Equal(lastModTime) && info.Size() == lastSize {
+			return
+		}
+		if err := p.blocklists.file.Load(path); err != nil {
+			log.Warn("blocklist file load failed", "error", err, "path", path)
+			return
+		}
+		lastModTime, lastSize = info.ModTime(), info.Size()
+		log.Info("blocklist file loaded", "len", p.blocklists.file.Len())
+	}
+
+	check()
+	ticker := time.NewTicker(fileWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func (p *TxPool) readBlocklistRegistryLoop() {
+	registry := *p.options.BlocklistRegistry
+
+	var lastID ablock.Bytes32
+	read := func() {
+		summary := p.repo.BestBlockSummary()
+		if summary.Header.ID() == lastID {
+			return
+		}
+		addrs, err := p.readBlocklistRegistry(registry, summary)
+		if err != nil {
+			log.Warn("blocklist registry read failed", "error", err, "registry", registry)
+			return
+		}
+		lastID = summary.Header.ID()
+		p.blocklists.registry.Set(addrs)
+		log.Debug("blocklist registry read", "len", len(addrs), "block", summary.Header.Number())
+	}
+
+	read()
+	ticker := time.NewTicker(time.Duration(p.options.BlockInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			read()
+		}
+	}
+}
+
+// readBlocklistRegistry calls the registry contract at the given block.
+func (p *TxPool) readBlocklistRegistry(registry ablock.Address, summary *chain.BlockSummary) ([]ablock.Address, error) {
+	method, _ := registryABI.MethodByName("blocklist")
+	data, err := method.EncodeInput()
+	if err != nil {
+		return nil, err
+	}
+
+	header := summary.Header
+	state := p.stater.NewState(header.StateRoot(), header.Number(), summary.Conflicts, summary.SteadyNum)
+	signer, _ := header.Signer()
+	// it's a read-only call, so it's fine to have all forks enabled
+	rt := runtime.New(p.repo.NewChain(header.ParentID()), state,
+		&xenv.BlockContext{
+			Beneficiary: header.Beneficiary(),
+			Signer:      signer,
+			Number:      header.Number(),
+			Time:        header.Timestamp(),
+			GasLimit:    header.GasLimit(),
+			TotalScore:  header.TotalScore(),
+		},
+		ablock.ForkConfig{})
+
+	exec, _ := rt.PrepareClause(tx.NewClause(&registry).WithData(data), 0, header.GasLimit(), &xenv.TransactionContext{
+		GasPrice: &big.Int{},
+		BlockRef: tx.NewBlockRef(header.Number()),
+	})
+	output, _, err := exec()
+	if err != nil {
+		return nil, err
+	}
+	if output.VMErr != nil {
+		return nil, output.VMErr
+	}
+
+	var addrs []common.Address
+	if err := method.DecodeOutput(output.Data, &addrs); err != nil {
+		return nil, err
+	}
+	list := make([]ablock.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		list = append(list, ablock.Address(addr))
+	}
+	return list, nil
+}
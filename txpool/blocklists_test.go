@@ -0,0 +1,146 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/block"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+func TestBlocklistsPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	builtin := ablock.BytesToAddress([]byte("builtin"))
+	defer ablock.MockBlocklist([]string{builtin.String()})()
+
+	var (
+		a1 = ablock.BytesToAddress([]byte("a1"))
+		a2 = ablock.BytesToAddress([]byte("a2"))
+		a3 = ablock.BytesToAddress([]byte("a3"))
+		a4 = ablock.BytesToAddress([]byte("a4"))
+	)
+
+	path := filepath.Join(dir, "blocklist")
+	content := a1.String() + "\n!" + a2.String() + "\n!" + builtin.String() + "\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var bls blocklists
+	assert.Nil(t, bls.file.Load(path))
+	bls.registry.Set([]ablock.Address{a1, a2, a3})
+	bls.url.Set([]ablock.Address{a2, a3, a4})
+
+	tests := []struct {
+		addr   ablock.Address
+		source string
+	}{
+		{builtin, BlocklistSourceBuiltin}, // builtin can't be overridden
+		{a1, BlocklistSourceFile},
+		{a2, ""}, // allowed by file
+		{a3, BlocklistSourceRegistry},
+		{a4, BlocklistSourceURL},
+		{ablock.BytesToAddress([]byte("a5")), ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.source, bls.BlockedBy(tt.addr), tt.addr.String())
+	}
+
+	// allowed entries are kept on save
+	assert.Nil(t, bls.file.Save(path))
+	var bl blocklist
+	assert.Nil(t, bl.Load(path))
+	blocked, listed := bl.Lookup(a2)
+	assert.False(t, blocked)
+	assert.True(t, listed)
+}
+
+func TestBlocklistFileReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blocklist")
+	addr := ablock.BytesToAddress([]byte("addr"))
+	if err := ioutil.WriteFile(path, []byte(addr.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
+	pool.Close()
+	pool = New(pool.repo, pool.stater, Options{
+		Limit:             LIMIT,
+		LimitPerAccount:   LIMIT_PER_ACCOUNT,
+		MaxLifetime:       time.Hour,
+		BlocklistFilePath: path,
+	})
+	defer pool.Close()
+
+	waitFor := func(source string) bool {
+		for i := 0; i < 50; i++ {
+			if pool.BlockedBy(addr) == source {
+				return true
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		return false
+	}
+	assert.True(t, waitFor(BlocklistSourceFile))
+
+	os.Remove(path)
+	assert.True(t, waitFor(""))
+}
+
+func TestReadBlocklistRegistry(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT_PER_ACCOUNT)
+	defer pool.Close()
+
+	blocked := ablock.BytesToAddress([]byte("blocked"))
+	registry := ablock.BytesToAddress([]byte("registry"))
+
+	// the code returns abi encoded address[] with a single element, regardless of input
+	code := common.FromHex("0x6060600c60003960606000f3")
+	code = append(code, common.LeftPadBytes([]byte{0x20}, 32)...)
+	code = append(code, common.LeftPadBytes([]byte{1}, 32)...)
+	code = append(code, common.LeftPadBytes(blocked.Bytes(), 32)...)
+
+	st := pool.stater.NewState(pool.repo.GenesisBlock().Header().StateRoot(), 0, 0, 0)
+	st.SetCode(registry, code)
+	stage, _ := st.Stage(1, 0)
+	root1, _ := stage.Commit()
+	b1 := new(block.Builder).
+		ParentID(pool.repo.GenesisBlock().Header().ID()).
+		Timestamp(uint64(time.Now().Unix())).
+		TotalScore(100).
+		GasLimit(10000000).
+		StateRoot(root1).
+		Build()
+	if err := pool.repo.AddBlock(b1, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	pool.repo.SetBestBlockID(b1.Header().ID())
+
+	addrs, err := pool.readBlocklistRegistry(registry, pool.repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Equal(t, []ablock.Address{blocked}, addrs)
+
+	_, err = pool.readBlocklistRegistry(ablock.BytesToAddress([]byte("none")), pool.repo.BestBlockSummary())
+	assert.NotNil(t, err, "no contract")
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+// default interval to snapshot the pool into journal.
+const defaultJournalInterval = time.Minute
+
+// journalEntry is the persisted form of a pooled tx.
+type journalEntry struct {
+	Tx        *tx.Transaction
+	TimeAdded uint64 // in unix nano
+	Local     bool
+}
+
+// journal persists pending txs of the pool into a file, so that they survive restarts.
+// The file is fully rewritten on each save.
+type journal struct {
+	path        string
+	limit       int
+	maxLifetime time.Duration
+}
+
+// Save writes txObjs into the journal file. Txs out of lifetime are skipped, and at most
+// limit txs are kept, local submitted ones first, then the newly added ones.
+func (j *journal) Save(txObjs []*txObject) (int, error) {
+	now := time.Now().UnixNano()
+	entries := make([]*journalEntry, 0, len(txObjs))
+	for _, txObj := range txObjs {
+		if !txObj.localSubmitted && now > txObj.timeAdded+int64(j.maxLifetime) {
+			continue
+		}
+		entries = append(entries, &journalEntry{txObj.Transaction, uint64(txObj.timeAdded), txObj.localSubmitted})
+	}
+	sort.SliceStable(entries, func(i, k int) bool {
+		if entries[i].Local != entries[k].Local {
+			return entries[i].Local
+		}
+		return entries[i].TimeAdded > entries[k].TimeAdded
+	})
+	if len(entries) > j.limit {
+		entries = entries[:j.limit]
+	}
+
+	// write into a temp file and then rename it, to not leave a broken journal
+	tmpPath := j.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(file)
+	for _, entry := range entries {
+		if err := rlp.Encode(w, entry); err != nil {
+			file.Close()
+			return 0, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// Load reads entries from the journal file. Entries out of lifetime are skipped.
+func (j *journal) Load() ([]*journalEntry, error) {
+	file, err := os.Open(j.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		now     = time.Now().UnixNano()
+		stream  = rlp.NewStream(bufio.NewReader(file), 0)
+		entries []*journalEntry
+	)
+	for len(entries) < j.limit {
+		var entry journalEntry
+		if err := stream.Decode(&entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			// keep entries decoded, in case of the tail corrupted
+			return entries, err
+		}
+		if !entry.Local && now > int64(entry.TimeAdded)+int64(j.maxLifetime) {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+func TestJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+	options := Options{
+		Limit:           LIMIT,
+		LimitPerAccount: LIMIT_PER_ACCOUNT,
+		MaxLifetime:     time.Hour,
+		JournalPath:     filepath.Join(dir, "txpool.journal"),
+	}
+
+	pool := New(repo, state.NewStater(db), options)
+	tx1 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[0])
+	tx2 := newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[1])
+	assert.Nil(t, pool.Add(tx1))
+	assert.Nil(t, pool.AddLocal(tx2))
+	timeAdded := pool.all.GetByID(tx1.ID()).timeAdded
+	pool.Close()
+
+	pool = New(repo, state.NewStater(db), options)
+	assert.Equal(t, 2, pool.all.Len())
+	obj1, obj2 := pool.all.GetByID(tx1.ID()), pool.all.GetByID(tx2.ID())
+	if assert.NotNil(t, obj1) && assert.NotNil(t, obj2) {
+		assert.Equal(t, timeAdded, obj1.timeAdded)
+		assert.False(t, obj1.localSubmitted)
+		assert.True(t, obj2.localSubmitted)
+	}
+	pool.Close()
+
+	// remote txs out of lifetime are not reloaded
+	options.MaxLifetime = time.Nanosecond
+	pool = New(repo, state.NewStater(db), options)
+	assert.Equal(t, 1, pool.all.Len())
+	assert.NotNil(t, pool.all.GetByID(tx2.ID()))
+	pool.Close()
+}
+
+func TestJournalLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+	var objs []*txObject
+	for i := 0; i < 4; i++ {
+		obj, _ := resolveTx(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[i]), i == 0)
+		obj.timeAdded = time.Now().UnixNano() + int64(i)
+		objs = append(objs, obj)
+	}
+
+	j := &journal{filepath.Join(dir, "txpool.journal"), 2, time.Hour}
+	n, err := j.Save(objs)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	entries, err := j.Load()
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 2) {
+		// local first, then the newest
+		assert.Equal(t, objs[0].ID(), entries[0].Tx.ID())
+		assert.True(t, entries[0].Local)
+		assert.Equal(t, objs[3].ID(), entries[1].Tx.ID())
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+// ExecutableTx is an executable tx along with the info to order it.
+type ExecutableTx struct {
+	*tx.Transaction
+	Origin          ablock.Address
+	TimeAdded       int64 // unix nano
+	OverallGasPrice *big.Int
+}
+
+// Ordering decides the order of executable txs to be adopted by packer.
+// Implementations should not modify the given slice.
+type Ordering interface {
+	Order(txs []*ExecutableTx) tx.Transactions
+}
+
+// names of built-in orderings.
+const (
+	OrderingGasPrice   = "gasprice"
+	OrderingFIFO       = "fifo"
+	OrderingRoundRobin = "round-robin"
+	OrderingPriority   = "priority"
+)
+
+// NewOrdering creates the named ordering. lanes is only used by priority ordering.
+func NewOrdering(name string, lanes [][]ablock.Address) (Ordering, error) {
+	switch name {
+	case "", OrderingGasPrice:
+		return GasPriceOrdering{}, nil
+	case OrderingFIFO:
+		return FIFOOrdering{}, nil
+	case OrderingRoundRobin:
+		return RoundRobinOrdering{}, nil
+	case OrderingPriority:
+		if len(lanes) == 0 {
+			return nil, fmt.Errorf("priority ordering requires at least one lane")
+		}
+		return NewPriorityOrdering(lanes), nil
+	}
+	return nil, fmt.Errorf("unknown tx ordering %q", name)
+}
+
+// ParseLanes parses priority lanes in form of 'addr1,addr2;addr3', lanes are separated by ';'
+// in descending priority, and origins in the same lane are separated by ','.
+func ParseLanes(str string) ([][]ablock.Address, error) {
+	var lanes [][]ablock.Address
+	for _, l := range strings.Split(str, ";") {
+		var lane []ablock.Address
+		for _, s := range strings.Split(l, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			addr, err := ablock.ParseAddress(s)
+			if err != nil {
+				return nil, err
+			}
+			lane = append(lane, addr)
+		}
+		if len(lane) > 0 {
+			lanes = append(lanes, lane)
+		}
+	}
+	return lanes, nil
+}
+
+func toTxs(txs []*ExecutableTx) tx.Transactions {
+	list := make(tx.Transactions, 0, len(txs))
+	for _, t := range txs {
+		list = append(list, t.Transaction)
+	}
+	return list
+}
+
+func sortedByGasPrice(txs []*ExecutableTx) []*ExecutableTx {
+	sorted := append([]*ExecutableTx(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OverallGasPrice.Cmp(sorted[j].OverallGasPrice) > 0
+	})
+	return sorted
+}
+
+// GasPriceOrdering orders txs by overall gas price from high to low. It's the default ordering.
+type GasPriceOrdering struct{}
+
+// Order implements Ordering.
+func (GasPriceOrdering) Order(txs []*ExecutableTx) tx.Transactions {
+	return toTxs(sortedByGasPrice(txs))
+}
+
+// FIFOOrdering orders txs strictly by the time they arrived at the pool.
+type FIFOOrdering struct{}
+
+// Order implements Ordering.
+func (FIFOOrdering) Order(txs []*ExecutableTx) tx.Transactions {
+	sorted := append([]*ExecutableTx(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeAdded < sorted[j].TimeAdded
+	})
+	return toTxs(sorted)
+}
+
+// RoundRobinOrdering takes txs from each origin in turn, so that no origin can occupy a block.
+// Origins are visited in the arrival order of their first tx, and txs of an origin are in arrival order.
+type RoundRobinOrdering struct{}
+
+// Order implements Ordering.
+func (RoundRobinOrdering) Order(txs []*ExecutableTx) tx.Transactions {
+	sorted := append([]*ExecutableTx(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeAdded < sorted[j].TimeAdded
+	})
+
+	var (
+		origins []ablock.Address
+		queues  = make(map[ablock.Address][]*ExecutableTx)
+	)
+	for _, t := range sorted {
+		if _, ok := queues[t.Origin]; !ok {
+			origins = append(origins, t.Origin)
+		}
+		queues[t.Origin] = append(queues[t.Origin], t)
+	}
+
+	list := make(tx.Transactions, 0, len(txs))
+	for round := 0; len(list) < len(txs); round++ {
+		for _, origin := range origins {
+			if q := queues[origin]; round < len(q) {
+				list = append(list, q[round].Transaction)
+			}
+		}
+	}
+	return list
+}
+
+// PriorityOrdering puts txs from whitelisted origins ahead. Txs are grouped into lanes,
+// and txs not from any whitelisted origin fall into the last lane.
+// Txs in the same lane are ordered by overall gas price.
+type PriorityOrdering struct {
+	lanes map[ablock.Address]int
+	last  int
+}
+
+// NewPriorityOrdering creates a priority ordering with lanes in descending priority.
+func NewPriorityOrdering(lanes [][]ablock.Address) *PriorityOrdering {
+	o := &PriorityOrdering{
+		lanes: make(map[ablock.Address]int),
+		last:  len(lanes),
+	}
+	for i, lane := range lanes {
+		for _, origin := range lane {
+			// the origin takes the highest lane if duplicated
+			if _, ok := o.lanes[origin]; !ok {
+				o.lanes[origin] = i
+			}
+		}
+	}
+	return o
+}
+
+func (o *PriorityOrdering) lane(origin ablock.Address) int {
+	if i, ok := o.lanes[origin]; ok {
+		return i
+	}
+	return o.last
+}
+
+// Order implements Ordering.
+func (o *PriorityOrdering) Order(txs []*ExecutableTx) tx.Transactions {
+	sorted := sortedByGasPrice(txs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return o.lane(sorted[i].Origin) < o.lane(sorted[j].Origin)
+	})
+	return toTxs(sorted)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+func newExecutableTx(nonce uint64, origin byte, timeAdded int64, gasPrice int64) *ExecutableTx {
+	return &ExecutableTx{
+		Transaction:     new(tx.Builder).Nonce(nonce).Build(),
+		Origin:          ablock.BytesToAddress([]byte{origin}),
+		TimeAdded:       timeAdded,
+		OverallGasPrice: big.NewInt(gasPrice),
+	}
+}
+
+func nonces(txs tx.Transactions) []uint64 {
+	var list []uint64
+	for _, t := range txs {
+		list = append(list, t.Nonce())
+	}
+	return list
+}
+
+func TestOrdering(t *testing.T) {
+	// nonce, origin, time added, gas price
+	txs := []*ExecutableTx{
+		newExecutableTx(0, 1, 5, 300),
+		newExecutableTx(1, 1, 1, 200),
+		newExecutableTx(2, 1, 2, 100),
+		newExecutableTx(3, 2, 4, 250),
+		newExecutableTx(4, 3, 3, 50),
+		newExecutableTx(5, 2, 6, 250),
+	}
+	orig := append([]*ExecutableTx(nil), txs...)
+
+	assert.Equal(t, []uint64{0, 3, 5, 1, 2, 4}, nonces(GasPriceOrdering{}.Order(txs)))
+	assert.Equal(t, []uint64{1, 2, 4, 3, 0, 5}, nonces(FIFOOrdering{}.Order(txs)))
+	// origins visited in order 1, 3, 2
+	assert.Equal(t, []uint64{1, 4, 3, 2, 5, 0}, nonces(RoundRobinOrdering{}.Order(txs)))
+
+	lanes, err := ParseLanes("0x0000000000000000000000000000000000000003; 0x0000000000000000000000000000000000000002,")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(lanes))
+	assert.Equal(t, []uint64{4, 3, 5, 0, 1, 2}, nonces(NewPriorityOrdering(lanes).Order(txs)))
+
+	assert.Equal(t, orig, txs, "input should not be modified")
+
+	_, err = ParseLanes("0x01,abc")
+	assert.NotNil(t, err)
+
+	for _, name := range []string{"", OrderingGasPrice, OrderingFIFO, OrderingRoundRobin} {
+		_, err := NewOrdering(name, nil)
+		assert.Nil(t, err)
+	}
+	_, err = NewOrdering(OrderingPriority, nil)
+	assert.NotNil(t, err, "lanes required")
+	_, err = NewOrdering(OrderingPriority, lanes)
+	assert.Nil(t, err)
+	_, err = NewOrdering("unknown", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ashishaw/authorityblock/metric"
+	"github.com/ashishaw/authorityblock/ablock"
+)
+
+const defaultRateLimitWindow = 6 // ~1 min
+
+var (
+	metricRateLimited = metric.NewCounterVec("txpool_rate_limited_total", "number of txs rejected by rate limiting", "kind", "reason")
+	metricExecFailed  = metric.NewCounterVec("txpool_exec_failures_total", "number of failed tx executions counted by rate limiting", "kind")
+	metricBans        = metric.NewCounterVec("txpool_bans_total", "number of temporary bans", "kind")
+)
+
+// RateLimit defines limits of txs accepted per origin and per delegator. Limits are counted in
+// windows of blocks, and an account exceeds any limit is banned for some blocks.
+// A zero limit means unlimited.
+type RateLimit struct {
+	Window            uint32 // size of window in blocks, defaults to 6
+	OriginTxs         int    // max txs accepted from an origin in a window
+	DelegatorTxs      int    // max txs accepted from a delegator in a window
+	OriginFailures    int    // max failed executions of txs from an origin in a window
+	DelegatorFailures int    // max failed executions of txs from a delegator in a window
+	Ban               uint32 // ban duration in blocks, defaults to the window size
+}
+
+// ParseRateLimit parses rate limit in form of 'key=value' separated by ','.
+// Keys are window, originTxs, delegatorTxs, originFailures, delegatorFailures and ban.
+func ParseRateLimit(str string) (rl RateLimit, err error) {
+	for _, kv := range strings.Split(str, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit item '%v'", kv)
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 32)
+		if err != nil {
+			return RateLimit{}, fmt.Errorf("invalid rate limit value '%v'", kv)
+		}
+		switch strings.TrimSpace(parts[0]) {
+		case "window":
+			rl.Window = uint32(v)
+		case "originTxs":
+			rl.OriginTxs = int(v)
+		case "delegatorTxs":
+			rl.DelegatorTxs = int(v)
+		case "originFailures":
+			rl.OriginFailures = int(v)
+		case "delegatorFailures":
+			rl.DelegatorFailures = int(v)
+		case "ban":
+			rl.Ban = uint32(v)
+		default:
+			return RateLimit{}, fmt.Errorf("unknown rate limit key '%v'", parts[0])
+		}
+	}
+	return
+}
+
+// RateLimitState is the state of an account counted by a kind of limiter in the current window.
+type RateLimitState struct {
+	Kind        string // origin or delegator
+	Txs         int
+	Failures    int
+	BannedUntil uint32 // zero if not banned
+}
+
+// Enabled returns whether any limit is set.
+func (rl RateLimit) Enabled() bool {
+	return rl.OriginTxs > 0 || rl.DelegatorTxs > 0 || rl.OriginFailures > 0 || rl.DelegatorFailures > 0
+}
+
+// limiter counts txs and failures of a kind of accounts.
+type limiter struct {
+	kind        string
+	maxTxs      int
+	maxFailures int
+	txs         map[ablock.Address]int
+	failures    map[ablock.Address]int
+	bans        map[ablock.Address]uint32 // banned until the block number
+}
+
+func newLimiter(kind string, maxTxs, maxFailures int) *limiter {
+	return &limiter{
+		kind:        kind,
+		maxTxs:      maxTxs,
+		maxFailures: maxFailures,
+		txs:         make(map[ablock.Address]int),
+		failures:    make(map[ablock.Address]int),
+		bans:        make(map[ablock.Address]uint32),
+	}
+}
+
+func (l *limiter) isBanned(addr ablock.Address, blockNum uint32) bool {
+	until, ok := l.bans[addr]
+	return ok && blockNum < until
+}
+
+func (l *limiter) ban(addr ablock.Address, until uint32) {
+	metricBans.WithLabelValues(l.kind).Inc()
+	l.bans[addr] = until
+	log.Debug("account banned", "kind", l.kind, "addr", addr, "until", until)
+}
+
+// rateLimiter limits txs per origin and per delegator.
+type rateLimiter struct {
+	opts      RateLimit
+	lock      sync.Mutex
+	window    uint32 // index of current window
+	origin    *limiter
+	delegator *limiter
+}
+
+func newRateLimiter(opts RateLimit) *rateLimiter {
+	if opts.Window == 0 {
+		opts.Window = defaultRateLimitWindow
+	}
+	if opts.Ban == 0 {
+		opts.Ban = opts.Window
+	}
+	return &rateLimiter{
+		opts:      opts,
+		origin:    newLimiter("origin", opts.OriginTxs, opts.OriginFailures),
+		delegator: newLimiter("delegator", opts.DelegatorTxs, opts.DelegatorFailures),
+	}
+}
+
+// roll resets counters if the window changed, and removes expired bans.
+func (rl *rateLimiter) roll(blockNum uint32) {
+	window := blockNum / rl.opts.Window
+	if window == rl.window {
+		return
+	}
+	rl.window = window
+	for _, l := range []*limiter{rl.origin, rl.delegator} {
+		l.txs = make(map[ablock.Address]int)
+		l.failures = make(map[ablock.Address]int)
+		for addr, until := range l.bans {
+			if blockNum >= until {
+				delete(l.bans, addr)
+			}
+		}
+	}
+}
+
+// limiters returns limiters along with the account to be checked.
+func (rl *rateLimiter) limiters(origin ablock.Address, delegator *ablock.Address) ([]*limiter, []ablock.Address) {
+	ls, addrs := []*limiter{rl.origin}, []ablock.Address{origin}
+	if delegator != nil {
+		ls, addrs = append(ls, rl.delegator), append(addrs, *delegator)
+	}
+	return ls, addrs
+}
+
+// Check checks a new tx, and returns error if the origin or the delegator is banned or exceeds
+// the limit. The tx is not counted until Accepted is called.
+func (rl *rateLimiter) Check(origin ablock.Address, delegator *ablock.Address, blockNum uint32) error {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	rl.roll(blockNum)
+
+	ls, addrs := rl.limiters(origin, delegator)
+	for i, l := range ls {
+		if l.isBanned(addrs[i], blockNum) {
+			metricRateLimited.WithLabelValues(l.kind, "banned").Inc()
+			return fmt.Errorf("%v banned", l.kind)
+		}
+	}
+	for i, l := range ls {
+		if l.maxTxs > 0 && l.txs[addrs[i]] >= l.maxTxs {
+			l.ban(addrs[i], blockNum+rl.opts.Ban)
+			metricRateLimited.WithLabelValues(l.kind, "rate").Inc()
+			return fmt.Errorf("%v rate limit exceeded", l.kind)
+		}
+	}
+	return nil
+}
+
+// Accepted counts a tx accepted by the pool.
+func (rl *rateLimiter) Accepted(origin ablock.Address, delegator *ablock.Address, blockNum uint32) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	rl.roll(blockNum)
+
+	ls, addrs := rl.limiters(origin, delegator)
+	for i, l := range ls {
+		l.txs[addrs[i]]++
+	}
+}
+
+// Failed counts a failed execution, and bans the origin or the delegator if exceeds the limit.
+func (rl *rateLimiter) Failed(origin ablock.Address, delegator *ablock.Address, blockNum uint32) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	rl.roll(blockNum)
+
+	ls, addrs := rl.limiters(origin, delegator)
+	for i, l := range ls {
+		metricExecFailed.WithLabelValues(l.kind).Inc()
+		l.failures[addrs[i]]++
+		if l.maxFailures > 0 && l.failures[addrs[i]] > l.maxFailures && !l.isBanned(addrs[i], blockNum) {
+			l.ban(addrs[i], blockNum+rl.opts.Ban)
+		}
+	}
+}
+
+// State returns states of the address as an origin and as a delegator.
+func (rl *rateLimiter) State(addr ablock.Address, blockNum uint32) []*RateLimitState {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	rl.roll(blockNum)
+
+	states := make([]*RateLimitState, 0, 2)
+	for _, l := range []*limiter{rl.origin, rl.delegator} {
+		st := &RateLimitState{
+			Kind:     l.kind,
+			Txs:      l.txs[addr],
+			Failures: l.failures[addr],
+		}
+		if l.isBanned(addr, blockNum) {
+			st.BannedUntil = l.bans[addr]
+		}
+		states = append(states, st)
+	}
+	return states
+}
+
+// IsBanned returns whether the origin or the delegator is banned.
+func (rl *rateLimiter) IsBanned(origin ablock.Address, delegator *ablock.Address, blockNum uint32) bool {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	ls, addrs := rl.limiters(origin, delegator)
+	for i, l := range ls {
+		if l.isBanned(addrs[i], blockNum) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2022 Ashish Waingankar
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/ashishaw/authorityblock/genesis"
+	"github.com/ashishaw/authorityblock/muxdb"
+	"github.com/ashishaw/authorityblock/state"
+	"github.com/ashishaw/authorityblock/ablock"
+	"github.com/ashishaw/authorityblock/tx"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	rl, err := ParseRateLimit("window=10, originTxs=5,delegatorTxs=20,originFailures=2,delegatorFailures=8,ban=60")
+	assert.Nil(t, err)
+	assert.Equal(t, RateLimit{10, 5, 20, 2, 8, 60}, rl)
+	assert.True(t, rl.Enabled())
+
+	rl, err = ParseRateLimit("")
+	assert.Nil(t, err)
+	assert.False(t, rl.Enabled())
+
+	_, err = ParseRateLimit("originTxs")
+	assert.NotNil(t, err)
+	_, err = ParseRateLimit("originTxs=-1")
+	assert.NotNil(t, err)
+	_, err = ParseRateLimit("foo=1")
+	assert.NotNil(t, err)
+}
+
+func TestRateLimiter(t *testing.T) {
+	var (
+		a1 = ablock.BytesToAddress([]byte("a1"))
+		a2 = ablock.BytesToAddress([]byte("a2"))
+		d  = ablock.BytesToAddress([]byte("d"))
+	)
+
+	rl := newRateLimiter(RateLimit{Window: 10, OriginTxs: 2, DelegatorTxs: 3, OriginFailures: 1, Ban: 20})
+	admit := func(origin ablock.Address, delegator *ablock.Address, blockNum uint32) error {
+		if err := rl.Check(origin, delegator, blockNum); err != nil {
+			return err
+		}
+		rl.Accepted(origin, delegator, blockNum)
+		return nil
+	}
+
+	// checked but not accepted txs are not counted
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, rl.Check(a1, nil, 1))
+	}
+
+	// origin tx limit
+	assert.Nil(t, admit(a1, nil, 1))
+	assert.Nil(t, admit(a1, nil, 2))
+	assert.EqualError(t, admit(a1, nil, 3), "origin rate limit exceeded")
+	assert.EqualError(t, admit(a1, nil, 4), "origin banned")
+	assert.True(t, rl.IsBanned(a1, nil, 22))
+	// still banned in next window
+	assert.EqualError(t, admit(a1, nil, 15), "origin banned")
+	// ban expired
+	assert.False(t, rl.IsBanned(a1, nil, 23))
+	assert.Nil(t, admit(a1, nil, 23))
+
+	// delegator tx limit
+	assert.Nil(t, admit(a2, &d, 30))
+	assert.Nil(t, admit(ablock.BytesToAddress([]byte("a3")), &d, 30))
+	assert.Nil(t, admit(ablock.BytesToAddress([]byte("a4")), &d, 30))
+	assert.EqualError(t, admit(ablock.BytesToAddress([]byte("a5")), &d, 30), "delegator rate limit exceeded")
+	assert.True(t, rl.IsBanned(ablock.Address{}, &d, 30))
+	assert.False(t, rl.IsBanned(a2, nil, 30))
+
+	// counters reset in new window
+	assert.Nil(t, admit(a2, nil, 40))
+	assert.Nil(t, admit(a2, nil, 41))
+
+	// failures
+	rl.Failed(a2, nil, 42)
+	assert.False(t, rl.IsBanned(a2, nil, 42))
+	rl.Failed(a2, nil, 42)
+	assert.True(t, rl.IsBanned(a2, nil, 42))
+}
+
+func TestPoolRateLimit(t *testing.T) {
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+	pool := New(repo, state.NewStater(db), Options{
+		Limit:           LIMIT,
+		LimitPerAccount: LIMIT,
+		MaxLifetime:     time.Hour,
+		RateLimit:       RateLimit{OriginTxs: 2},
+	})
+	defer pool.Close()
+
+	acc := genesis.DevAccounts()[0]
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), acc)))
+	}
+	err := pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), acc))
+	assert.EqualError(t, err, "tx rejected: origin rate limit exceeded")
+	err = pool.AddLocal(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), acc))
+	assert.EqualError(t, err, "tx rejected: origin banned")
+
+	// other origins not affected
+	assert.Nil(t, pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[1])))
+
+	// txs of banned origin are washed out
+	_, removed, err := pool.wash(repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, 1, pool.all.Len())
+}
+
+func TestPoolRateLimitRejected(t *testing.T) {
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+	pool := New(repo, state.NewStater(db), Options{
+		Limit:           1,
+		LimitPerAccount: 1,
+		MaxLifetime:     time.Hour,
+		RateLimit:       RateLimit{OriginTxs: 1},
+	})
+	defer pool.Close()
+
+	assert.Nil(t, pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[1])))
+
+	// txs rejected for the capacity of the pool don't count
+	acc := genesis.DevAccounts()[0]
+	for i := 0; i < 3; i++ {
+		err := pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), acc))
+		assert.EqualError(t, err, "tx rejected: pool is full")
+	}
+	assert.Equal(t, []*RateLimitState{{Kind: "origin"}, {Kind: "delegator"}}, pool.RateLimitState(acc.Address))
+}
+
+func TestPoolRateLimitFailures(t *testing.T) {
+	db := muxdb.NewMem()
+	repo := newChainRepo(db)
+	pool := New(repo, state.NewStater(db), Options{
+		Limit:           LIMIT,
+		LimitPerAccount: LIMIT,
+		MaxLifetime:     time.Hour,
+		RateLimit:       RateLimit{OriginFailures: 1},
+	})
+	defer pool.Close()
+
+	// out of energy or with broken dependency, not failures
+	key, _ := crypto.GenerateKey()
+	poor := genesis.DevAccount{Address: ablock.Address(crypto.PubkeyToAddress(key.PublicKey)), PrivateKey: key}
+	assert.Nil(t, pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), poor)))
+	acc := genesis.DevAccounts()[0]
+	assert.Nil(t, pool.Add(newTx(repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, &ablock.Bytes32{1}, tx.Features(0), acc)))
+	_, _, err := pool.wash(repo.BestBlockSummary())
+	assert.Nil(t, err)
+	assert.Equal(t, []*RateLimitState{{Kind: "origin", Txs: 1}, {Kind: "delegator"}}, pool.RateLimitState(poor.Address))
+	assert.Equal(t, []*RateLimitState{{Kind: "origin", Txs: 1}, {Kind: "delegator"}}, pool.RateLimitState(acc.Address))
+
+	// intrinsic gas not covered
+	for i := 0; i < 2; i++ {
+		err = pool.Add(newTx(repo.ChainTag(), nil, 20000, tx.BlockRef{}, 100, nil, tx.Features(0), acc))
+		assert.EqualError(t, err, "bad tx: intrinsic gas exceeds provided gas")
+	}
+	states := pool.RateLimitState(acc.Address)
+	assert.Equal(t, 2, states[0].Failures)
+	assert.NotZero(t, states[0].BannedUntil)
+}
@@ -6,10 +6,12 @@
 package txpool
 
 import (
+	"io"
 	"math/big"
 	"sort"
 	"time"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
 	"github.com/ashishaw/authorityblock/block"
 	"github.com/ashishaw/authorityblock/chain"
@@ -19,9 +21,16 @@ import (
 	"github.com/ashishaw/authorityblock/tx"
 )
 
+var (
+	errKnownTx    = errors.New("known tx")
+	errRevertedTx = errors.New("known tx reverted")
+)
+
 type txObject struct {
 	*tx.Transaction
 	resolved *runtime.ResolvedTransaction
+	slot     ablock.Bytes32 // txs in the same slot are candidates to replace each other
+	clauses  ablock.Bytes32 // hash of clauses
 
 	timeAdded       int64
 	executable      bool
@@ -36,8 +45,14 @@ func resolveTx(tx *tx.Transaction, localSubmitted bool) (*txObject, error) {
 	}
 
 	return &txObject{
-		Transaction:    tx,
-		resolved:       resolved,
+		Transaction: tx,
+		resolved:    resolved,
+		slot: ablock.Blake2bFn(func(w io.Writer) {
+			rlp.Encode(w, []interface{}{resolved.Origin, tx.Nonce(), tx.BlockRef()})
+		}),
+		clauses: ablock.Blake2bFn(func(w io.Writer) {
+			rlp.Encode(w, tx.Clauses())
+		}),
 		timeAdded:      time.Now().UnixNano(),
 		localSubmitted: localSubmitted,
 	}, nil
@@ -47,23 +62,38 @@ func (o *txObject) Origin() ablock.Address {
 	return o.resolved.Origin
 }
 
-func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock *block.Header) (bool, error) {
+// IsCancel returns whether the tx is to cancel pooled txs in the same slot.
+func (o *txObject) IsCancel() bool {
+	return len(o.Clauses()) == 0
+}
+
+// CanReplace returns whether the tx can replace the given one, if it's not underpriced.
+// A tx replaces another one in the same slot, if it has identical clauses or either of them is a cancel tx.
+// The latter keeps txs cancelled, e.g. re-gossiped by peers, out of the pool unless they are priced higher.
+func (o *txObject) CanReplace(other *txObject) bool {
+	return o.slot == other.slot && (o.clauses == other.clauses || o.IsCancel() || other.IsCancel())
+}
+
+func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock *block.Header, blockInterval uint64) (bool, error) {
 	switch {
 	case o.Gas() > headBlock.GasLimit():
 		return false, errors.New("gas too large")
 	case o.IsExpired(headBlock.Number()):
 		return false, errors.New("expired")
-	case o.BlockRef().Number() > headBlock.Number()+uint32(5*60/ablock.BlockInterval):
+	case o.BlockRef().Number() > headBlock.Number()+uint32(5*60/blockInterval):
 		// reject deferred tx which will be applied after 5mins
 		return false, errors.New("block ref out of schedule")
 	}
 
-	if _, err := chain.GetTransactionMeta(o.ID()); err != nil {
+	if meta, err := chain.GetTransactionMeta(o.ID()); err != nil {
 		if !chain.IsNotFound(err) {
 			return false, err
 		}
 	} else {
-		return false, errors.New("known tx")
+		if meta.Reverted {
+			return false, errRevertedTx
+		}
+		return false, errKnownTx
 	}
 
 	if dep := o.DependsOn(); dep != nil {
@@ -86,7 +116,7 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 	// checkpoint := state.NewCheckpoint()
 	// defer state.RevertTo(checkpoint)
 
-	if _, _, _, _, err := o.resolved.BuyGas(state, headBlock.Timestamp()+ablock.BlockInterval); err != nil {
+	if _, _, _, _, err := o.resolved.BuyGas(state, headBlock.Timestamp()+blockInterval); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -13,11 +13,14 @@ import (
 	"github.com/ashishaw/authorityblock/tx"
 )
 
+var errReplacementUnderpriced = errors.New("replacement tx underpriced")
+
 // txObjectMap to maintain mapping of tx hash to tx object, and account quota.
 type txObjectMap struct {
 	lock      sync.RWMutex
 	mapByHash map[ablock.Bytes32]*txObject
 	mapByID   map[ablock.Bytes32]*txObject
+	mapBySlot map[ablock.Bytes32][]*txObject
 	quota     map[ablock.Address]int
 }
 
@@ -25,6 +28,7 @@ func newTxObjectMap() *txObjectMap {
 	return &txObjectMap{
 		mapByHash: make(map[ablock.Bytes32]*txObject),
 		mapByID:   make(map[ablock.Bytes32]*txObject),
+		mapBySlot: make(map[ablock.Bytes32][]*txObject),
 		quota:     make(map[ablock.Address]int),
 	}
 }
@@ -36,23 +40,66 @@ func (m *txObjectMap) ContainsHash(txHash ablock.Bytes32) bool {
 	return found
 }
 
-func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) error {
+// Add adds the tx object. If the tx object can replace pooled ones, they are evicted and returned.
+// A replacement must have a strictly higher gas price coef than all the replaced ones.
+func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) (replaced []*txObject, err error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	hash := txObj.Hash()
 	if _, found := m.mapByHash[hash]; found {
-		return nil
+		return nil, nil
+	}
+
+	for _, slotObj := range m.mapBySlot[txObj.slot] {
+		if txObj.CanReplace(slotObj) {
+			if txObj.GasPriceCoef() <= slotObj.GasPriceCoef() {
+				return nil, errReplacementUnderpriced
+			}
+			replaced = append(replaced, slotObj)
+		}
 	}
 
-	if m.quota[txObj.Origin()] >= limitPerAccount {
-		return errors.New("account quota exceeded")
+	// the replacement takes the quota of replaced ones
+	if len(replaced) == 0 && m.quota[txObj.Origin()] >= limitPerAccount {
+		return nil, errors.New("account quota exceeded")
 	}
 
+	for _, obj := range replaced {
+		m.remove(obj)
+	}
+	m.add(txObj)
+	return replaced, nil
+}
+
+func (m *txObjectMap) add(txObj *txObject) {
 	m.quota[txObj.Origin()]++
-	m.mapByHash[hash] = txObj
+	m.mapByHash[txObj.Hash()] = txObj
 	m.mapByID[txObj.ID()] = txObj
-	return nil
+	m.mapBySlot[txObj.slot] = append(m.mapBySlot[txObj.slot], txObj)
+}
+
+func (m *txObjectMap) remove(txObj *txObject) {
+	if m.quota[txObj.Origin()] > 1 {
+		m.quota[txObj.Origin()]--
+	} else {
+		delete(m.quota, txObj.Origin())
+	}
+	delete(m.mapByHash, txObj.Hash())
+	delete(m.mapByID, txObj.ID())
+
+	slotObjs := m.mapBySlot[txObj.slot]
+	for i, obj := range slotObjs {
+		if obj == txObj {
+			slotObjs = append(slotObjs[:i:i], slotObjs[i+1:]...)
+			break
+		}
+	}
+	if len(slotObjs) > 0 {
+		m.mapBySlot[txObj.slot] = slotObjs
+	} else {
+		delete(m.mapBySlot, txObj.slot)
+	}
 }
 
 func (m *txObjectMap) GetByID(id ablock.Bytes32) *txObject {
@@ -66,13 +113,7 @@ func (m *txObjectMap) RemoveByHash(txHash ablock.Bytes32) bool {
 	defer m.lock.Unlock()
 
 	if txObj, ok := m.mapByHash[txHash]; ok {
-		if m.quota[txObj.Origin()] > 1 {
-			m.quota[txObj.Origin()]--
-		} else {
-			delete(m.quota, txObj.Origin())
-		}
-		delete(m.mapByHash, txHash)
-		delete(m.mapByID, txObj.ID())
+		m.remove(txObj)
 		return true
 	}
 	return false
@@ -107,11 +148,8 @@ func (m *txObjectMap) Fill(txObjs []*txObject) {
 		if _, found := m.mapByHash[txObj.Hash()]; found {
 			continue
 		}
-		// skip account limit check
-
-		m.quota[txObj.Origin()]++
-		m.mapByHash[txObj.Hash()] = txObj
-		m.mapByID[txObj.ID()] = txObj
+		// skip account limit check and replacement
+		m.add(txObj)
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/ashishaw/authorityblock/builtin"
 	"github.com/ashishaw/authorityblock/chain"
 	"github.com/ashishaw/authorityblock/co"
+	"github.com/ashishaw/authorityblock/metric"
 	"github.com/ashishaw/authorityblock/state"
 	"github.com/ashishaw/authorityblock/ablock"
 	"github.com/ashishaw/authorityblock/tx"
@@ -31,6 +32,10 @@ const (
 
 var (
 	log = log15.New("pkg", "txpool")
+
+	metricPoolSize    = metric.NewGauge("txpool_size", "number of txs in pool")
+	metricExecutables = metric.NewGauge("txpool_executables", "number of executable txs in pool")
+	metricWashedOut   = metric.NewCounter("txpool_washed_out_total", "number of txs washed out of pool")
 )
 
 // Options options for tx pool.
@@ -40,6 +45,18 @@ type Options struct {
 	MaxLifetime            time.Duration
 	BlocklistCacheFilePath string
 	BlocklistFetchURL      string
+	// BlocklistFilePath is the path of local blocklist file, which is reloaded once modified.
+	BlocklistFilePath string
+	// BlocklistRegistry is the address of on-chain blocklist registry, which is read at best block.
+	BlocklistRegistry *ablock.Address
+	// JournalPath is the file path to persist pending txs, journal is disabled if empty.
+	JournalPath string
+	// JournalInterval is the interval to snapshot the pool into journal, defaults to 1 minute.
+	JournalInterval time.Duration
+	// RateLimit limits txs per origin and per delegator, disabled if no limit set.
+	RateLimit RateLimit
+	// BlockInterval is the block interval of the chain in seconds, defaults to ablock.DefaultConfig.BlockInterval.
+	BlockInterval uint64
 }
 
 // TxEvent will be posted when tx is added or status changed.
@@ -50,14 +67,18 @@ type TxEvent struct {
 
 // TxPool maintains unprocessed transactions.
 type TxPool struct {
-	options   Options
-	repo      *chain.Repository
-	stater    *state.Stater
-	blocklist blocklist
+	options     Options
+	repo        *chain.Repository
+	stater      *state.Stater
+	blocklists  blocklists
+	journal     *journal
+	rateLimiter *rateLimiter // nil if disabled
 
 	executables    atomic.Value
 	all            *txObjectMap
 	addedAfterWash uint32
+	// set when txs replaced, to refresh executables asap
+	replacedAfterWash uint32
 
 	ctx    context.Context
 	cancel func()
@@ -69,6 +90,9 @@ type TxPool struct {
 // New create a new TxPool instance.
 // Shutdown is required to be called at end.
 func New(repo *chain.Repository, stater *state.Stater, options Options) *TxPool {
+	if options.BlockInterval == 0 {
+		options.BlockInterval = ablock.DefaultConfig.BlockInterval
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	pool := &TxPool{
 		options: options,
@@ -79,11 +103,77 @@ func New(repo *chain.Repository, stater *state.Stater, options Options) *TxPool
 		cancel:  cancel,
 	}
 
+	if options.RateLimit.Enabled() {
+		pool.rateLimiter = newRateLimiter(options.RateLimit)
+	}
+	if options.JournalPath != "" {
+		pool.journal = &journal{options.JournalPath, options.Limit, options.MaxLifetime}
+		pool.loadJournal()
+		pool.goes.Go(pool.journalLoop)
+	}
+
 	pool.goes.Go(pool.housekeeping)
 	pool.goes.Go(pool.fetchBlocklistLoop)
+	if options.BlocklistFilePath != "" {
+		pool.goes.Go(pool.watchBlocklistFileLoop)
+	}
+	if options.BlocklistRegistry != nil {
+		pool.goes.Go(pool.readBlocklistRegistryLoop)
+	}
 	return pool
 }
 
+// loadJournal reloads txs from journal, and txs are re-validated as they are added.
+func (p *TxPool) loadJournal() {
+	entries, err := p.journal.Load()
+	if err != nil && !os.IsNotExist(err) {
+		log.Warn("tx journal load failed", "error", err, "path", p.journal.path)
+	}
+
+	var loaded int
+	for _, entry := range entries {
+		if err := p.add(entry.Tx, false, entry.Local); err != nil {
+			log.Debug("journaled tx rejected", "id", entry.Tx.ID(), "err", err)
+			continue
+		}
+		// keep the original time added, so that the lifetime is not renewed
+		if txObj := p.all.GetByID(entry.Tx.ID()); txObj != nil {
+			txObj.timeAdded = int64(entry.TimeAdded)
+			loaded++
+		}
+	}
+	if len(entries) > 0 {
+		log.Info("txs loaded from journal", "total", len(entries), "loaded", loaded)
+	}
+}
+
+func (p *TxPool) saveJournal() {
+	n, err := p.journal.Save(p.all.ToTxObjects())
+	if err != nil {
+		log.Warn("tx journal save failed", "error", err, "path", p.journal.path)
+		return
+	}
+	log.Debug("tx journal saved", "len", n)
+}
+
+func (p *TxPool) journalLoop() {
+	interval := p.options.JournalInterval
+	if interval <= 0 {
+		interval = defaultJournalInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.saveJournal()
+		}
+	}
+}
+
 func (p *TxPool) housekeeping() {
 	log.Debug("enter housekeeping")
 	defer log.Debug("leave housekeeping")
@@ -103,7 +193,7 @@ func (p *TxPool) housekeeping() {
 				headSummary = newHeadSummary
 				headBlockChanged = true
 			}
-			if !isChainSynced(uint64(time.Now().Unix()), headSummary.Header.Timestamp()) {
+			if !isChainSynced(uint64(time.Now().Unix()), headSummary.Header.Timestamp(), p.options.BlockInterval) {
 				// skip washing txs if not synced
 				continue
 			}
@@ -112,11 +202,14 @@ func (p *TxPool) housekeeping() {
 			// 1. head block changed
 			// 2. pool size exceeds limit
 			// 3. new tx added while pool size is small
+			// 4. txs replaced
 			if headBlockChanged ||
 				poolLen > p.options.Limit ||
-				(poolLen < 200 && atomic.LoadUint32(&p.addedAfterWash) > 0) {
+				(poolLen < 200 && atomic.LoadUint32(&p.addedAfterWash) > 0) ||
+				atomic.LoadUint32(&p.replacedAfterWash) > 0 {
 
 				atomic.StoreUint32(&p.addedAfterWash, 0)
+				atomic.StoreUint32(&p.replacedAfterWash, 0)
 
 				startTime := mclock.Now()
 				executables, removed, err := p.wash(headSummary)
@@ -131,7 +224,10 @@ func (p *TxPool) housekeeping() {
 					ctx = append(ctx, "err", err)
 				} else {
 					p.executables.Store(executables)
+					metricExecutables.Set(float64(len(executables)))
 				}
+				metricPoolSize.Set(float64(p.all.Len()))
+				metricWashedOut.Add(float64(removed))
 
 				log.Debug("wash done", ctx...)
 			}
@@ -146,12 +242,12 @@ func (p *TxPool) fetchBlocklistLoop() {
 	)
 
 	if path != "" {
-		if err := p.blocklist.Load(path); err != nil {
+		if err := p.blocklists.url.Load(path); err != nil {
 			if !os.IsNotExist(err) {
 				log.Warn("blocklist load failed", "error", err, "path", path)
 			}
 		} else {
-			log.Debug("blocklist loaded", "len", p.blocklist.Len())
+			log.Debug("blocklist loaded", "len", p.blocklists.url.Len())
 		}
 	}
 	if url == "" {
@@ -160,15 +256,15 @@ func (p *TxPool) fetchBlocklistLoop() {
 
 	var eTag string
 	fetch := func() {
-		if err := p.blocklist.Fetch(p.ctx, url, &eTag); err != nil {
+		if err := p.blocklists.url.Fetch(p.ctx, url, &eTag); err != nil {
 			if err == context.Canceled {
 				return
 			}
 			log.Warn("blocklist fetch failed", "error", err, "url", url)
 		} else {
-			log.Debug("blocklist fetched", "len", p.blocklist.Len())
+			log.Debug("blocklist fetched", "len", p.blocklists.url.Len())
 			if path != "" {
-				if err := p.blocklist.Save(path); err != nil {
+				if err := p.blocklists.url.Save(path); err != nil {
 					log.Warn("blocklist save failed", "error", err, "path", path)
 				} else {
 					log.Debug("blocklist saved")
@@ -196,6 +292,9 @@ func (p *TxPool) Close() {
 	p.cancel()
 	p.scope.Close()
 	p.goes.Wait()
+	if p.journal != nil {
+		p.saveJournal()
+	}
 	log.Debug("closed")
 }
 
@@ -211,7 +310,7 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, localSubmi
 	}
 
 	origin, _ := newTx.Origin()
-	if ablock.IsOriginBlocked(origin) || p.blocklist.Contains(origin) {
+	if p.blocklists.IsBlocked(origin) {
 		// tx origin blocked
 		return nil
 	}
@@ -232,10 +331,21 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, localSubmi
 
 	txObj, err := resolveTx(newTx, localSubmitted)
 	if err != nil {
+		// the tx fails intrinsic validation, blame the origin if it's recovered
+		if _, e := newTx.Origin(); e == nil && p.rateLimiter != nil {
+			delegator, _ := newTx.Delegator()
+			p.rateLimiter.Failed(origin, delegator, headSummary.Header.Number())
+		}
 		return badTxError{err.Error()}
 	}
 
-	if isChainSynced(uint64(time.Now().Unix()), headSummary.Header.Timestamp()) {
+	if p.rateLimiter != nil {
+		if err := p.rateLimiter.Check(txObj.Origin(), txObj.resolved.Delegator, headSummary.Header.Number()); err != nil {
+			return txRejectedError{err.Error()}
+		}
+	}
+
+	if isChainSynced(uint64(time.Now().Unix()), headSummary.Header.Timestamp(), p.options.BlockInterval) {
 		if !localSubmitted {
 			// reject when pool size exceeds 120% of limit
 			if p.all.Len() >= p.options.Limit*12/10 {
@@ -244,7 +354,7 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, localSubmi
 		}
 
 		state := p.stater.NewState(headSummary.Header.StateRoot(), headSummary.Header.Number(), headSummary.Conflicts, headSummary.SteadyNum)
-		executable, err := txObj.Executable(p.repo.NewChain(headSummary.Header.ID()), state, headSummary.Header)
+		executable, err := txObj.Executable(p.repo.NewChain(headSummary.Header.ID()), state, headSummary.Header, p.options.BlockInterval)
 		if err != nil {
 			return txRejectedError{err.Error()}
 		}
@@ -253,9 +363,11 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, localSubmi
 			return txRejectedError{"tx is not executable"}
 		}
 
-		if err := p.all.Add(txObj, p.options.LimitPerAccount); err != nil {
+		replaced, err := p.all.Add(txObj, p.options.LimitPerAccount)
+		if err != nil {
 			return txRejectedError{err.Error()}
 		}
+		p.evictReplaced(txObj, replaced)
 
 		txObj.executable = executable
 		p.goes.Go(func() {
@@ -269,19 +381,55 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool, localSubmi
 			return txRejectedError{"pool is full"}
 		}
 
-		if err := p.all.Add(txObj, p.options.LimitPerAccount); err != nil {
+		replaced, err := p.all.Add(txObj, p.options.LimitPerAccount)
+		if err != nil {
 			return txRejectedError{err.Error()}
 		}
+		p.evictReplaced(txObj, replaced)
 		log.Debug("tx added", "id", newTx.ID())
 		p.goes.Go(func() {
 			p.txFeed.Send(&TxEvent{newTx, nil})
 		})
 	}
+	if p.rateLimiter != nil {
+		// only accepted txs are counted, so that an origin is never charged for rejections of the pool
+		p.rateLimiter.Accepted(txObj.Origin(), txObj.resolved.Delegator, headSummary.Header.Number())
+	}
 	atomic.AddUint32(&p.addedAfterWash, 1)
 	return nil
 }
 
+// evictReplaced evicts txs depending on the replaced ones, since their dependencies
+// will never be settled in this pool. The executables will be refreshed on next wash.
+func (p *TxPool) evictReplaced(newObj *txObject, replaced []*txObject) {
+	if len(replaced) == 0 {
+		return
+	}
+	evicted := make(map[ablock.Bytes32]bool)
+	for _, obj := range replaced {
+		evicted[obj.ID()] = true
+		log.Debug("tx replaced", "id", obj.ID(), "by", newObj.ID())
+	}
+
+	all := p.all.ToTxObjects()
+	for found := true; found; {
+		found = false
+		for _, obj := range all {
+			if dep := obj.DependsOn(); dep != nil && evicted[*dep] && !evicted[obj.ID()] {
+				if p.all.RemoveByHash(obj.Hash()) {
+					log.Debug("tx evicted due to dep replaced", "id", obj.ID())
+				}
+				evicted[obj.ID()] = true
+				found = true
+			}
+		}
+	}
+	atomic.StoreUint32(&p.replacedAfterWash, 1)
+}
+
 // Add add new tx into pool.
+// Pooled txs from the same origin with the same nonce and block ref are replaced, if the new
+// tx has identical clauses or no clause (to cancel), and a strictly higher gas price coef.
 // It's not assumed as an error if the tx to be added is already in the pool,
 func (p *TxPool) Add(newTx *tx.Transaction) error {
 	return p.add(newTx, false, false)
@@ -292,6 +440,19 @@ func (p *TxPool) AddLocal(newTx *tx.Transaction) error {
 	return p.add(newTx, false, true)
 }
 
+// RateLimitState returns the rate limiting state of the address, or nil if rate limiting is disabled.
+func (p *TxPool) RateLimitState(addr ablock.Address) []*RateLimitState {
+	if p.rateLimiter == nil {
+		return nil
+	}
+	return p.rateLimiter.State(addr, p.repo.BestBlockSummary().Header.Number())
+}
+
+// BlockedBy returns the blocklist source which blocks the address, or empty string if not blocked.
+func (p *TxPool) BlockedBy(addr ablock.Address) string {
+	return p.blocklists.BlockedBy(addr)
+}
+
 // Get get pooled tx by id.
 func (p *TxPool) Get(id ablock.Bytes32) *tx.Transaction {
 	if txObj := p.all.GetByID(id); txObj != nil {
@@ -316,8 +477,17 @@ func (p *TxPool) Remove(txHash ablock.Bytes32, txID ablock.Bytes32) bool {
 
 // Executables returns executable txs.
 func (p *TxPool) Executables() tx.Transactions {
+	if sorted := p.ExecutableTxs(); sorted != nil {
+		return toTxs(sorted)
+	}
+	return nil
+}
+
+// ExecutableTxs returns executable txs along with the info to order them.
+// The returned slice is shared, and should not be modified.
+func (p *TxPool) ExecutableTxs() []*ExecutableTx {
 	if sorted := p.executables.Load(); sorted != nil {
-		return sorted.(tx.Transactions)
+		return sorted.([]*ExecutableTx)
 	}
 	return nil
 }
@@ -327,7 +497,7 @@ func (p *TxPool) Fill(txs tx.Transactions, localSubmitted bool) {
 	txObjs := make([]*txObject, 0, len(txs))
 	for _, tx := range txs {
 		origin, _ := tx.Origin()
-		if ablock.IsOriginBlocked(origin) || p.blocklist.Contains(origin) {
+		if p.blocklists.IsBlocked(origin) {
 			continue
 		}
 		// here we ignore errors
@@ -345,7 +515,7 @@ func (p *TxPool) Dump() tx.Transactions {
 
 // wash to evict txs that are over limit, out of lifetime, out of energy, settled, expired or dep broken.
 // this method should only be called in housekeeping go routine
-func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transactions, removed int, err error) {
+func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables []*ExecutableTx, removed int, err error) {
 	all := p.all.ToTxObjects()
 	var toRemove []*txObject
 	defer func() {
@@ -383,7 +553,7 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 		now                 = time.Now().UnixNano()
 	)
 	for _, txObj := range all {
-		if ablock.IsOriginBlocked(txObj.Origin()) || p.blocklist.Contains(txObj.Origin()) {
+		if p.blocklists.IsBlocked(txObj.Origin()) {
 			toRemove = append(toRemove, txObj)
 			log.Debug("tx washed out", "id", txObj.ID(), "err", "blocked")
 			continue
@@ -395,9 +565,18 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 			log.Debug("tx washed out", "id", txObj.ID(), "err", "out of lifetime")
 			continue
 		}
+		if p.rateLimiter != nil && p.rateLimiter.IsBanned(txObj.Origin(), txObj.resolved.Delegator, headSummary.Header.Number()) {
+			toRemove = append(toRemove, txObj)
+			log.Debug("tx washed out", "id", txObj.ID(), "err", "banned")
+			continue
+		}
 		// settled, out of energy or dep broken
-		executable, err := txObj.Executable(chain, newState(), headSummary.Header)
+		executable, err := txObj.Executable(chain, newState(), headSummary.Header, p.options.BlockInterval)
 		if err != nil {
+			// only reverted ones are failures, txs expired, out of energy or with broken dependency are not
+			if p.rateLimiter != nil && err == errRevertedTx {
+				p.rateLimiter.Failed(txObj.Origin(), txObj.resolved.Delegator, headSummary.Header.Number())
+			}
 			toRemove = append(toRemove, txObj)
 			log.Debug("tx washed out", "id", txObj.ID(), "err", err)
 			continue
@@ -452,11 +631,16 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 	// Sort will be faster (part of it already sorted).
 	sortTxObjsByOverallGasPriceDesc(executableObjs)
 
-	executables = make(tx.Transactions, 0, len(executableObjs))
+	executables = make([]*ExecutableTx, 0, len(executableObjs))
 	var toBroadcast tx.Transactions
 
 	for _, obj := range executableObjs {
-		executables = append(executables, obj.Transaction)
+		executables = append(executables, &ExecutableTx{
+			Transaction:     obj.Transaction,
+			Origin:          obj.Origin(),
+			TimeAdded:       obj.timeAdded,
+			OverallGasPrice: obj.overallGasPrice,
+		})
 		if !obj.executable || obj.localSubmitted {
 			obj.executable = true
 			toBroadcast = append(toBroadcast, obj.Transaction)
@@ -472,10 +656,10 @@ func (p *TxPool) wash(headSummary *chain.BlockSummary) (executables tx.Transacti
 	return executables, 0, nil
 }
 
-func isChainSynced(nowTimestamp, blockTimestamp uint64) bool {
+func isChainSynced(nowTimestamp, blockTimestamp, blockInterval uint64) bool {
 	timeDiff := nowTimestamp - blockTimestamp
 	if blockTimestamp > nowTimestamp {
 		timeDiff = blockTimestamp - nowTimestamp
 	}
-	return timeDiff < ablock.BlockInterval*6
+	return timeDiff < blockInterval*6
 }
@@ -9,6 +9,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -93,7 +94,7 @@ func TestWashTxs(t *testing.T) {
 
 	txs, _, err = pool.wash(pool.repo.BestBlockSummary())
 	assert.Nil(t, err)
-	assert.Equal(t, Tx.Transactions{tx1}, txs)
+	assert.Equal(t, Tx.Transactions{tx1}, toTxs(txs))
 
 	st := pool.stater.NewState(pool.repo.GenesisBlock().Header().StateRoot(), 0, 0, 0)
 	stage, _ := st.Stage(1, 0)
@@ -109,15 +110,17 @@ func TestWashTxs(t *testing.T) {
 
 	txs, _, err = pool.wash(pool.repo.BestBlockSummary())
 	assert.Nil(t, err)
-	assert.Equal(t, Tx.Transactions{tx1}, txs)
+	assert.Equal(t, Tx.Transactions{tx1}, toTxs(txs))
 
 	tx2 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[1])
 	txObj2, _ := resolveTx(tx2, false)
-	assert.Nil(t, pool.all.Add(txObj2, LIMIT_PER_ACCOUNT)) // this tx will participate in the wash out.
+	_, err = pool.all.Add(txObj2, LIMIT_PER_ACCOUNT) // this tx will participate in the wash out.
+	assert.Nil(t, err)
 
 	tx3 := newTx(pool.repo.ChainTag(), nil, 21000, tx.BlockRef{}, 100, nil, tx.Features(0), genesis.DevAccounts()[2])
 	txObj3, _ := resolveTx(tx3, false)
-	assert.Nil(t, pool.all.Add(txObj3, LIMIT_PER_ACCOUNT)) // this tx will participate in the wash out.
+	_, err = pool.all.Add(txObj3, LIMIT_PER_ACCOUNT) // this tx will participate in the wash out.
+	assert.Nil(t, err)
 
 	txs, removedCount, err := pool.wash(pool.repo.BestBlockSummary())
 	assert.Nil(t, err)
@@ -213,3 +216,117 @@ func TestBeforeVIP191Add(t *testing.T) {
 
 	assert.Equal(t, "tx rejected: unsupported features", err.Error())
 }
+
+func TestReplace(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT)
+	defer pool.Close()
+
+	acc := genesis.DevAccounts()[0]
+	to := genesis.DevAccounts()[1].Address
+	build := func(nonce uint64, coef uint8, clauses []*tx.Clause, dependsOn *ablock.Bytes32) *tx.Transaction {
+		builder := new(tx.Builder).ChainTag(pool.repo.ChainTag())
+		for _, c := range clauses {
+			builder.Clause(c)
+		}
+		trx := builder.BlockRef(tx.BlockRef{}).
+			Expiration(100).
+			GasPriceCoef(coef).
+			Gas(21000 * uint64(len(clauses)+1)).
+			DependsOn(dependsOn).
+			Nonce(nonce).
+			Build()
+		return signTx(trx, acc)
+	}
+	clauses := []*tx.Clause{tx.NewClause(&to)}
+	otherClauses := []*tx.Clause{tx.NewClause(&to).WithData([]byte{1})}
+
+	tx1 := build(1, 0, clauses, nil)
+	assert.Nil(t, pool.Add(tx1))
+
+	// not higher gas price coef
+	err := pool.Add(build(1, 0, clauses, &ablock.Bytes32{}))
+	assert.Equal(t, "tx rejected: replacement tx underpriced", err.Error())
+
+	// different nonce or clauses, not a replacement
+	tx2 := build(2, 0, clauses, nil)
+	assert.Nil(t, pool.Add(tx2))
+	tx3 := build(1, 0, otherClauses, nil)
+	assert.Nil(t, pool.Add(tx3))
+	assert.Equal(t, 3, pool.all.Len())
+
+	// replace tx1
+	tx1r := build(1, 10, clauses, nil)
+	assert.Nil(t, pool.Add(tx1r))
+	assert.Nil(t, pool.Get(tx1.ID()))
+	assert.Equal(t, tx1r, pool.Get(tx1r.ID()))
+	assert.Equal(t, 3, pool.all.Len())
+
+	// cancel all txs in slot
+	err = pool.Add(build(1, 10, nil, nil))
+	assert.Equal(t, "tx rejected: replacement tx underpriced", err.Error(), "should be higher than all")
+	cancel := build(1, 20, nil, nil)
+	assert.Nil(t, pool.Add(cancel))
+	assert.Nil(t, pool.Get(tx1r.ID()))
+	assert.Nil(t, pool.Get(tx3.ID()))
+	assert.NotNil(t, pool.Get(tx2.ID()))
+	assert.Equal(t, 2, pool.all.Len())
+
+	// replaced or cancelled txs come back, e.g. re-gossiped by peers
+	for _, trx := range []*tx.Transaction{tx1, tx1r, tx3} {
+		err = pool.Add(trx)
+		assert.Equal(t, "tx rejected: replacement tx underpriced", err.Error())
+	}
+	assert.Equal(t, cancel, pool.Get(cancel.ID()))
+	assert.Equal(t, 2, pool.all.Len())
+
+	// priced higher than the cancel tx
+	tx1rr := build(1, 30, clauses, nil)
+	assert.Nil(t, pool.Add(tx1rr))
+	assert.Nil(t, pool.Get(cancel.ID()))
+	assert.Equal(t, 2, pool.all.Len())
+}
+
+func TestReplaceWithDependents(t *testing.T) {
+	pool := newPool(LIMIT, LIMIT)
+	defer pool.Close()
+
+	acc := genesis.DevAccounts()[0]
+	build := func(nonce uint64, coef uint8, dependsOn *ablock.Bytes32) *tx.Transaction {
+		trx := new(tx.Builder).ChainTag(pool.repo.ChainTag()).
+			Clause(tx.NewClause(&genesis.DevAccounts()[1].Address)).
+			Expiration(100).
+			GasPriceCoef(coef).
+			Gas(42000).
+			DependsOn(dependsOn).
+			Nonce(nonce).
+			Build()
+		return signTx(trx, acc)
+	}
+
+	// tx1 <- tx2 <- tx3, tx4
+	tx1 := build(1, 0, nil)
+	id1 := tx1.ID()
+	tx2 := build(2, 0, &id1)
+	id2 := tx2.ID()
+	tx3 := build(3, 0, &id2)
+	tx4 := build(4, 0, nil)
+	for _, trx := range []*tx.Transaction{tx1, tx2, tx3, tx4} {
+		assert.Nil(t, pool.Add(trx))
+	}
+
+	// replace tx2 with the same dep, tx3 is evicted
+	tx2r := build(2, 10, &id1)
+	assert.Nil(t, pool.Add(tx2r))
+	assert.Nil(t, pool.Get(tx2.ID()))
+	assert.Nil(t, pool.Get(tx3.ID()))
+	assert.Equal(t, tx2r, pool.Get(tx2r.ID()))
+	assert.Equal(t, 3, pool.all.Len())
+
+	// cancel tx1, the whole dependency chain is evicted
+	assert.Nil(t, pool.Add(build(1, 10, nil)))
+	assert.Nil(t, pool.Get(tx1.ID()))
+	assert.Nil(t, pool.Get(tx2r.ID()))
+	assert.NotNil(t, pool.Get(tx4.ID()))
+	assert.Equal(t, 2, pool.all.Len())
+	assert.Equal(t, uint32(1), atomic.LoadUint32(&pool.replacedAfterWash))
+}